@@ -1,19 +1,30 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"log/slog"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"backend/internal/botdetect"
 	"backend/internal/config"
 	"backend/internal/db"
 	"backend/internal/domain"
 	"backend/internal/handler"
 	"backend/internal/logger"
+	"backend/internal/mail"
 	"backend/internal/middleware"
 	"backend/internal/repository"
 	"backend/internal/router"
+	"backend/internal/search"
 	"backend/internal/service"
 	"backend/internal/session"
+	"backend/internal/spam"
+	"backend/internal/traffic"
 	"backend/internal/worker"
 	"backend/internal/ws"
 
@@ -69,6 +80,23 @@ func main() {
 		&domain.SystemSetting{},
 		&domain.AuditLog{},
 		&domain.SystemLog{},
+		&domain.Comment{},
+		&domain.CommentReaction{},
+		&domain.CommentMention{},
+		&domain.Notification{},
+		&domain.NotificationPreference{},
+		&domain.NotificationMute{},
+		&domain.ContentReport{},
+		&domain.CommentRevision{},
+		&domain.UserBlock{},
+		&domain.ReviewVote{},
+		&domain.VisitorSalt{},
+		&domain.AnalyticsRollup{},
+		&domain.ArticleEngagement{},
+		&domain.RollupState{},
+		&domain.SpamToken{},
+		&domain.SavedSearch{},
+		&domain.SavedSearchMatch{},
 	)
 	if err != nil {
 		logger.Get().Error("AutoMigration failed", "error", err)
@@ -84,11 +112,38 @@ func main() {
 
 	mediaRepo := repository.NewMediaRepository(db.DB)
 
+	// Settings
+	settingRepo := repository.NewSettingRepository(db.DB)
+	settingService := service.NewSettingService(settingRepo, auditService)
+
 	articleRepo := repository.NewArticleRepository(db.DB)
 	categoryRepo := repository.NewCategoryRepository(db.DB)
-	articleService := service.NewArticleService(articleRepo, mediaRepo, auditService, seoService, wsHub)
+
+	// Search backend
+	var searchIndex domain.SearchIndex
+	if cfg.SearchBackend == "embedded" {
+		opts := search.DefaultOptions()
+		if settings, err := settingService.GetSettings(); err == nil {
+			opts.Synonyms = search.ParseSynonyms(settings["search_synonyms"])
+		}
+		searchIndex, err = search.NewEmbeddedIndex(cfg.SearchIndexDir, articleRepo, opts)
+		if err != nil {
+			logger.Get().Error("Failed to open embedded search index", "dir", cfg.SearchIndexDir, "error", err)
+			return
+		}
+	} else {
+		searchIndex = repository.NewPostgresSearchIndex(db.DB)
+	}
+	log.Info("Search backend initialized", "backend", searchIndex.Name())
+	searchReindexWorker := worker.NewSearchReindexWorker(articleRepo, searchIndex, wsHub)
+	searchHandler := handler.NewSearchHandler(searchReindexWorker)
+
+	commentPolicy := service.NewCommentPolicyResolver(categoryRepo, settingService)
+	ratingRepo := repository.NewRatingRepository(db.DB)
+	topReviews := service.NewTopReviewsProvider(ratingRepo, settingService)
+	reportRepo := repository.NewReportRepository(db.DB)
+	articleService := service.NewArticleService(articleRepo, mediaRepo, auditService, seoService, searchIndex, wsHub, commentPolicy, topReviews, reportRepo)
 	categoryService := service.NewCategoryService(categoryRepo, auditService)
-	articleHandler := handler.NewArticleHandler(articleService, respCache, wsHub)
 	categoryHandler := handler.NewCategoryHandler(categoryService, respCache, wsHub)
 
 	tagRepo := repository.NewTagRepository(db.DB)
@@ -125,22 +180,71 @@ func main() {
 
 	// Comments
 	commentRepo := repository.NewCommentRepository(db.DB)
-	commentService := service.NewCommentService(commentRepo, articleRepo)
-	commentHandler := handler.NewCommentHandler(commentService, wsHub)
+	spamBayes, err := spam.NewBayesChecker(repository.NewSpamTokenRepository(db.DB))
+	if err != nil {
+		logger.Get().Error("Failed to load spam classifier", "error", err)
+		return
+	}
+	spamChecker := spam.NewComposite(
+		spam.Weighted{Checker: spam.NewLinkDensityChecker(), Weight: 1},
+		spam.Weighted{Checker: spam.NewBlocklistChecker(settingService), Weight: 1},
+		spam.Weighted{Checker: spam.NewRepeatedContentChecker(commentRepo), Weight: 1},
+		spam.Weighted{Checker: spam.NewNewAccountChecker(userRepo, commentRepo), Weight: 1},
+		spam.Weighted{Checker: spamBayes, Weight: 0.9},
+	)
+	notificationRepo := repository.NewNotificationRepository(db.DB)
+	userBlockRepo := repository.NewUserBlockRepository(db.DB)
+	notificationService := service.NewNotificationService(notificationRepo, userBlockRepo, wsHub)
+	notificationHandler := handler.NewNotificationHandler(notificationService)
+	notificationDigestWorker := worker.NewNotificationDigestWorker(notificationRepo, mail.New(cfg))
+	notificationDigestWorker.Start()
+	userBlockHandler := handler.NewUserBlockHandler(service.NewUserBlockService(userBlockRepo, userRepo))
+	commentService := service.NewCommentService(commentRepo, articleRepo, settingService, auditService, spamChecker, notificationService, commentPolicy, userBlockRepo, reportRepo)
+	guestChallenges := service.NewGuestChallengeService(settingService, cfg.ChallengeSecret)
+	commentHandler := handler.NewCommentHandler(commentService, guestChallenges, wsHub)
+
+	// Reader reports & triage
+	reportService := service.NewReportService(reportRepo, commentService, articleService, articleRepo, settingService, auditService, wsHub)
+	reportHandler := handler.NewReportHandler(reportService, wsHub)
 
 	// Ratings
-	ratingRepo := repository.NewRatingRepository(db.DB)
-	ratingService := service.NewRatingService(ratingRepo, articleRepo)
-	ratingHandler := handler.NewRatingHandler(ratingService)
+	ratingService := service.NewRatingService(ratingRepo, articleRepo, settingService, spamChecker, auditService)
+	ratingHandler := handler.NewRatingHandler(ratingService, wsHub)
+	ratingIntegrityWorker := worker.NewRatingIntegrityWorker(ratingService, auditService, wsHub)
+	ratingIntegrityWorker.Start()
+	ratingIntegrityHandler := handler.NewRatingIntegrityHandler(ratingService, ratingIntegrityWorker, wsHub)
 
 	// View Tracking
 	viewTrackingRepo := repository.NewViewTrackingRepository(db.DB)
-	viewTrackingService := service.NewViewTrackingService(viewTrackingRepo, articleRepo, seoService)
-	viewTrackingHandler := handler.NewViewTrackingHandler(viewTrackingService)
+	viewIngest := worker.NewViewIngestPipeline(viewTrackingRepo, articleRepo, worker.ViewIngestOptions{
+		BufferSize:    cfg.ViewBufferSize,
+		Workers:       cfg.ViewWorkers,
+		BatchSize:     cfg.ViewBatchSize,
+		FlushInterval: time.Duration(cfg.ViewFlushIntervalMs) * time.Millisecond,
+		DropPolicy:    domain.ViewDropPolicy(cfg.ViewDropPolicy),
+	})
+	viewIngest.Start()
+	botClassifier := botdetect.NewClassifier(settingService)
+	visitorSaltRepo := repository.NewVisitorSaltRepository(db.DB)
+	visitorIdentifier := service.NewVisitorIdentifier(visitorSaltRepo, settingService)
+	viewPrivacyWorker := worker.NewViewPrivacyWorker(viewTrackingRepo, visitorSaltRepo, settingService)
+	viewPrivacyWorker.Start()
+	viewSources := traffic.NewResolver(settingService)
+	engagementRepo := repository.NewEngagementRepository(db.DB)
+	engagementBuffer := worker.NewEngagementBuffer(engagementRepo, articleRepo)
+	engagementBuffer.Start()
+	viewTrackingService := service.NewViewTrackingService(viewTrackingRepo, articleRepo, seoService, viewIngest, botClassifier, visitorIdentifier, viewSources, engagementBuffer)
+	viewTrackingHandler := handler.NewViewTrackingHandler(viewTrackingService, viewIngest, botClassifier)
+	articleHandler := handler.NewArticleHandler(articleService, viewTrackingService, respCache, wsHub)
+
+	// Analytics rollups
+	analyticsRollupRepo := repository.NewAnalyticsRollupRepository(db.DB)
+	analyticsRollupWorker := worker.NewAnalyticsRollupWorker(analyticsRollupRepo, viewTrackingRepo, engagementRepo, settingService)
+	analyticsRollupWorker.Start()
+	analyticsService := service.NewAnalyticsService(analyticsRollupRepo, articleRepo, engagementRepo, userRepo)
+	analyticsHandler := handler.NewAnalyticsHandler(analyticsService, analyticsRollupWorker)
 
 	// Settings
-	settingRepo := repository.NewSettingRepository(db.DB)
-	settingService := service.NewSettingService(settingRepo, auditService)
 	settingHandler := handler.NewSettingHandler(settingService)
 
 	// Audit & System Logs
@@ -150,6 +254,13 @@ func main() {
 	logWorker := worker.NewLogWorker(settingService, auditService)
 	logWorker.Start()
 
+	// Saved searches & new-match alerts
+	savedSearchRepo := repository.NewSavedSearchRepository(db.DB)
+	savedSearchService := service.NewSavedSearchService(savedSearchRepo)
+	savedSearchHandler := handler.NewSavedSearchHandler(savedSearchService)
+	savedSearchWorker := worker.NewSavedSearchWorker(savedSearchRepo, articleService, settingService, wsHub)
+	savedSearchWorker.Start()
+
 	appRouter := router.NewRouter(
 		articleHandler,
 		categoryHandler,
@@ -165,6 +276,13 @@ func main() {
 		viewTrackingHandler,
 		settingHandler,
 		auditHandler,
+		searchHandler,
+		savedSearchHandler,
+		notificationHandler,
+		reportHandler,
+		userBlockHandler,
+		ratingIntegrityHandler,
+		analyticsHandler,
 		wsHub,
 		respCache,
 	)
@@ -172,8 +290,36 @@ func main() {
 
 	// 6. Start Server
 	logger.Get().Info("Server starting", "port", cfg.ServerPort)
-	handler := session.SessionManager.LoadAndSave(r)
-	if err := http.ListenAndServe(":"+cfg.ServerPort, handler); err != nil {
-		logger.Get().Error("Failed to start server", "error", err)
+	server := &http.Server{
+		Addr:    ":" + cfg.ServerPort,
+		Handler: session.SessionManager.LoadAndSave(r),
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Get().Error("Failed to start server", "error", err)
+			os.Exit(1)
+		}
+	}()
+
+	// 7. Graceful shutdown: finish in-flight requests, then flush the search index and
+	// buffered views and pings
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
+	<-quit
+	logger.Get().Info("Shutting down server")
+
+	ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+	defer cancel()
+	if err := server.Shutdown(ctx); err != nil {
+		logger.Get().Error("Server shutdown failed", "error", err)
+	}
+	if err := searchIndex.Close(); err != nil {
+		logger.Get().Error("Failed to flush search index", "error", err)
+	}
+	if err := viewIngest.Stop(ctx); err != nil {
+		logger.Get().Error("Failed to flush buffered views", "error", err, "stats", viewIngest.Stats())
+	}
+	if err := engagementBuffer.Stop(ctx); err != nil {
+		logger.Get().Error("Failed to flush engagement sessions", "error", err)
 	}
 }
@@ -0,0 +1,198 @@
+// Package botdetect classifies article views as human, bot or suspicious traffic.
+package botdetect
+
+import (
+	"backend/internal/domain"
+	"log"
+	"net/netip"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+)
+
+// rulesRefresh is how long a loaded ruleset is used before settings are read again,
+// so edits to the rule settings apply without a restart
+const rulesRefresh = time.Minute
+
+// defaultBotPatterns match crawlers, HTTP libraries and headless browsers with honest
+// user agents. They are case-insensitive and extended by view_bot_ua_patterns.
+var defaultBotPatterns = []string{
+	`bot\b`, `bot/`, `crawl`, `spider`, `slurp`, `archiver`, `facebookexternalhit`, `embedly`,
+	`headlesschrome`, `phantomjs`, `puppeteer`, `playwright`, `selenium`, `webdriver`, `lighthouse`,
+	`curl/`, `wget/`, `python-requests`, `python-urllib`, `aiohttp`, `go-http-client`, `java/`,
+	`okhttp`, `apache-httpclient`, `axios/`, `node-fetch`, `undici`, `libwww-perl`, `scrapy`,
+	`pingdom`, `uptimerobot`, `statuscake`, `monitor`,
+}
+
+// Rule is a compiled user agent pattern
+type Rule struct {
+	Pattern string `json:"pattern"`
+	Builtin bool   `json:"builtin"`
+	re      *regexp.Regexp
+}
+
+// Ruleset is the classifier configuration read from system settings:
+//   - view_bot_ua_patterns: extra bot user agent regexes
+//   - view_bot_ua_allow: user agent regexes that are never treated as bots
+//   - view_ip_denylist: IPs / CIDRs classified as bots
+//   - view_ip_watchlist: IPs / CIDRs classified as suspicious
+//   - view_ip_allowlist: IPs / CIDRs always classified as human
+type Ruleset struct {
+	BotPatterns   []Rule         `json:"bot_patterns"`
+	AllowPatterns []Rule         `json:"allow_patterns"`
+	IPDenylist    []netip.Prefix `json:"ip_denylist"`
+	IPWatchlist   []netip.Prefix `json:"ip_watchlist"`
+	IPAllowlist   []netip.Prefix `json:"ip_allowlist"`
+	LoadedAt      time.Time      `json:"loaded_at"`
+}
+
+// Classifier implements domain.ViewClassifier. Rules are checked in order: IP
+// allowlist, IP denylist, allowed user agents, bot user agents, IP watchlist and
+// finally user agents that do not look like a browser.
+type Classifier struct {
+	settingServ domain.SettingService
+
+	mu     sync.RWMutex
+	rules  *Ruleset
+	reload singleflight.Group
+}
+
+func NewClassifier(settingServ domain.SettingService) *Classifier {
+	return &Classifier{settingServ: settingServ}
+}
+
+func (c *Classifier) Classify(ipAddress, userAgent string) domain.ViewClassification {
+	rules := c.Rules()
+	ua := strings.ToLower(strings.TrimSpace(userAgent))
+	addr, ipErr := netip.ParseAddr(ipAddress)
+
+	if ipErr == nil && inPrefixes(rules.IPAllowlist, addr) {
+		return domain.ViewClassification{Class: domain.ViewHuman, Reason: "ip_allowlist"}
+	}
+	if ipErr == nil && inPrefixes(rules.IPDenylist, addr) {
+		return domain.ViewClassification{Class: domain.ViewBot, Reason: "ip_denylist"}
+	}
+	if ua == "" {
+		return domain.ViewClassification{Class: domain.ViewSuspicious, Reason: "empty_user_agent"}
+	}
+	for _, r := range rules.AllowPatterns {
+		if r.re.MatchString(ua) {
+			return domain.ViewClassification{Class: domain.ViewHuman, Reason: "ua_allow:" + r.Pattern}
+		}
+	}
+	for _, r := range rules.BotPatterns {
+		if r.re.MatchString(ua) {
+			return domain.ViewClassification{Class: domain.ViewBot, Reason: "ua:" + r.Pattern}
+		}
+	}
+	if ipErr == nil && inPrefixes(rules.IPWatchlist, addr) {
+		return domain.ViewClassification{Class: domain.ViewSuspicious, Reason: "ip_watchlist"}
+	}
+	if !strings.HasPrefix(ua, "mozilla/") && !strings.HasPrefix(ua, "opera/") {
+		return domain.ViewClassification{Class: domain.ViewSuspicious, Reason: "non_browser"}
+	}
+	return domain.ViewClassification{Class: domain.ViewHuman}
+}
+
+// Rules returns the active ruleset, reloading it from settings when it is stale
+func (c *Classifier) Rules() *Ruleset {
+	c.mu.RLock()
+	rules := c.rules
+	c.mu.RUnlock()
+	if rules != nil && time.Since(rules.LoadedAt) < rulesRefresh {
+		return rules
+	}
+	return c.Reload()
+}
+
+// Reload reads the ruleset from settings now; concurrent callers share one load.
+// Invalid patterns and addresses are logged and skipped. If settings cannot be read
+// the previous ruleset stays in use, or the built-in rules before the first load.
+func (c *Classifier) Reload() *Ruleset {
+	rules, _, _ := c.reload.Do("rules", func() (interface{}, error) {
+		return c.load(), nil
+	})
+	return rules.(*Ruleset)
+}
+
+func (c *Classifier) load() *Ruleset {
+	settings, err := c.settingServ.GetSettings()
+	if err != nil {
+		log.Printf("botdetect: failed to load settings: %v", err)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		// Retried after rulesRefresh rather than on every request
+		kept := &Ruleset{BotPatterns: builtinRules()}
+		if c.rules != nil {
+			copied := *c.rules
+			kept = &copied
+		}
+		kept.LoadedAt = time.Now()
+		c.rules = kept
+		return kept
+	}
+
+	rules := &Ruleset{LoadedAt: time.Now(), BotPatterns: builtinRules()}
+	rules.BotPatterns = append(rules.BotPatterns, compileRules(domain.SettingStrings(settings, "view_bot_ua_patterns"))...)
+	rules.AllowPatterns = compileRules(domain.SettingStrings(settings, "view_bot_ua_allow"))
+	rules.IPDenylist = parsePrefixes(domain.SettingStrings(settings, "view_ip_denylist"))
+	rules.IPWatchlist = parsePrefixes(domain.SettingStrings(settings, "view_ip_watchlist"))
+	rules.IPAllowlist = parsePrefixes(domain.SettingStrings(settings, "view_ip_allowlist"))
+
+	c.mu.Lock()
+	c.rules = rules
+	c.mu.Unlock()
+	return rules
+}
+
+func builtinRules() []Rule {
+	rules := make([]Rule, 0, len(defaultBotPatterns))
+	for _, p := range defaultBotPatterns {
+		rules = append(rules, Rule{Pattern: p, Builtin: true, re: regexp.MustCompile(p)})
+	}
+	return rules
+}
+
+func compileRules(patterns []string) []Rule {
+	var rules []Rule
+	for _, p := range patterns {
+		re, err := regexp.Compile("(?i)" + p)
+		if err != nil {
+			log.Printf("botdetect: skipping invalid pattern %q: %v", p, err)
+			continue
+		}
+		rules = append(rules, Rule{Pattern: p, re: re})
+	}
+	return rules
+}
+
+// parsePrefixes accepts single addresses as well as CIDR ranges
+func parsePrefixes(entries []string) []netip.Prefix {
+	var prefixes []netip.Prefix
+	for _, e := range entries {
+		if strings.Contains(e, "/") {
+			if p, err := netip.ParsePrefix(e); err == nil {
+				prefixes = append(prefixes, p.Masked())
+				continue
+			}
+		} else if a, err := netip.ParseAddr(e); err == nil {
+			prefixes = append(prefixes, netip.PrefixFrom(a, a.BitLen()))
+			continue
+		}
+		log.Printf("botdetect: skipping invalid address %q", e)
+	}
+	return prefixes
+}
+
+func inPrefixes(prefixes []netip.Prefix, addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, p := range prefixes {
+		if p.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
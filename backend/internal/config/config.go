@@ -3,6 +3,7 @@ package config
 import (
 	"log"
 	"os"
+	"strconv"
 
 	"github.com/joho/godotenv"
 )
@@ -16,6 +17,26 @@ type Config struct {
 	DBSSLMode  string
 	ServerPort string
 	AppEnv     string // dev, prod, staging
+
+	SearchBackend  string // postgres, embedded
+	SearchIndexDir string
+
+	// Outgoing mail; without SMTPHost emails are only logged
+	SMTPHost     string
+	SMTPPort     string
+	SMTPUser     string
+	SMTPPassword string
+	MailFrom     string
+
+	// ChallengeSecret signs guest comment challenges; random per process when empty
+	ChallengeSecret string
+
+	// View ingestion pipeline; ViewDropPolicy is drop_newest, drop_oldest or block
+	ViewBufferSize      int
+	ViewWorkers         int
+	ViewBatchSize       int
+	ViewFlushIntervalMs int
+	ViewDropPolicy      string
 }
 
 func LoadConfig() *Config {
@@ -42,6 +63,23 @@ func LoadConfig() *Config {
 		DBSSLMode:  getEnv("DB_SSLMODE", "disable"),
 		ServerPort: getEnv("SERVER_PORT", "8080"),
 		AppEnv:     getEnv("APP_ENV", "dev"),
+
+		SearchBackend:  getEnv("SEARCH_BACKEND", "postgres"),
+		SearchIndexDir: getEnv("SEARCH_INDEX_DIR", "../../data/search_index"),
+
+		SMTPHost:     getEnv("SMTP_HOST", ""),
+		SMTPPort:     getEnv("SMTP_PORT", "587"),
+		SMTPUser:     getEnv("SMTP_USER", ""),
+		SMTPPassword: getEnv("SMTP_PASSWORD", ""),
+		MailFrom:     getEnv("MAIL_FROM", "no-reply@localhost"),
+
+		ChallengeSecret: getEnv("CHALLENGE_SECRET", ""),
+
+		ViewBufferSize:      getEnvInt("VIEW_BUFFER_SIZE", 10000),
+		ViewWorkers:         getEnvInt("VIEW_WORKERS", 2),
+		ViewBatchSize:       getEnvInt("VIEW_BATCH_SIZE", 500),
+		ViewFlushIntervalMs: getEnvInt("VIEW_FLUSH_INTERVAL_MS", 2000),
+		ViewDropPolicy:      getEnv("VIEW_DROP_POLICY", "drop_newest"),
 	}
 }
 
@@ -51,3 +89,13 @@ func getEnv(key, fallback string) string {
 	}
 	return fallback
 }
+
+func getEnvInt(key string, fallback int) int {
+	if value, ok := os.LookupEnv(key); ok {
+		if n, err := strconv.Atoi(value); err == nil {
+			return n
+		}
+		log.Printf("Invalid %s=%q, using %d", key, value, fallback)
+	}
+	return fallback
+}
@@ -84,6 +84,14 @@ func NewConflict(message string, err error) *AppError {
 	}
 }
 
+// NewTooManyRequests creates a 429 Too Many Requests error
+func NewTooManyRequests(message string) *AppError {
+	return &AppError{
+		Code:    http.StatusTooManyRequests,
+		Message: message,
+	}
+}
+
 // IsUniqueConstraintViolation checks if an error is a PostgreSQL unique constraint violation
 func IsUniqueConstraintViolation(err error) bool {
 	if err == nil {
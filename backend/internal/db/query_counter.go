@@ -0,0 +1,108 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"gorm.io/gorm"
+)
+
+type queryCounterKey struct{}
+
+// QueryCounter counts SQL statements issued through a *gorm.DB returned by Counted.
+// It is used by the repository query-budget test to catch N+1 regressions.
+type QueryCounter struct {
+	count   atomic.Int64
+	mu      sync.Mutex
+	queries []string
+}
+
+// queryCounterPlugin registers callbacks that report to the counter stored in the statement context
+type queryCounterPlugin struct{}
+
+func (queryCounterPlugin) Name() string {
+	return "query_counter"
+}
+
+func (queryCounterPlugin) Initialize(db *gorm.DB) error {
+	record := func(tx *gorm.DB) {
+		if tx.Statement == nil || tx.Statement.Context == nil {
+			return
+		}
+		if counter, ok := tx.Statement.Context.Value(queryCounterKey{}).(*QueryCounter); ok {
+			counter.record(tx.Statement.SQL.String())
+		}
+	}
+
+	cb := db.Callback()
+	if err := cb.Create().After("gorm:create").Register("query_counter:create", record); err != nil {
+		return err
+	}
+	if err := cb.Query().After("gorm:query").Register("query_counter:query", record); err != nil {
+		return err
+	}
+	if err := cb.Update().After("gorm:update").Register("query_counter:update", record); err != nil {
+		return err
+	}
+	if err := cb.Delete().After("gorm:delete").Register("query_counter:delete", record); err != nil {
+		return err
+	}
+	if err := cb.Row().After("gorm:row").Register("query_counter:row", record); err != nil {
+		return err
+	}
+	return cb.Raw().After("gorm:raw").Register("query_counter:raw", record)
+}
+
+// Counted returns a session of db whose statements are tallied by the returned counter.
+// Repositories built on the returned *gorm.DB are counted; everything else is unaffected.
+func Counted(db *gorm.DB) (*gorm.DB, *QueryCounter, error) {
+	if err := db.Use(queryCounterPlugin{}); err != nil && !errors.Is(err, gorm.ErrRegistered) {
+		return nil, nil, err
+	}
+
+	counter := &QueryCounter{}
+	ctx := context.WithValue(context.Background(), queryCounterKey{}, counter)
+	return db.WithContext(ctx), counter, nil
+}
+
+func (c *QueryCounter) record(sql string) {
+	c.count.Add(1)
+	c.mu.Lock()
+	c.queries = append(c.queries, sql)
+	c.mu.Unlock()
+}
+
+// Count returns the number of statements since the last Reset
+func (c *QueryCounter) Count() int64 {
+	return c.count.Load()
+}
+
+// Queries returns the SQL recorded since the last Reset
+func (c *QueryCounter) Queries() []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]string(nil), c.queries...)
+}
+
+// Reset clears the counter
+func (c *QueryCounter) Reset() {
+	c.count.Store(0)
+	c.mu.Lock()
+	c.queries = nil
+	c.mu.Unlock()
+}
+
+// Measure resets the counter, runs fn and fails if more than max statements were issued
+func (c *QueryCounter) Measure(name string, max int64, fn func() error) (int64, error) {
+	c.Reset()
+	if err := fn(); err != nil {
+		return c.Count(), fmt.Errorf("%s: %w", name, err)
+	}
+	if n := c.Count(); n > max {
+		return n, fmt.Errorf("%s: issued %d queries, budget is %d", name, n, max)
+	}
+	return c.Count(), nil
+}
@@ -0,0 +1,175 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// RollupGranularity is the bucket size of an analytics rollup
+type RollupGranularity string
+
+const (
+	RollupHour RollupGranularity = "HOUR"
+	RollupDay  RollupGranularity = "DAY"
+)
+
+func (g RollupGranularity) IsValid() bool {
+	return g == RollupHour || g == RollupDay
+}
+
+// RollupDimension is what an analytics rollup row is aggregated by
+type RollupDimension string
+
+const (
+	RollupSite     RollupDimension = "SITE"
+	RollupArticle  RollupDimension = "ARTICLE"
+	RollupCategory RollupDimension = "CATEGORY"
+	RollupAuthor   RollupDimension = "AUTHOR"
+)
+
+func (d RollupDimension) IsValid() bool {
+	return d == RollupSite || d == RollupArticle || d == RollupCategory || d == RollupAuthor
+}
+
+// DefaultViewRawRetentionDays applies when view_raw_retention_days is unset; raw
+// article_views older than this are deleted once they are rolled up (0 keeps them)
+const DefaultViewRawRetentionDays = 90
+
+// AnalyticsRollup holds the metrics of one dimension value in one hour or day.
+// Views, uniques and session time only cover human views; comments are the approved
+// ones and ratings the non-voided ones, bucketed by creation time, and reads are
+// bucketed by the time the session started. DimensionID is
+// uuid.Nil for SITE rows.
+type AnalyticsRollup struct {
+	Granularity    RollupGranularity `gorm:"type:varchar(10);primaryKey" json:"granularity"`
+	Bucket         time.Time         `gorm:"type:timestamptz;primaryKey" json:"bucket"`
+	Dimension      RollupDimension   `gorm:"type:varchar(10);primaryKey" json:"dimension"`
+	DimensionID    uuid.UUID         `gorm:"type:uuid;primaryKey" json:"dimension_id"`
+	Views          int64             `gorm:"not null;default:0" json:"views"`
+	BotViews       int64             `gorm:"not null;default:0" json:"bot_views"`
+	Uniques        int64             `gorm:"not null;default:0" json:"uniques"`
+	SessionSeconds int64             `gorm:"not null;default:0" json:"session_seconds"`
+	Comments       int64             `gorm:"not null;default:0" json:"comments"`
+	Ratings        int64             `gorm:"not null;default:0" json:"ratings"`
+	RatingSum      int64             `gorm:"not null;default:0" json:"rating_sum"`
+	// Reads are engagement sessions started in the bucket, Completions those that
+	// reached the end and ActiveSeconds their total active reading time
+	Reads         int64     `gorm:"not null;default:0" json:"reads"`
+	Completions   int64     `gorm:"not null;default:0" json:"completions"`
+	ActiveSeconds int64     `gorm:"not null;default:0" json:"active_seconds"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	AvgSession     float64 `gorm:"-" json:"avg_session"`
+	AvgRating      float64 `gorm:"-" json:"avg_rating"`
+	CompletionRate float64 `gorm:"-" json:"completion_rate"`
+	AvgActive      float64 `gorm:"-" json:"avg_active_seconds"`
+}
+
+// Finish fills the derived averages
+func (r *AnalyticsRollup) Finish() {
+	if r.Views > 0 {
+		r.AvgSession = float64(r.SessionSeconds) / float64(r.Views)
+	}
+	if r.Ratings > 0 {
+		r.AvgRating = float64(r.RatingSum) / float64(r.Ratings)
+	}
+	if r.Reads > 0 {
+		r.CompletionRate = float64(r.Completions) / float64(r.Reads)
+		r.AvgActive = float64(r.ActiveSeconds) / float64(r.Reads)
+	}
+}
+
+// Add accumulates another row's counters; summing Uniques is exact for daily rows
+// of one dimension value, since visitor IDs rotate daily
+func (r *AnalyticsRollup) Add(o AnalyticsRollup) {
+	r.Views += o.Views
+	r.BotViews += o.BotViews
+	r.Uniques += o.Uniques
+	r.SessionSeconds += o.SessionSeconds
+	r.Comments += o.Comments
+	r.Ratings += o.Ratings
+	r.RatingSum += o.RatingSum
+	r.Reads += o.Reads
+	r.Completions += o.Completions
+	r.ActiveSeconds += o.ActiveSeconds
+}
+
+// RollupState records how far the rollup worker has aggregated
+type RollupState struct {
+	Name        string    `gorm:"type:varchar(50);primaryKey" json:"name"`
+	RolledUntil time.Time `json:"rolled_until"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// RollupQuery selects rollup rows; a nil DimensionID covers every value of the
+// dimension. AuthorID limits ARTICLE rankings to one author's articles.
+type RollupQuery struct {
+	Granularity RollupGranularity
+	Dimension   RollupDimension
+	DimensionID *uuid.UUID
+	AuthorID    *uuid.UUID
+	From        time.Time
+	To          time.Time
+}
+
+// RollupTotal is one dimension value's totals over a range, with its display name
+type RollupTotal struct {
+	AnalyticsRollup
+	Name string `json:"name"`
+}
+
+// AuthorBenchmark holds the per-author metrics compared on the author dashboard
+type AuthorBenchmark struct {
+	Views          float64 `json:"views"`
+	Uniques        float64 `json:"uniques"`
+	Comments       float64 `json:"comments"`
+	Ratings        float64 `json:"ratings"`
+	AvgRating      float64 `json:"avg_rating"`
+	CompletionRate float64 `json:"completion_rate"`
+	AvgActive      float64 `json:"avg_active_seconds"`
+}
+
+// AuthorAnalytics is an author's dashboard: totals and a gap-free series of their
+// articles' metrics, their top articles, and how the totals compare with the median
+// author. Uniques are summed per bucket, so a reader returning on another day counts again.
+type AuthorAnalytics struct {
+	AuthorID    uuid.UUID         `json:"author_id"`
+	AuthorName  string            `json:"author_name"`
+	Granularity RollupGranularity `json:"granularity"`
+	From        time.Time         `json:"from"`
+	To          time.Time         `json:"to"`
+	Totals      AnalyticsRollup   `json:"totals"`
+	Series      []AnalyticsRollup `json:"series"`
+	TopArticles []RollupTotal     `json:"top_articles"`
+	Author      AuthorBenchmark   `json:"author"`
+	SiteMedian  AuthorBenchmark   `json:"site_median"`
+	// VsMedian divides each author metric by the median, 0 where the median is 0
+	VsMedian AuthorBenchmark `json:"vs_median"`
+}
+
+type AnalyticsRollupRepository interface {
+	// Rebuild recomputes every bucket of the granularity in [from, to) from the raw tables
+	Rebuild(granularity RollupGranularity, from, to time.Time) error
+	// EarliestEvent is the time of the oldest view, comment or rating, nil without any
+	EarliestEvent() (*time.Time, error)
+	GetState(name string) (*RollupState, error)
+	SaveState(state *RollupState) error
+
+	Series(q RollupQuery) ([]AnalyticsRollup, error)
+	// Top ranks the dimension's values by human views over [q.From, q.To)
+	Top(q RollupQuery, limit int) ([]RollupTotal, error)
+	// AuthorMedians is the median author's totals over [from, to)
+	AuthorMedians(granularity RollupGranularity, from, to time.Time) (*AuthorBenchmark, error)
+}
+
+type AnalyticsService interface {
+	// GetSeries returns the buckets of q with the derived averages filled
+	GetSeries(q RollupQuery) ([]AnalyticsRollup, error)
+	GetTop(q RollupQuery, limit int) ([]RollupTotal, error)
+	// GetArticleEngagement builds the read-depth report of an article for its author,
+	// editors and admins
+	GetArticleEngagement(articleID, viewerID uuid.UUID, roles []string, from, to time.Time) (*ArticleEngagementStats, error)
+	// GetAuthorAnalytics builds an author's dashboard over [from, to)
+	GetAuthorAnalytics(authorID uuid.UUID, granularity RollupGranularity, from, to time.Time, topLimit int) (*AuthorAnalytics, error)
+}
@@ -37,9 +37,12 @@ type Article struct {
 	Status       ArticleStatus `gorm:"type:varchar(30);not null;default:'DRAFT'" json:"status"`
 	IsFeatured   bool          `gorm:"default:false" json:"is_featured"`
 	AllowComment bool          `gorm:"default:true" json:"allow_comment"`
-	PublishedAt  *time.Time    `json:"published_at"`
-	CreatedAt    time.Time     `gorm:"default:now()" json:"created_at"`
-	UpdatedAt    time.Time     `gorm:"default:now()" json:"updated_at"`
+	// CommentPolicy is the article's own comment policy, nil to inherit from its category
+	CommentPolicy *CommentPolicy `gorm:"type:jsonb;serializer:json" json:"comment_policy"`
+	Language      string         `gorm:"type:varchar(10);not null;default:'vi'" json:"language"`
+	PublishedAt   *time.Time     `json:"published_at"`
+	CreatedAt     time.Time      `gorm:"default:now()" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"default:now()" json:"updated_at"`
 
 	// Relations
 	Images       []ArticleImage       `gorm:"foreignKey:ArticleID" json:"images"`
@@ -54,10 +57,15 @@ type Article struct {
 	CommentCount int                  `gorm:"default:0" json:"comment_count"` // Actual persisted count for performance
 	RatingAvg    float64              `gorm:"default:0" json:"rating_avg"`
 	RatingCount  int                  `gorm:"default:0" json:"rating_count"`
-	Complexity   int                  `gorm:"default:0" json:"complexity"` // Content complexity score (0-100)
-	Depth        int                  `gorm:"default:0" json:"depth"`      // Content depth/detail score (0-100)
-	Impact       int                  `gorm:"default:0" json:"impact"`     // Reader impact/engagement score (0-100)
-	ImageURL     string               `gorm:"-" json:"image_url"`          // Alias for primary image URL
+	RatingScore  float64              `gorm:"default:0" json:"rating_score"` // Bayesian-adjusted RatingAvg for ranking
+	Complexity   int                  `gorm:"default:0" json:"complexity"`   // Content complexity score (0-100)
+	Depth        int                  `gorm:"default:0" json:"depth"`        // Content depth/detail score (0-100)
+	Impact       int                  `gorm:"default:0" json:"impact"`       // Reader impact/engagement score (0-100)
+	ImageURL     string               `gorm:"-" json:"image_url"`            // Alias for primary image URL
+	// TopReviews are the most helpful approved reviews, set on article detail
+	TopReviews []ArticleRating `gorm:"-" json:"top_reviews,omitempty"`
+	// EffectiveCommentPolicy is set on article detail so the UI can render the comment box state
+	EffectiveCommentPolicy *EffectiveCommentPolicy `gorm:"-" json:"effective_comment_policy,omitempty"`
 }
 
 func (a *Article) PopulateImageURL() {
@@ -127,7 +135,7 @@ type ArticleStatusLog struct {
 	ArticleID uuid.UUID     `gorm:"type:uuid;not null" json:"article_id"`
 	OldStatus ArticleStatus `gorm:"type:varchar(30)" json:"old_status"`
 	NewStatus ArticleStatus `gorm:"type:varchar(30)" json:"new_status"`
-	ChangedBy uuid.UUID     `gorm:"type:uuid" json:"changed_by"`
+	ChangedBy *uuid.UUID    `gorm:"type:uuid" json:"changed_by"` // nil for automatic changes
 	Note      string        `json:"note"`
 	CreatedAt time.Time     `gorm:"default:now()" json:"created_at"`
 }
@@ -142,6 +150,28 @@ type ArticleRating struct {
 	RelevanceScore int       `gorm:"default:0" json:"relevance_score"`
 	CreatedAt      time.Time `gorm:"default:now()" json:"created_at"`
 	UpdatedAt      time.Time `gorm:"default:now()" json:"updated_at"`
+
+	// Optional written review (see review.go). ReviewStatus is empty without one.
+	Review           string        `gorm:"type:text" json:"review,omitempty"`
+	ReviewStatus     CommentStatus `gorm:"type:varchar(20)" json:"review_status,omitempty"`
+	ReviewUpdatedAt  *time.Time    `json:"review_updated_at,omitempty"`
+	ModerationReason string        `gorm:"type:text" json:"moderation_reason,omitempty"`
+	ModeratedBy      *uuid.UUID    `gorm:"type:uuid" json:"-"`
+	ModeratedAt      *time.Time    `json:"moderated_at,omitempty"`
+	SpamScore        float64       `gorm:"default:0" json:"-"`
+	// Helpfulness, denormalized from review_votes; HelpfulScore is their Wilson score
+	HelpfulCount    int     `gorm:"not null;default:0" json:"helpful_count"`
+	NotHelpfulCount int     `gorm:"not null;default:0" json:"not_helpful_count"`
+	HelpfulScore    float64 `gorm:"not null;default:0" json:"helpful_score"`
+
+	// Voided ratings are kept for the audit trail but excluded from every aggregate and listing
+	VoidedAt   *time.Time `json:"voided_at,omitempty"`
+	VoidedBy   *uuid.UUID `gorm:"type:uuid" json:"voided_by,omitempty"`
+	VoidReason string     `gorm:"type:text" json:"void_reason,omitempty"`
+
+	User *User `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	// MyVote is the viewer's vote on the review, set on listings
+	MyVote *bool `gorm:"-" json:"my_vote,omitempty"`
 }
 
 type SeoMetadata struct {
@@ -160,9 +190,16 @@ func (SeoMetadata) TableName() string {
 
 type ArticleRepository interface {
 	Create(article *Article) error
-	GetAll(offset, limit int, filter map[string]interface{}) ([]Article, int64, error)
+	GetAll(offset, limit int, query ArticleQuery) ([]Article, int64, error)
+	GetAllByCursor(req CursorRequest, query ArticleQuery) (*PaginatedResult[Article], error)
+	// ExistingIDs reports which of ids are still in the articles table
+	ExistingIDs(ids []uuid.UUID) (map[uuid.UUID]bool, error)
+	// GetAfterID pages every article by id for batch jobs, with GetAll's associations
+	GetAfterID(afterID uuid.UUID, limit int) ([]Article, error)
 	GetByID(id uuid.UUID) (*Article, error)
 	GetByIDFull(id uuid.UUID) (*Article, error)
+	// GetByIDs loads listing-weight articles, preserving the order of ids
+	GetByIDs(ids []uuid.UUID) ([]Article, error)
 	GetBySlug(slug string) (*Article, error)
 	Update(article *Article) error
 	Delete(id uuid.UUID) error
@@ -175,6 +212,8 @@ type ArticleRepository interface {
 	GetDiscussed(limit int) ([]Article, error)
 	GetRandom(limit int, excludeIDs []uuid.UUID) ([]Article, error)
 	IncrementViewCount(id uuid.UUID) error
+	// IncrementViewCounts adds each article's count to its view_count in one statement
+	IncrementViewCounts(counts map[uuid.UUID]int) error
 	IncrementCommentCount(id uuid.UUID) error
 	DecrementCommentCount(id uuid.UUID) error
 	// Search method with full-text search and fuzzy matching
@@ -183,12 +222,15 @@ type ArticleRepository interface {
 
 type ArticleService interface {
 	CreateArticle(article *Article) error
-	GetArticles(page, limit int, filter map[string]interface{}) ([]Article, int64, error)
+	GetArticles(page, limit int, query ArticleQuery) ([]Article, int64, error)
+	GetArticlesByCursor(req CursorRequest, query ArticleQuery) (*PaginatedResult[Article], error)
 	GetArticleByID(id uuid.UUID) (*Article, error)
 	GetArticleBySlug(slug string) (*Article, error)
 	UpdateArticle(article *Article) error
 	DeleteArticle(id uuid.UUID) error
 	ChangeStatus(id uuid.UUID, newStatus ArticleStatus, changedBy uuid.UUID, note string) error
+	// HideForReview moves a published article back to REVIEW as a system action; it reports whether the article was hidden
+	HideForReview(id uuid.UUID, note string) (bool, error)
 	CreateMediaFile(media *MediaFile) error
 	DeleteMediaByUrl(url string) error
 	CreateArticleRedirect(articleID uuid.UUID, fromSlug string) error
@@ -203,19 +245,58 @@ type ArticleService interface {
 }
 
 type RatingRepository interface {
-	Upsert(rating *ArticleRating) error
+	// Upsert saves the rating and refreshes the article's rating_avg, rating_count and
+	// rating_score, the last with the given Bayesian prior weight
+	Upsert(rating *ArticleRating, priorWeight float64) error
 	GetByArticleAndUser(articleID, userID string) (*ArticleRating, error)
 	GetStats(articleID string) (float64, int64, error)
+	GetBreakdown(articleID uuid.UUID) (*RatingBreakdown, error)
+	// PriorMean is the site-wide mean overall score, 3 without ratings
+	PriorMean() (float64, error)
+
+	// Reviews. Listings only return approved reviews.
+	ListReviews(articleID uuid.UUID, sort ReviewSort, viewerID *uuid.UUID, offset, limit int) ([]ArticleRating, int64, error)
+	// TopReviews returns the most helpful approved reviews with at least one helpful vote
+	TopReviews(articleID uuid.UUID, limit int) ([]ArticleRating, error)
+	GetReviewByID(id uuid.UUID) (*ArticleRating, error)
+	// Vote stores the user's vote (nil removes it) and refreshes the review's counts
+	Vote(ratingID, userID uuid.UUID, helpful *bool) (*ArticleRating, error)
+	GetReviewQueue(filter ReviewFilter, offset, limit int) ([]ArticleRating, int64, error)
+	GetReviewsByIDs(ids []uuid.UUID) ([]ArticleRating, error)
+	SetReviewStatus(ids []uuid.UUID, status CommentStatus, reason string, moderatorID uuid.UUID, at time.Time) error
+
+	// Integrity
+	GetByID(id uuid.UUID) (*ArticleRating, error)
+	// Void excludes the rating from the aggregates and refreshes its article's cached values
+	Void(id, voidedBy uuid.UUID, reason string, at time.Time, priorWeight float64) error
+	// Reconcile recomputes every article's cached rating values, fixing and returning
+	// the drifted ones along with the number of articles checked
+	Reconcile(priorWeight float64) ([]RatingDrift, int64, error)
+	FindAnomalies(opts RatingAnomalyOptions) ([]RatingAnomaly, error)
 }
 
 type RatingDetail struct {
 	Content   int `json:"content"`
 	Clarity   int `json:"clarity"`
 	Relevance int `json:"relevance"`
+	// Review is the optional written review: nil keeps the current one, "" removes it
+	Review *string `json:"review,omitempty"`
 }
 
 type RatingService interface {
 	RateArticle(articleID, userID string, details RatingDetail) error
 	GetRatingStats(articleID string) (float64, int64, error)
 	GetUserRating(articleID, userID string) (*ArticleRating, error)
+	GetBreakdown(articleID uuid.UUID) (*RatingBreakdown, error)
+
+	ListReviews(articleID uuid.UUID, sort ReviewSort, viewerID *uuid.UUID, page, limit int) ([]ArticleRating, int64, error)
+	// VoteReview records a helpful (true) or not helpful (false) vote; nil removes the vote
+	VoteReview(reviewID, userID uuid.UUID, helpful *bool) (*ArticleRating, error)
+	GetReviewQueue(filter ReviewFilter, page, limit int) ([]ArticleRating, int64, error)
+	ModerateReviews(action ReviewModerationAction, moderatorID uuid.UUID) (*ReviewModerationResult, error)
+
+	VoidRating(id, moderatorID uuid.UUID, reason string) (*ArticleRating, error)
+	// CheckIntegrity reconciles the cached aggregates and scans for anomalies
+	CheckIntegrity() (*RatingIntegrityReport, error)
+	FindAnomalies() ([]RatingAnomaly, error)
 }
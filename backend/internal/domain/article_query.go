@@ -0,0 +1,79 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TagMatchMode controls how multiple tag filters are combined
+type TagMatchMode string
+
+const (
+	TagMatchAny TagMatchMode = "any" // article has at least one of the tags
+	TagMatchAll TagMatchMode = "all" // article has every tag
+)
+
+// Sortable article fields accepted by ArticleQuery.Sort
+const (
+	ArticleSortCreatedAt   = "created_at"
+	ArticleSortPublishedAt = "published_at"
+	ArticleSortUpdatedAt   = "updated_at"
+	ArticleSortTitle       = "title"
+	ArticleSortViews       = "views"
+	ArticleSortComments    = "comments"
+	ArticleSortRating      = "rating"
+	ArticleSortRatingScore = "rating_score"
+	ArticleSortEngagement  = "engagement"
+)
+
+// ArticleSortKey is one component of a combined sort, e.g. "-published_at"
+type ArticleSortKey struct {
+	Field string
+	Desc  bool
+}
+
+// ArticleQuery is the typed filter shared by the admin article list and the
+// public archive. Zero values mean "no filter"; ranges are inclusive.
+type ArticleQuery struct {
+	Status     ArticleStatus
+	Search     string
+	IsFeatured *bool
+	// Minimal selects only the columns needed for pickers and skips associations
+	Minimal bool
+
+	AuthorIDs []uuid.UUID
+
+	CategoryID *uuid.UUID
+	// IncludeSubcategories widens CategoryID to its whole subtree
+	IncludeSubcategories bool
+
+	TagIDs   []uuid.UUID
+	TagMatch TagMatchMode
+
+	CreatedFrom   *time.Time
+	CreatedTo     *time.Time
+	PublishedFrom *time.Time
+	PublishedTo   *time.Time
+	UpdatedFrom   *time.Time
+	UpdatedTo     *time.Time
+
+	MinRating *float64
+	MaxRating *float64
+	MinViews  *int
+	MaxViews  *int
+
+	HasImage *bool
+	Language string
+
+	// Sort is applied in order; an empty slice means newest first
+	Sort []ArticleSortKey
+}
+
+// PrimarySort returns the leading sort key, defaulting to newest first
+func (q ArticleQuery) PrimarySort() ArticleSortKey {
+	if len(q.Sort) == 0 {
+		return ArticleSortKey{Field: ArticleSortCreatedAt, Desc: true}
+	}
+	return q.Sort[0]
+}
@@ -76,6 +76,7 @@ type AuditRepository interface {
 	Create(log *AuditLog) error
 	CreateSystemLog(log *SystemLog) error
 	GetAuditLogs(page, limit int, filter map[string]interface{}) ([]AuditLog, int64, error)
+	GetAuditLogsByCursor(req CursorRequest, filter map[string]interface{}) (*PaginatedResult[AuditLog], error)
 	GetSystemLogs(page, limit int, filter map[string]interface{}) ([]SystemLog, int64, error)
 	GetAuditLog(id uuid.UUID) (*AuditLog, error)
 	GetSystemLog(id uuid.UUID) (*SystemLog, error)
@@ -87,6 +88,7 @@ type AuditService interface {
 	LogAction(userID uuid.UUID, action, tableName string, recordID uuid.UUID, oldData, newData interface{}) error
 	LogSystemEvent(userID *uuid.UUID, action, tableName string, recordID uuid.UUID, oldData, newData interface{}) error
 	GetAuditLogs(page, limit int, filter map[string]interface{}) ([]AuditLog, int64, error)
+	GetAuditLogsByCursor(req CursorRequest, filter map[string]interface{}) (*PaginatedResult[AuditLog], error)
 	GetSystemLogs(page, limit int, filter map[string]interface{}) ([]SystemLog, int64, error)
 	GetAuditLog(id uuid.UUID) (*AuditLog, error)
 	GetSystemLog(id uuid.UUID) (*SystemLog, error)
@@ -13,8 +13,10 @@ type Category struct {
 	ParentID    *uuid.UUID `gorm:"type:uuid" json:"parent_id"`
 	Description string     `json:"description"`
 	IsActive    bool       `gorm:"default:true" json:"is_active"`
-	CreatedAt   time.Time  `gorm:"default:now()" json:"created_at"`
-	UpdatedAt   time.Time  `gorm:"default:now()" json:"updated_at"`
+	// CommentPolicy applies to the category's articles and subcategories that set none
+	CommentPolicy *CommentPolicy `gorm:"type:jsonb;serializer:json" json:"comment_policy"`
+	CreatedAt     time.Time      `gorm:"default:now()" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"default:now()" json:"updated_at"`
 
 	Parent   *Category  `gorm:"foreignKey:ParentID" json:"parent,omitempty"`
 	Children []Category `gorm:"foreignKey:ParentID" json:"children,omitempty"`
@@ -48,6 +50,8 @@ type CategoryRepository interface {
 	GetList(page, limit int, search, sortBy, order string, minimal bool) (*PaginatedResult[Category], error)
 	GetStats() ([]CategoryStats, error)
 	GetTree() ([]Category, error)
+	// GetAncestors returns the category followed by its parents up to the root
+	GetAncestors(id uuid.UUID) ([]Category, error)
 	Update(category *Category) error
 	Delete(id uuid.UUID) error
 }
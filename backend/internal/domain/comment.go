@@ -1,23 +1,192 @@
 package domain
 
 import (
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// CommentStatus is the moderation state of a comment
+type CommentStatus string
+
+const (
+	CommentPending  CommentStatus = "PENDING"
+	CommentApproved CommentStatus = "APPROVED"
+	CommentRejected CommentStatus = "REJECTED"
+	CommentSpam     CommentStatus = "SPAM"
+)
+
+// IsValid reports whether s is a known moderation status
+func (s CommentStatus) IsValid() bool {
+	switch s {
+	case CommentPending, CommentApproved, CommentRejected, CommentSpam:
+		return true
+	}
+	return false
+}
+
 type Comment struct {
 	ID        uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
 	ArticleID uuid.UUID  `gorm:"type:uuid;not null" json:"article_id"`
-	UserID    uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;default:null" json:"user_id"` // uuid.Nil (NULL) for guests
 	Content   string     `gorm:"not null" json:"content"`
 	ParentID  *uuid.UUID `gorm:"type:uuid" json:"parent_id"`
 	IsSpam    bool       `gorm:"default:false" json:"is_spam"`
 	IsDeleted bool       `gorm:"default:false" json:"is_deleted"`
 	CreatedAt time.Time  `json:"created_at"`
 	UpdatedAt time.Time  `json:"updated_at"`
+	// Guest comments have no user row; GuestName is shown instead of User and
+	// GuestEmail is kept for moderators only
+	GuestName  string `gorm:"type:varchar(100)" json:"guest_name,omitempty"`
+	GuestEmail string `gorm:"type:varchar(255)" json:"-"`
+	// Edit marker for readers: EditedAt is the time of the latest content edit.
+	// Earlier texts are kept as CommentRevision rows, visible to moderators only.
+	EditedAt  *time.Time `json:"edited_at"`
+	EditCount int        `gorm:"not null;default:0" json:"edit_count"`
+	IsEdited  bool       `gorm:"not null;default:false" json:"is_edited"`
+
+	// Thread position. Path is the dot-separated chain of ancestor IDs ending with
+	// the comment's own (see CommentPathSegment); Depth is 0 for top-level comments.
+	Path  string `gorm:"type:text;not null;default:''" json:"-"`
+	Depth int    `gorm:"not null;default:0" json:"depth"`
+	// ReplyCount counts visible (approved, not deleted) direct replies
+	ReplyCount int `gorm:"not null;default:0" json:"reply_count"`
+
+	// Reactions, denormalized from comment_reactions on every toggle. ReactionCounts maps
+	// ReactionType to count; Score is the Wilson score of approving vs disapproving reactions.
+	ReactionCounts JSONB   `gorm:"type:jsonb" json:"reaction_counts,omitempty"`
+	Score          float64 `gorm:"not null;default:0" json:"score"`
+
+	// Moderation; only Status is public, the rest is served through CommentQueueItem
+	Status           CommentStatus `gorm:"type:varchar(20);not null;default:'APPROVED'" json:"status"`
+	ModerationReason string        `gorm:"type:text" json:"-"`
+	ModeratedBy      *uuid.UUID    `gorm:"type:uuid" json:"-"`
+	ModeratedAt      *time.Time    `json:"-"`
+	SpamScore        float64       `gorm:"default:0" json:"-"`
+	SpamReasons      JSONB         `gorm:"type:jsonb" json:"-"`
 
 	// Associations
 	User    *User     `gorm:"foreignKey:UserID" json:"user,omitempty"`
+	Article *Article  `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
 	Replies []Comment `gorm:"foreignKey:ParentID" json:"replies,omitempty"`
+	// Mentions are the users @mentioned in Content, resolved when it was saved
+	Mentions []CommentMention `gorm:"foreignKey:CommentID" json:"mentions,omitempty"`
+
+	// Set on thread listings when more replies exist than were loaded. They are
+	// fetched from the replies endpoint with ?after=RepliesCursor; an empty cursor
+	// means the replies were not loaded at all and start from the first page.
+	HasMoreReplies bool   `gorm:"-" json:"has_more_replies"`
+	RepliesCursor  string `gorm:"-" json:"replies_cursor,omitempty"`
+	// MyReactions are the viewer's own reactions, set on thread listings
+	MyReactions []ReactionType `gorm:"-" json:"my_reactions,omitempty"`
+	// IsCollapsed marks comments by authors the viewer muted or blocked
+	IsCollapsed bool `gorm:"-" json:"is_collapsed,omitempty"`
+}
+
+// IsGuest reports whether the comment was posted without an account
+func (c *Comment) IsGuest() bool {
+	return c.UserID == uuid.Nil
+}
+
+// CommentPathSegment is a comment's own component of Path: its ID in hex without
+// dashes, so every segment has the same width and a prefix selects a subtree.
+func CommentPathSegment(id uuid.UUID) string {
+	return strings.ReplaceAll(id.String(), "-", "")
+}
+
+// PlaceInThread assigns the ID, Path and Depth of a new comment under parent (nil for a top-level comment)
+func (c *Comment) PlaceInThread(parent *Comment) {
+	if c.ID == uuid.Nil {
+		c.ID = uuid.New()
+	}
+	c.Path = CommentPathSegment(c.ID)
+	c.Depth = 0
+	if parent != nil {
+		c.Path = parent.Path + "." + c.Path
+		c.Depth = parent.Depth + 1
+	}
+}
+
+// CommentSort orders the comments of one thread level
+type CommentSort string
+
+const (
+	CommentSortOldest CommentSort = "oldest"
+	CommentSortNewest CommentSort = "newest"
+	// CommentSortTop ranks comments by the Wilson score of their reactions
+	CommentSortTop CommentSort = "top"
+)
+
+// IsValid reports whether s is a known comment sort
+func (s CommentSort) IsValid() bool {
+	switch s {
+	case CommentSortOldest, CommentSortNewest, CommentSortTop:
+		return true
+	}
+	return false
+}
+
+// CommentThreadOptions controls how much of a thread one listing loads.
+// A listing is one page of sibling comments plus a bounded reply subtree below each.
+type CommentThreadOptions struct {
+	// ViewerID (optional) lets authors see their own pending comments
+	ViewerID *uuid.UUID
+	// Sort orders the listed comments, ReplySort the replies loaded below them
+	Sort      CommentSort
+	ReplySort CommentSort
+	// Depth is how many reply levels are loaded below each listed comment;
+	// negative means the comment_thread_depth setting
+	Depth int
+	// RepliesLimit caps the replies loaded per comment; negative means the
+	// comment_replies_per_level setting
+	RepliesLimit int
+	// CollapsedAuthors are the viewer's muted and blocked users, filled in by the service
+	CollapsedAuthors map[uuid.UUID]bool
+}
+
+// CommentModerationFilter narrows the admin moderation queue
+type CommentModerationFilter struct {
+	Status    CommentStatus
+	ArticleID *uuid.UUID
+	UserID    *uuid.UUID
+	Search    string
+	From      *time.Time
+	To        *time.Time
+	// MinSpamScore keeps comments the spam checker scored at or above the value
+	MinSpamScore *float64
+	// SortBySpamScore lists the most suspicious comments first
+	SortBySpamScore bool
+}
+
+// CommentModerationAction is a bulk moderation request
+type CommentModerationAction struct {
+	IDs    []uuid.UUID
+	Status CommentStatus
+	Reason string
+}
+
+// CommentQueueItem is a comment in the moderation queue with the moderation fields
+// readers don't see
+type CommentQueueItem struct {
+	Comment
+	ModerationReason string     `json:"moderation_reason,omitempty"`
+	ModeratedBy      *uuid.UUID `json:"moderated_by,omitempty"`
+	ModeratedAt      *time.Time `json:"moderated_at,omitempty"`
+	SpamScore        float64    `json:"spam_score"`
+	SpamReasons      JSONB      `json:"spam_reasons,omitempty"`
+}
+
+// CommentStatusChange is one comment whose status was changed by moderation
+type CommentStatusChange struct {
+	Comment Comment
+	From    CommentStatus
+}
+
+// CommentModerationResult reports the outcome of a bulk moderation request
+type CommentModerationResult struct {
+	Updated  []uuid.UUID `json:"updated"`
+	NotFound []uuid.UUID `json:"not_found"`
+	// Changes lets the caller push live updates; not serialised
+	Changes []CommentStatusChange `json:"-"`
 }
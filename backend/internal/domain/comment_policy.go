@@ -0,0 +1,178 @@
+package domain
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentPolicyMode says who may comment on an article and how comments are published
+type CommentPolicyMode string
+
+const (
+	CommentPolicyOpen    CommentPolicyMode = "OPEN"
+	CommentPolicyClosed  CommentPolicyMode = "CLOSED"
+	CommentPolicyMembers CommentPolicyMode = "MEMBERS_ONLY"
+	// CommentPolicyPreModerated holds every new comment for approval
+	CommentPolicyPreModerated CommentPolicyMode = "PRE_MODERATED"
+)
+
+// IsValid reports whether m is a known policy mode
+func (m CommentPolicyMode) IsValid() bool {
+	switch m {
+	case CommentPolicyOpen, CommentPolicyClosed, CommentPolicyMembers, CommentPolicyPreModerated:
+		return true
+	}
+	return false
+}
+
+// maxCommentAutoCloseDays bounds AutoCloseDays to about ten years
+const maxCommentAutoCloseDays = 3650
+
+// CommentPolicy is the comment setting of an article or a category. A nil policy
+// inherits from the parent category, then from comment_default_policy in settings.
+type CommentPolicy struct {
+	Mode CommentPolicyMode `json:"mode"`
+	// Roles lists who may comment in MEMBERS_ONLY mode; empty means any signed-in user
+	Roles []string `json:"roles,omitempty"`
+	// AutoCloseDays closes comments that many days after published_at; 0 never closes
+	AutoCloseDays int `json:"auto_close_days,omitempty"`
+}
+
+// Normalize upper-cases the mode and roles and checks the policy. A nil policy is valid.
+func (p *CommentPolicy) Normalize() error {
+	if p == nil {
+		return nil
+	}
+	p.Mode = CommentPolicyMode(strings.ToUpper(strings.TrimSpace(string(p.Mode))))
+	if !p.Mode.IsValid() {
+		return errors.New("chế độ bình luận không hợp lệ")
+	}
+	if p.AutoCloseDays < 0 || p.AutoCloseDays > maxCommentAutoCloseDays {
+		return errors.New("số ngày tự động đóng bình luận không hợp lệ")
+	}
+
+	roles := p.Roles[:0]
+	for _, r := range p.Roles {
+		if r = strings.ToUpper(strings.TrimSpace(r)); r != "" {
+			roles = append(roles, r)
+		}
+	}
+	p.Roles = roles
+	if p.Mode != CommentPolicyMembers {
+		p.Roles = nil
+	}
+	return nil
+}
+
+// CommentPolicySource is where an effective policy was set
+type CommentPolicySource string
+
+const (
+	CommentPolicyFromArticle  CommentPolicySource = "ARTICLE"
+	CommentPolicyFromCategory CommentPolicySource = "CATEGORY"
+	CommentPolicyFromDefault  CommentPolicySource = "DEFAULT"
+)
+
+// EffectiveCommentPolicy is the policy that applies to an article after inheritance
+type EffectiveCommentPolicy struct {
+	CommentPolicy
+	Source CommentPolicySource `json:"source"`
+	// CategoryID is the category the policy was inherited from
+	CategoryID *uuid.UUID `json:"category_id,omitempty"`
+	// ClosesAt is when AutoCloseDays takes effect, for published articles
+	ClosesAt *time.Time `json:"closes_at,omitempty"`
+	// IsOpen is false when the mode is CLOSED or ClosesAt has passed
+	IsOpen bool `json:"is_open"`
+}
+
+// AllowsRoles reports whether a user with the given roles may comment. Guests have
+// no roles and are only let in by OPEN and PRE_MODERATED policies.
+func (p EffectiveCommentPolicy) AllowsRoles(roles []string, signedIn bool) bool {
+	if p.Mode != CommentPolicyMembers {
+		return true
+	}
+	if !signedIn {
+		return false
+	}
+	if len(p.Roles) == 0 {
+		return true
+	}
+	for _, want := range p.Roles {
+		for _, have := range roles {
+			if strings.EqualFold(want, have) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ResolveCommentPolicy applies inheritance: the article's own policy, then the nearest
+// category in ancestors (the article's category first) with a policy, then fallback.
+// An article without a policy whose legacy allow_comment flag is off stays closed.
+func ResolveCommentPolicy(article *Article, ancestors []Category, fallback CommentPolicy, now time.Time) EffectiveCommentPolicy {
+	effective := EffectiveCommentPolicy{CommentPolicy: fallback, Source: CommentPolicyFromDefault}
+	switch {
+	case article.CommentPolicy != nil:
+		effective.CommentPolicy = *article.CommentPolicy
+		effective.Source = CommentPolicyFromArticle
+	case !article.AllowComment:
+		effective.CommentPolicy = CommentPolicy{Mode: CommentPolicyClosed}
+		effective.Source = CommentPolicyFromArticle
+	default:
+		for i := range ancestors {
+			if ancestors[i].CommentPolicy != nil {
+				effective.CommentPolicy = *ancestors[i].CommentPolicy
+				effective.Source = CommentPolicyFromCategory
+				effective.CategoryID = &ancestors[i].ID
+				break
+			}
+		}
+	}
+	if !effective.Mode.IsValid() {
+		effective.Mode = CommentPolicyOpen
+	}
+
+	effective.IsOpen = effective.Mode != CommentPolicyClosed
+	if effective.AutoCloseDays > 0 && article.PublishedAt != nil {
+		closesAt := article.PublishedAt.UTC().AddDate(0, 0, effective.AutoCloseDays)
+		effective.ClosesAt = &closesAt
+		if !now.Before(closesAt) {
+			effective.IsOpen = false
+		}
+	}
+	return effective
+}
+
+// SettingCommentPolicy reads a policy object such as
+// {"mode": "MEMBERS_ONLY", "roles": ["AUTHOR"], "auto_close_days": 30} from settings.
+// A missing or invalid value yields an OPEN policy.
+func SettingCommentPolicy(settings map[string]interface{}, key string) CommentPolicy {
+	open := CommentPolicy{Mode: CommentPolicyOpen}
+	var b []byte
+	switch v := settings[key].(type) {
+	case nil:
+		return open
+	case string:
+		b = []byte(v)
+	default:
+		var err error
+		if b, err = json.Marshal(v); err != nil {
+			return open
+		}
+	}
+	var policy CommentPolicy
+	if err := json.Unmarshal(b, &policy); err != nil || policy.Normalize() != nil {
+		return open
+	}
+	return policy
+}
+
+// CommentPolicyResolver computes the effective comment policy of articles
+type CommentPolicyResolver interface {
+	Resolve(article *Article) EffectiveCommentPolicy
+}
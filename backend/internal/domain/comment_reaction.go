@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReactionType is one of the fixed reactions readers can leave on a comment
+type ReactionType string
+
+const (
+	ReactionLike  ReactionType = "LIKE"
+	ReactionLove  ReactionType = "LOVE"
+	ReactionHaha  ReactionType = "HAHA"
+	ReactionWow   ReactionType = "WOW"
+	ReactionSad   ReactionType = "SAD"
+	ReactionAngry ReactionType = "ANGRY"
+)
+
+// IsValid reports whether t is in the fixed reaction set
+func (t ReactionType) IsValid() bool {
+	switch t {
+	case ReactionLike, ReactionLove, ReactionHaha, ReactionWow, ReactionSad, ReactionAngry:
+		return true
+	}
+	return false
+}
+
+// Sentiment is +1 for approving reactions, -1 for disapproving ones and 0 for
+// reactions that say nothing about the comment's quality
+func (t ReactionType) Sentiment() int {
+	switch t {
+	case ReactionLike, ReactionLove, ReactionHaha, ReactionWow:
+		return 1
+	case ReactionAngry:
+		return -1
+	}
+	return 0
+}
+
+// CommentReaction is one user's reaction of one type on a comment.
+// Each type toggles independently, so a user may leave several.
+type CommentReaction struct {
+	CommentID uuid.UUID    `gorm:"type:uuid;primaryKey" json:"comment_id"`
+	UserID    uuid.UUID    `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Type      ReactionType `gorm:"type:varchar(20);primaryKey" json:"type"`
+	CreatedAt time.Time    `json:"created_at"`
+}
+
+// CommentReactionSummary is the state of a comment's reactions after a toggle
+type CommentReactionSummary struct {
+	CommentID uuid.UUID            `json:"comment_id"`
+	ArticleID uuid.UUID            `json:"article_id"`
+	Reaction  ReactionType         `json:"reaction"`
+	Added     bool                 `json:"added"`
+	Counts    map[ReactionType]int `json:"reaction_counts"`
+	Score     float64              `json:"score"`
+	// MyReactions are the toggling user's reactions on the comment
+	MyReactions []ReactionType `json:"my_reactions"`
+}
+
+// WilsonScore is the lower bound of the 95% Wilson confidence interval for the
+// share of positive votes, so a comment with 40 of 50 approving reactions ranks
+// above one with a single approving reaction. It is 0 without votes.
+func WilsonScore(positive, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	const z = 1.96
+	n := float64(total)
+	p := float64(positive) / n
+	return (p + z*z/(2*n) - z*math.Sqrt((p*(1-p)+z*z/(4*n))/n)) / (1 + z*z/n)
+}
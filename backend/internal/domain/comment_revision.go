@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentRevision is the text a comment had before one of its edits. Revision 1 is
+// the original; the current text lives on the comment itself.
+type CommentRevision struct {
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	CommentID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_comment_revision_number" json:"comment_id"`
+	Revision  int       `gorm:"not null;uniqueIndex:idx_comment_revision_number" json:"revision"`
+	Content   string    `gorm:"type:text;not null" json:"content"`
+	// EditedBy is who replaced this text, EditedAt when
+	EditedBy  uuid.UUID `gorm:"type:uuid;not null" json:"edited_by"`
+	EditedAt  time.Time `gorm:"not null" json:"edited_at"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Editor *User `gorm:"foreignKey:EditedBy" json:"editor,omitempty"`
+}
+
+// CommentHistory is a comment with every earlier version of its text, oldest first,
+// for moderators
+type CommentHistory struct {
+	Comment *Comment `json:"comment"`
+	// GuestEmail is the contact address a guest left, if any
+	GuestEmail string            `json:"guest_email,omitempty"`
+	Revisions  []CommentRevision `json:"revisions"`
+}
@@ -8,6 +8,7 @@ type DashboardAnalyticsData struct {
 	TotalCategories int64           `json:"total_categories"`
 	TotalReaders    int64           `json:"total_readers"`
 	ArticleTrend    []ArticleTrend  `json:"article_trend"`
+	ViewTrend       []ArticleTrend  `json:"view_trend"`
 	TopCategories   []CategoryStats `json:"top_categories"`
 	TopTags         []TagStats      `json:"top_tags"`
 }
@@ -0,0 +1,201 @@
+package domain
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// MaxEngagementActiveSeconds caps the active time a single reading session may report
+const MaxEngagementActiveSeconds = 6 * 60 * 60
+
+// ArticleEngagement is one reading session of an article, built from the periodic
+// pings the page sends. Pings only raise MaxScroll and ActiveSeconds and can only
+// set ReachedEnd, so late or repeated pings are harmless.
+type ArticleEngagement struct {
+	ID            uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ArticleID     uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_article_engagements_session" json:"article_id"`
+	SessionID     string    `gorm:"type:varchar(64);not null;uniqueIndex:idx_article_engagements_session" json:"session_id"`
+	VisitorID     string    `gorm:"type:varchar(64);not null" json:"-"`
+	MaxScroll     int       `gorm:"not null;default:0" json:"max_scroll"`
+	ActiveSeconds int       `gorm:"not null;default:0" json:"active_seconds"`
+	ReachedEnd    bool      `gorm:"not null;default:false" json:"reached_end"`
+	Pings         int       `gorm:"not null;default:1" json:"pings"`
+	StartedAt     time.Time `gorm:"type:timestamptz;not null;index" json:"started_at"`
+	UpdatedAt     time.Time `gorm:"type:timestamptz" json:"updated_at"`
+}
+
+// EngagementKey identifies a reading session
+type EngagementKey struct {
+	ArticleID uuid.UUID
+	SessionID string
+}
+
+// Merge folds a later ping of the same session into e
+func (e *ArticleEngagement) Merge(o ArticleEngagement) {
+	if o.MaxScroll > e.MaxScroll {
+		e.MaxScroll = o.MaxScroll
+	}
+	if o.ActiveSeconds > e.ActiveSeconds {
+		e.ActiveSeconds = o.ActiveSeconds
+	}
+	e.ReachedEnd = e.ReachedEnd || o.ReachedEnd
+	e.Pings += o.Pings
+	if o.UpdatedAt.After(e.UpdatedAt) {
+		e.UpdatedAt = o.UpdatedAt
+	}
+}
+
+// EngagementPing is what the page reports: the session ID is random per page load,
+// ActiveSeconds is the non-idle time so far and ScrollDepth the deepest point reached
+// in percent of the article body
+type EngagementPing struct {
+	SessionID     string
+	ScrollDepth   int
+	ActiveSeconds int
+	ReachedEnd    bool
+}
+
+// EngagementRecorder accepts sessions for buffered storage; false means dropped
+type EngagementRecorder interface {
+	Record(e ArticleEngagement) bool
+}
+
+// EngagementSummary is the aggregate of an article's sessions
+type EngagementSummary struct {
+	Sessions       int64   `json:"sessions"`
+	Completed      int64   `json:"completed"`
+	CompletionRate float64 `json:"completion_rate" gorm:"-"`
+	MedianActive   float64 `json:"median_active_seconds"`
+	AvgActive      float64 `json:"avg_active_seconds"`
+	AvgScroll      float64 `json:"avg_scroll"`
+}
+
+// ScrollCount is the number of sessions whose deepest scroll was Depth percent
+type ScrollCount struct {
+	Depth    int   `json:"depth"`
+	Sessions int64 `json:"sessions"`
+}
+
+type EngagementRepository interface {
+	// UpsertBatch merges sessions into stored ones; a session ID already used by
+	// another visitor is left untouched
+	UpsertBatch(sessions []ArticleEngagement) error
+	Summary(articleID uuid.UUID, from, to time.Time) (*EngagementSummary, error)
+	ScrollHistogram(articleID uuid.UUID, from, to time.Time) ([]ScrollCount, error)
+	DeleteBefore(before time.Time, chunk int) (int64, error)
+}
+
+// OutlineSection is a part of an article starting at a heading. StartPct places the
+// heading by its offset in the content, which approximates its scroll position.
+type OutlineSection struct {
+	Index    int     `json:"index"`
+	Level    int     `json:"level"`
+	Title    string  `json:"title"`
+	StartPct float64 `json:"start_pct"`
+	EndPct   float64 `json:"end_pct"`
+}
+
+var (
+	markdownHeading = regexp.MustCompile(`^(#{1,3})\s+(.+?)\s*#*\s*$`)
+	htmlHeading     = regexp.MustCompile(`(?i)^<h([1-3])[^>]*>(.*?)</h[1-3]>`)
+	htmlTag         = regexp.MustCompile(`<[^>]+>`)
+	// headingMarkup strips inline emphasis and code markers from heading titles
+	headingMarkup = strings.NewReplacer("**", "", "__", "", "*", "", "`", "")
+)
+
+// ParseOutline splits markdown content at its level 1-3 headings (markdown or raw
+// HTML), skipping fenced code. Text before the first heading is an intro section.
+func ParseOutline(content string) []OutlineSection {
+	total := float64(len(content))
+	if total == 0 {
+		return nil
+	}
+
+	var sections []OutlineSection
+	inFence := false
+	offset := 0
+	for _, line := range strings.SplitAfter(content, "\n") {
+		start := offset
+		offset += len(line)
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+
+		var level int
+		var title string
+		if m := markdownHeading.FindStringSubmatch(trimmed); m != nil {
+			level, title = len(m[1]), m[2]
+		} else if m := htmlHeading.FindStringSubmatch(trimmed); m != nil {
+			level, title = int(m[1][0]-'0'), htmlTag.ReplaceAllString(m[2], "")
+		} else {
+			continue
+		}
+
+		if len(sections) == 0 && start > 0 {
+			sections = append(sections, OutlineSection{Title: "Mở đầu"})
+		}
+		sections = append(sections, OutlineSection{
+			Level:    level,
+			Title:    strings.TrimSpace(headingMarkup.Replace(title)),
+			StartPct: float64(start) / total * 100,
+		})
+	}
+	if len(sections) == 0 {
+		sections = append(sections, OutlineSection{Title: "Toàn bài"})
+	}
+
+	for i := range sections {
+		sections[i].Index = i
+		sections[i].EndPct = 100
+		if i+1 < len(sections) {
+			sections[i].EndPct = sections[i+1].StartPct
+		}
+	}
+	return sections
+}
+
+// SectionDropOff tells how many sessions reached a section and how many of them
+// stopped scrolling inside it
+type SectionDropOff struct {
+	OutlineSection
+	Reached     int64   `json:"reached"`
+	Dropped     int64   `json:"dropped"`
+	DropOffRate float64 `json:"drop_off_rate"`
+}
+
+// ScrollReach is the share of sessions that scrolled at least Depth percent
+type ScrollReach struct {
+	Depth    int     `json:"depth"`
+	Sessions int64   `json:"sessions"`
+	Rate     float64 `json:"rate"`
+}
+
+// ArticleEngagementStats is the per-article read-depth report
+type ArticleEngagementStats struct {
+	ArticleID uuid.UUID `json:"article_id"`
+	From      time.Time `json:"from"`
+	To        time.Time `json:"to"`
+	EngagementSummary
+	ScrollReach []ScrollReach    `json:"scroll_reach"`
+	Sections    []SectionDropOff `json:"sections"`
+}
+
+// HasAnyRole reports whether roles contains one of want
+func HasAnyRole(roles []string, want ...string) bool {
+	for _, have := range roles {
+		for _, w := range want {
+			if strings.EqualFold(have, w) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// GuestChallenge is a proof-of-work puzzle that guests solve before commenting:
+// find a Solution such that SHA-256(Token + ":" + Solution) starts with Difficulty
+// zero bits. The token is HMAC-signed, expires and can be used only once.
+type GuestChallenge struct {
+	Token      string    `json:"token"`
+	Algorithm  string    `json:"algorithm"`
+	Difficulty int       `json:"difficulty"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// GuestChallengeService issues and checks guest comment challenges
+type GuestChallengeService interface {
+	// Issue fails when guest comments are disabled in settings
+	Issue() (*GuestChallenge, error)
+	Verify(token, solution string) error
+}
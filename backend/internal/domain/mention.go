@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"regexp"
+
+	"github.com/google/uuid"
+)
+
+// MaxMentionsPerComment caps how many users one comment can notify
+const MaxMentionsPerComment = 10
+
+// mentionPattern matches the markup the comment editor's autocomplete inserts:
+// @[Display Name](user-id). Plain "@name" text is left alone because users have
+// no unique handle to resolve it against.
+var mentionPattern = regexp.MustCompile(`@\[([^\]\n]{1,100})\]\(([0-9a-fA-F-]{36})\)`)
+
+// CommentMention is a user mentioned in a comment, resolved when the comment is saved
+type CommentMention struct {
+	CommentID uuid.UUID `gorm:"type:uuid;primaryKey" json:"-"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	// Name is the user's name at the time of the mention
+	Name string `gorm:"type:varchar(255)" json:"name"`
+}
+
+// ParseMentionIDs returns the distinct user IDs mentioned in content, in order of
+// appearance and at most MaxMentionsPerComment of them
+func ParseMentionIDs(content string) []uuid.UUID {
+	var ids []uuid.UUID
+	seen := make(map[uuid.UUID]bool)
+	for _, m := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		id, err := uuid.Parse(m[2])
+		if err != nil || seen[id] {
+			continue
+		}
+		seen[id] = true
+		ids = append(ids, id)
+		if len(ids) == MaxMentionsPerComment {
+			break
+		}
+	}
+	return ids
+}
+
+// StripMentionMarkup replaces mention markup with "@Display Name" for plain-text output
+func StripMentionMarkup(content string) string {
+	return mentionPattern.ReplaceAllString(content, "@$1")
+}
@@ -0,0 +1,126 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// NotificationType is why a user was notified
+type NotificationType string
+
+const (
+	// NotificationMention: the user was @mentioned in a comment
+	NotificationMention NotificationType = "MENTION"
+	// NotificationReply: someone replied to the user's comment
+	NotificationReply NotificationType = "REPLY"
+)
+
+// Notification is an in-app notification about a comment. There is at most one
+// per user, comment and type, so re-approving or editing a comment doesn't notify twice.
+type Notification struct {
+	ID        uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID    uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_notification_unique" json:"user_id"`
+	Type      NotificationType `gorm:"type:varchar(20);not null;uniqueIndex:idx_notification_unique" json:"type"`
+	ActorID   uuid.UUID        `gorm:"type:uuid;not null" json:"actor_id"`
+	ArticleID uuid.UUID        `gorm:"type:uuid;not null" json:"article_id"`
+	CommentID uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_notification_unique" json:"comment_id"`
+	Excerpt   string           `gorm:"type:varchar(200)" json:"excerpt"`
+	CreatedAt time.Time        `json:"created_at"`
+	ReadAt    *time.Time       `json:"read_at"`
+	EmailedAt *time.Time       `json:"-"`
+
+	Actor   *User    `gorm:"foreignKey:ActorID" json:"actor,omitempty"`
+	Article *Article `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
+}
+
+// NotificationPreference holds a user's opt-outs. Every field defaults to off,
+// so users without a row get all in-app notifications and no email.
+type NotificationPreference struct {
+	UserID       uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	MuteMentions bool       `gorm:"not null;default:false" json:"mute_mentions"`
+	MuteReplies  bool       `gorm:"not null;default:false" json:"mute_replies"`
+	EmailDigest  bool       `gorm:"not null;default:false" json:"email_digest"`
+	MutedUntil   *time.Time `json:"muted_until"`
+	LastDigestAt *time.Time `json:"-"`
+	UpdatedAt    time.Time  `json:"updated_at"`
+
+	User *User `gorm:"foreignKey:UserID" json:"-"`
+	// MutedArticles lists the threads the user muted; not stored on the row
+	MutedArticles []uuid.UUID `gorm:"-" json:"muted_articles"`
+}
+
+// Mutes reports whether notifications of type t are currently off
+func (p *NotificationPreference) Mutes(t NotificationType, now time.Time) bool {
+	if p.MutedUntil != nil && now.Before(*p.MutedUntil) {
+		return true
+	}
+	switch t {
+	case NotificationMention:
+		return p.MuteMentions
+	case NotificationReply:
+		return p.MuteReplies
+	}
+	return false
+}
+
+// NotificationMute silences comment notifications from one article's thread
+type NotificationMute struct {
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	ArticleID uuid.UUID `gorm:"type:uuid;primaryKey" json:"article_id"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// MentionSuggestion is a user offered by the mention autocomplete
+type MentionSuggestion struct {
+	ID        uuid.UUID `json:"id"`
+	FullName  string    `json:"full_name"`
+	AvatarURL string    `json:"avatar_url"`
+}
+
+// Mailer sends plain-text email
+type Mailer interface {
+	Send(to, subject, body string) error
+}
+
+type NotificationRepository interface {
+	// CreateIfAbsent inserts the notifications, skipping ones that already exist, and returns those inserted
+	CreateIfAbsent(notifications []Notification) ([]Notification, error)
+	List(userID uuid.UUID, unreadOnly bool, offset, limit int) ([]Notification, int64, error)
+	CountUnread(userID uuid.UUID) (int64, error)
+	// MarkRead marks the given notifications, or all of the user's when ids is empty, as read
+	MarkRead(userID uuid.UUID, ids []uuid.UUID, at time.Time) error
+
+	GetPreferences(userIDs []uuid.UUID) (map[uuid.UUID]NotificationPreference, error)
+	SavePreference(pref *NotificationPreference) error
+	ListMutes(userID uuid.UUID) ([]uuid.UUID, error)
+	MutedUsers(userIDs []uuid.UUID, articleID uuid.UUID) (map[uuid.UUID]bool, error)
+	AddMute(userID, articleID uuid.UUID) error
+	RemoveMute(userID, articleID uuid.UUID) error
+
+	// Users that can be mentioned
+	GetActiveUsers(ids []uuid.UUID) ([]User, error)
+	SearchActiveUsers(query string, limit int) ([]User, error)
+
+	// Email digest
+	ListDigestDue(lastBefore time.Time) ([]NotificationPreference, error)
+	GetUnemailed(userID uuid.UUID, limit int) ([]Notification, error)
+	MarkEmailed(ids []uuid.UUID, at time.Time) error
+	MarkDigestSent(userID uuid.UUID, at time.Time) error
+}
+
+type NotificationService interface {
+	// ResolveMentions turns the mention markup in content into mention records of active users
+	ResolveMentions(content string) ([]CommentMention, error)
+	// NotifyComment notifies the users mentioned in a newly visible comment and the
+	// author of the comment it replies to (parentAuthorID, optional)
+	NotifyComment(comment *Comment, parentAuthorID *uuid.UUID)
+
+	List(userID uuid.UUID, unreadOnly bool, page, limit int) ([]Notification, int64, int64, error)
+	MarkRead(userID uuid.UUID, ids []uuid.UUID) error
+	GetPreference(userID uuid.UUID) (*NotificationPreference, error)
+	UpdatePreference(userID uuid.UUID, pref *NotificationPreference) error
+	MuteArticle(userID, articleID uuid.UUID) error
+	UnmuteArticle(userID, articleID uuid.UUID) error
+	SuggestMentions(query string) ([]MentionSuggestion, error)
+}
@@ -1,5 +1,17 @@
 package domain
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+
+	"github.com/google/uuid"
+)
+
+// ErrInvalidCursor is returned when an ?after= cursor cannot be decoded or
+// was issued for a different sort order
+var ErrInvalidCursor = errors.New("invalid pagination cursor")
+
 type Pagination struct {
 	Page       int   `json:"page"`
 	Limit      int   `json:"limit"`
@@ -7,7 +19,53 @@ type Pagination struct {
 	TotalPages int   `json:"total_pages"`
 }
 
+// CursorPagination describes a keyset page. TotalRows in Pagination is only
+// meaningful when TotalKnown is true, since counting is optional in this mode.
+type CursorPagination struct {
+	NextCursor string `json:"next_cursor,omitempty"`
+	HasMore    bool   `json:"has_more"`
+	TotalKnown bool   `json:"total_known"`
+}
+
 type PaginatedResult[T any] struct {
-	Data       []T        `json:"data"`
-	Pagination Pagination `json:"pagination"`
+	Data       []T               `json:"data"`
+	Pagination Pagination        `json:"pagination"`
+	Cursor     *CursorPagination `json:"cursor,omitempty"`
+}
+
+// CursorRequest carries keyset pagination parameters (?after=<cursor>&limit=&with_total=)
+type CursorRequest struct {
+	After     string
+	Limit     int
+	WithTotal bool
+}
+
+// Cursor is the decoded form of an opaque ?after= token: the sort key it was
+// issued for, the last row's sort value and its ID as a tiebreaker.
+type Cursor struct {
+	Sort  string    `json:"s"`
+	Value string    `json:"v"`
+	ID    uuid.UUID `json:"id"`
+}
+
+// Encode returns the opaque, URL-safe form of the cursor
+func (c Cursor) Encode() string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// DecodeCursor parses an opaque cursor; an empty string means "first page" and returns nil
+func DecodeCursor(s string) (*Cursor, error) {
+	if s == "" {
+		return nil, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c Cursor
+	if err := json.Unmarshal(b, &c); err != nil || c.ID == uuid.Nil {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
 }
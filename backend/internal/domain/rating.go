@@ -0,0 +1,80 @@
+package domain
+
+import (
+	"math"
+
+	"github.com/google/uuid"
+)
+
+// RatingOverallExpr is one rating's exact overall score: the mean of its three
+// criteria, or the stored score for old ratings saved before criteria existed
+const RatingOverallExpr = "CASE WHEN content_score > 0 AND clarity_score > 0 AND relevance_score > 0 " +
+	"THEN (content_score + clarity_score + relevance_score) / 3.0 ELSE score END"
+
+// DefaultRatingPriorWeight is how many average ratings the Bayesian score pretends
+// every article already has, when rating_prior_weight is unset
+const DefaultRatingPriorWeight = 5.0
+
+// RatingCriterionStats is the average and star histogram of one rating criterion
+type RatingCriterionStats struct {
+	Average float64 `json:"average"`
+	Count   int64   `json:"count"`
+	// Histogram[i] counts ratings of i+1 stars
+	Histogram [5]int64 `json:"histogram"`
+}
+
+// Add counts n ratings of the given stars; other values are ignored
+func (s *RatingCriterionStats) Add(stars int, n int64) {
+	if stars < 1 || stars > 5 {
+		return
+	}
+	s.Histogram[stars-1] += n
+	s.Count += n
+}
+
+// Finish computes Average from the histogram
+func (s *RatingCriterionStats) Finish() {
+	if s.Count == 0 {
+		s.Average = 0
+		return
+	}
+	var sum int64
+	for i, n := range s.Histogram {
+		sum += int64(i+1) * n
+	}
+	s.Average = float64(sum) / float64(s.Count)
+}
+
+// RatingBreakdown is the detailed rating picture of one article
+type RatingBreakdown struct {
+	ArticleID uuid.UUID `json:"article_id"`
+	Count     int64     `json:"count"`
+	// Average is the exact mean of the ratings' overall scores
+	Average float64 `json:"average"`
+	// BayesianScore shrinks Average towards the site mean for articles with few
+	// ratings, so one 5★ rating doesn't outrank fifty 4.8★ ones
+	BayesianScore float64 `json:"bayesian_score"`
+	PriorMean     float64 `json:"prior_mean"`
+	PriorWeight   float64 `json:"prior_weight"`
+
+	// Overall's histogram counts the rounded overall score of each rating
+	Overall   RatingCriterionStats `json:"overall"`
+	Content   RatingCriterionStats `json:"content"`
+	Clarity   RatingCriterionStats `json:"clarity"`
+	Relevance RatingCriterionStats `json:"relevance"`
+}
+
+// BayesianRating is (priorWeight*priorMean + count*average) / (priorWeight + count)
+func BayesianRating(average float64, count int64, priorMean, priorWeight float64) float64 {
+	if priorWeight <= 0 {
+		return average
+	}
+	n := float64(count)
+	return (priorWeight*priorMean + n*average) / (priorWeight + n)
+}
+
+// OverallRatingScore is the rounded mean of the three criteria, the 1-5 star score
+// stored with a rating
+func OverallRatingScore(d RatingDetail) int {
+	return int(math.Round(float64(d.Content+d.Clarity+d.Relevance) / 3.0))
+}
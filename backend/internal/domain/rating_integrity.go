@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Defaults for the rating anomaly detector, overridable with the
+// rating_anomaly_window_hours, rating_anomaly_account_age_days and
+// rating_anomaly_min_count settings
+const (
+	DefaultRatingAnomalyWindowHours    = 24
+	DefaultRatingAnomalyAccountAgeDays = 7
+	DefaultRatingAnomalyMinCount       = 5
+)
+
+// RatingDrift is an article whose cached rating_avg / rating_count no longer
+// matched its (non-voided) ratings when the reconciliation job ran
+type RatingDrift struct {
+	ArticleID   uuid.UUID `json:"article_id"`
+	Title       string    `json:"title"`
+	StoredAvg   float64   `json:"stored_avg"`
+	ActualAvg   float64   `json:"actual_avg"`
+	StoredCount int       `json:"stored_count"`
+	ActualCount int       `json:"actual_count"`
+}
+
+// RatingAnomalyOptions selects the burst pattern to look for: at least MinCount
+// ratings with the same extreme score (1 or 5 stars) on one article since Since,
+// all from accounts younger than MaxAccountAge when they rated
+type RatingAnomalyOptions struct {
+	Since         time.Time
+	MaxAccountAge time.Duration
+	MinCount      int
+}
+
+// RatingAnomaly is a suspicious burst of ratings on one article
+type RatingAnomaly struct {
+	ArticleID uuid.UUID `json:"article_id"`
+	Title     string    `json:"title"`
+	Score     int       `json:"score"`
+	// Count ratings with Score came from new accounts, out of Total ratings in the window
+	Count     int         `json:"count"`
+	Total     int         `json:"total"`
+	FirstAt   time.Time   `json:"first_at"`
+	LastAt    time.Time   `json:"last_at"`
+	RatingIDs []uuid.UUID `json:"rating_ids"`
+}
+
+// RatingIntegrityReport is the outcome of one reconciliation and anomaly scan
+type RatingIntegrityReport struct {
+	StartedAt  time.Time       `json:"started_at"`
+	FinishedAt time.Time       `json:"finished_at"`
+	Articles   int64           `json:"articles"`
+	Drifts     []RatingDrift   `json:"drifts"`
+	Anomalies  []RatingAnomaly `json:"anomalies"`
+	Error      string          `json:"error,omitempty"`
+}
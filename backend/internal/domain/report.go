@@ -0,0 +1,130 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReportTargetType is the kind of content a report is about
+type ReportTargetType string
+
+const (
+	ReportTargetComment ReportTargetType = "COMMENT"
+	ReportTargetArticle ReportTargetType = "ARTICLE"
+)
+
+// ReportReason is the category a reader picks when reporting
+type ReportReason string
+
+const (
+	ReportSpam           ReportReason = "SPAM"
+	ReportHarassment     ReportReason = "HARASSMENT"
+	ReportHateSpeech     ReportReason = "HATE_SPEECH"
+	ReportMisinformation ReportReason = "MISINFORMATION"
+	ReportInappropriate  ReportReason = "INAPPROPRIATE"
+	ReportCopyright      ReportReason = "COPYRIGHT"
+	ReportOther          ReportReason = "OTHER"
+)
+
+// IsValid reports whether r is a known report reason
+func (r ReportReason) IsValid() bool {
+	switch r {
+	case ReportSpam, ReportHarassment, ReportHateSpeech, ReportMisinformation, ReportInappropriate, ReportCopyright, ReportOther:
+		return true
+	}
+	return false
+}
+
+// ReportStatus is the triage state of a report
+type ReportStatus string
+
+const (
+	ReportOpen      ReportStatus = "OPEN"
+	ReportActioned  ReportStatus = "ACTIONED"
+	ReportDismissed ReportStatus = "DISMISSED"
+)
+
+// IsValid reports whether s is a known triage state
+func (s ReportStatus) IsValid() bool {
+	switch s {
+	case ReportOpen, ReportActioned, ReportDismissed:
+		return true
+	}
+	return false
+}
+
+// ContentReport is one reader's report about a comment or an article.
+// A user can report each item once.
+type ContentReport struct {
+	ID             uuid.UUID        `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	TargetType     ReportTargetType `gorm:"type:varchar(20);not null;uniqueIndex:idx_content_report_unique" json:"target_type"`
+	TargetID       uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_content_report_unique" json:"target_id"`
+	ReporterID     uuid.UUID        `gorm:"type:uuid;not null;uniqueIndex:idx_content_report_unique" json:"reporter_id"`
+	Reason         ReportReason     `gorm:"type:varchar(30);not null" json:"reason"`
+	Details        string           `gorm:"type:text" json:"details"`
+	Status         ReportStatus     `gorm:"type:varchar(20);not null;default:'OPEN'" json:"status"`
+	ResolutionNote string           `gorm:"type:text" json:"resolution_note,omitempty"`
+	ResolvedBy     *uuid.UUID       `gorm:"type:uuid" json:"resolved_by,omitempty"`
+	ResolvedAt     *time.Time       `json:"resolved_at,omitempty"`
+	CreatedAt      time.Time        `json:"created_at"`
+	UpdatedAt      time.Time        `json:"updated_at"`
+
+	Reporter *User `gorm:"foreignKey:ReporterID" json:"reporter,omitempty"`
+	// Target and OpenReports are filled in for the triage queue
+	Target      *ReportTarget `gorm:"-" json:"target,omitempty"`
+	OpenReports int64         `gorm:"-" json:"open_reports"`
+}
+
+// ReportTarget is a short view of the reported item for moderators
+type ReportTarget struct {
+	Title     string     `json:"title"`
+	Excerpt   string     `json:"excerpt,omitempty"`
+	AuthorID  uuid.UUID  `json:"author_id"`
+	ArticleID uuid.UUID  `json:"article_id"`
+	Slug      string     `json:"slug,omitempty"`
+	Status    string     `json:"status"`
+	Deleted   bool       `json:"deleted,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+}
+
+// ReportFilter narrows the triage queue
+type ReportFilter struct {
+	Status     ReportStatus
+	TargetType ReportTargetType
+	TargetID   *uuid.UUID
+	Reason     ReportReason
+	// MostReported lists items with the most open reports first instead of oldest first
+	MostReported bool
+}
+
+// ReportResult is the outcome of filing a report
+type ReportResult struct {
+	Report *ContentReport `json:"report"`
+	// AutoHidden is set when this report pushed the item over the auto-hide threshold
+	AutoHidden bool `json:"-"`
+	// HiddenComment is the comment that was hidden, for live updates
+	HiddenComment *Comment `json:"-"`
+}
+
+// ReportResolution is a bulk triage decision
+type ReportResolution struct {
+	IDs    []uuid.UUID
+	Status ReportStatus
+	Note   string
+}
+
+type ReportRepository interface {
+	Create(report *ContentReport) error
+	GetByIDs(ids []uuid.UUID) ([]ContentReport, error)
+	List(filter ReportFilter, offset, limit int) ([]ContentReport, int64, error)
+	CountOpen(targetType ReportTargetType, targetID uuid.UUID) (int64, error)
+	// CountOpenByTargets returns open report counts keyed by target ID
+	CountOpenByTargets(targetIDs []uuid.UUID) (map[uuid.UUID]int64, error)
+	CountByReporterSince(reporterID uuid.UUID, since time.Time) (int64, error)
+	Resolve(ids []uuid.UUID, status ReportStatus, note string, moderatorID uuid.UUID, at time.Time) error
+	// ResolveOpenForTargets closes the open reports of items a moderator has decided on
+	ResolveOpenForTargets(targetType ReportTargetType, targetIDs []uuid.UUID, status ReportStatus, note string, moderatorID uuid.UUID, at time.Time) (int64, error)
+	// ModeratorIDs lists active users allowed to triage reports
+	ModeratorIDs() ([]uuid.UUID, error)
+}
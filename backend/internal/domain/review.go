@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Written reviews are the optional text of an ArticleRating, so each user has at
+// most one per article. They go through the same moderation statuses as comments.
+
+const (
+	// DefaultReviewMaxLength applies when review_max_length is unset
+	DefaultReviewMaxLength = 2000
+	MinReviewLength        = 10
+	// DefaultTopReviews is the size of the article's top reviews block when top_reviews_count is unset
+	DefaultTopReviews = 3
+)
+
+// ReviewSort orders a review listing
+type ReviewSort string
+
+const (
+	ReviewSortHelpful ReviewSort = "helpful"
+	ReviewSortNewest  ReviewSort = "newest"
+	ReviewSortHighest ReviewSort = "highest"
+)
+
+// IsValid reports whether s is a known review order
+func (s ReviewSort) IsValid() bool {
+	return s == ReviewSortHelpful || s == ReviewSortNewest || s == ReviewSortHighest
+}
+
+// ReviewVote is one user's helpful / not helpful vote on a review
+type ReviewVote struct {
+	RatingID  uuid.UUID `gorm:"type:uuid;primaryKey" json:"rating_id"`
+	UserID    uuid.UUID `gorm:"type:uuid;primaryKey" json:"user_id"`
+	Helpful   bool      `gorm:"not null" json:"helpful"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ReviewFilter narrows the admin review moderation queue
+type ReviewFilter struct {
+	Status    CommentStatus
+	ArticleID *uuid.UUID
+	Search    string
+}
+
+// ReviewQueueItem is a review in the moderation queue with the moderation fields
+// readers don't see
+type ReviewQueueItem struct {
+	ArticleRating
+	ModeratedBy *uuid.UUID `json:"moderated_by,omitempty"`
+	SpamScore   float64    `json:"spam_score"`
+}
+
+// ReviewModerationAction is a bulk review moderation request
+type ReviewModerationAction struct {
+	IDs    []uuid.UUID
+	Status CommentStatus
+	Reason string
+}
+
+// ReviewModerationResult reports the outcome of a bulk review moderation request
+type ReviewModerationResult struct {
+	Updated  []uuid.UUID `json:"updated"`
+	NotFound []uuid.UUID `json:"not_found"`
+	// Changes lets the caller notify the reviewers; not serialised
+	Changes []ArticleRating `json:"-"`
+}
+
+// TopReviewsProvider fills the top reviews block of the article payload
+type TopReviewsProvider interface {
+	TopReviews(articleID uuid.UUID) ([]ArticleRating, error)
+}
@@ -0,0 +1,85 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SavedSearchFrequency controls how new matches of a saved search are delivered
+type SavedSearchFrequency string
+
+const (
+	SavedSearchInstant SavedSearchFrequency = "INSTANT" // pushed on every worker run
+	SavedSearchDaily   SavedSearchFrequency = "DAILY"   // collected into one digest per day
+	SavedSearchNone    SavedSearchFrequency = "NONE"    // saved for reuse, no alerts
+)
+
+// SavedSearch is a search query (plus filters) a user asked to be alerted about.
+// Alerts use the same semantics as GET /articles/search restricted to PUBLISHED articles.
+type SavedSearch struct {
+	ID           uuid.UUID            `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	UserID       uuid.UUID            `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name         string               `gorm:"type:varchar(100);not null" json:"name"`
+	Query        string               `gorm:"type:varchar(200);not null" json:"query"`
+	CategoryID   *uuid.UUID           `gorm:"type:uuid" json:"category_id"`
+	Frequency    SavedSearchFrequency `gorm:"type:varchar(20);not null;default:'INSTANT'" json:"frequency"`
+	LastRunAt    *time.Time           `json:"last_run_at"`
+	LastDigestAt *time.Time           `json:"last_digest_at"`
+	CreatedAt    time.Time            `gorm:"default:now()" json:"created_at"`
+	UpdatedAt    time.Time            `gorm:"default:now()" json:"updated_at"`
+
+	// Computed
+	UnreadCount int64 `gorm:"-" json:"unread_count"`
+}
+
+// Filters returns the SearchArticles filter map for this saved search
+func (s *SavedSearch) Filters() map[string]interface{} {
+	filters := map[string]interface{}{"status": string(StatusPublished)}
+	if s.CategoryID != nil {
+		filters["category_id"] = s.CategoryID.String()
+	}
+	return filters
+}
+
+// SavedSearchMatch records an article found by a saved search. DeliveredAt is
+// set once the user has been notified (instantly or through the digest).
+type SavedSearchMatch struct {
+	ID            uuid.UUID  `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	SavedSearchID uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_saved_search_match" json:"saved_search_id"`
+	ArticleID     uuid.UUID  `gorm:"type:uuid;not null;uniqueIndex:idx_saved_search_match" json:"article_id"`
+	Article       *Article   `gorm:"foreignKey:ArticleID" json:"article,omitempty"`
+	MatchedAt     time.Time  `gorm:"default:now()" json:"matched_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+	ReadAt        *time.Time `json:"read_at"`
+}
+
+type SavedSearchRepository interface {
+	Create(search *SavedSearch) error
+	Update(search *SavedSearch) error
+	Delete(id uuid.UUID) error
+	GetByID(id uuid.UUID) (*SavedSearch, error)
+	ListByUser(userID uuid.UUID) ([]SavedSearch, error)
+	CountByUser(userID uuid.UUID) (int64, error)
+	// ListAlerting returns every saved search whose frequency is not NONE
+	ListAlerting() ([]SavedSearch, error)
+	MarkRun(id uuid.UUID, at time.Time) error
+	MarkDigested(ids []uuid.UUID, at time.Time) error
+
+	// AddMatches inserts matches, skipping articles already recorded for the search, and returns the new rows
+	AddMatches(searchID uuid.UUID, articleIDs []uuid.UUID) ([]SavedSearchMatch, error)
+	GetMatches(searchID uuid.UUID, offset, limit int) ([]SavedSearchMatch, int64, error)
+	GetUndelivered(searchIDs []uuid.UUID) ([]SavedSearchMatch, error)
+	MarkDelivered(matchIDs []uuid.UUID, at time.Time) error
+	MarkRead(searchID uuid.UUID, at time.Time) error
+}
+
+type SavedSearchService interface {
+	Create(userID uuid.UUID, search *SavedSearch) error
+	List(userID uuid.UUID) ([]SavedSearch, error)
+	Get(userID, id uuid.UUID) (*SavedSearch, error)
+	Update(userID uuid.UUID, search *SavedSearch) error
+	Delete(userID, id uuid.UUID) error
+	// GetMatches lists articles found by the saved search, newest first, and marks them read
+	GetMatches(userID, id uuid.UUID, page, limit int) ([]SavedSearchMatch, int64, error)
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SearchIndex abstracts the full-text search backend used by SearchArticles.
+// Implementations must be safe for concurrent use.
+type SearchIndex interface {
+	// Name identifies the backend ("postgres", "embedded") for logs and admin views
+	Name() string
+
+	// Search returns a page of hydrated articles ordered by relevance plus the total hit count.
+	// Supported filters: "status" (string, default PUBLISHED), "category_id" (string) and
+	// "published_after" (time.Time, exclusive).
+	Search(query string, offset, limit int, filters map[string]interface{}) ([]Article, int64, error)
+
+	// IndexArticle adds or replaces a single article in the index
+	IndexArticle(article *Article) error
+
+	// RemoveArticle drops an article from the index
+	RemoveArticle(id uuid.UUID) error
+
+	// BeginRebuild starts a full rebuild. The current index keeps serving searches
+	// until the build is committed.
+	BeginRebuild() (SearchIndexBuild, error)
+
+	// Flush persists any pending changes
+	Flush() error
+
+	// Close stops background work and persists pending changes
+	Close() error
+}
+
+// SearchIndexBuild collects every article for a rebuild started with BeginRebuild
+type SearchIndexBuild interface {
+	IndexArticle(article *Article) error
+
+	// Commit replaces the live index with the build
+	Commit() error
+
+	// Discard drops a failed build and leaves the live index as it was
+	Discard()
+}
+
+// SearchReindexStatus describes the state of the admin "reindex all" job
+type SearchReindexStatus struct {
+	Backend     string     `json:"backend"`
+	Running     bool       `json:"running"`
+	Total       int64      `json:"total"`
+	Processed   int64      `json:"processed"`
+	Failed      int64      `json:"failed"`
+	StartedAt   *time.Time `json:"started_at"`
+	FinishedAt  *time.Time `json:"finished_at"`
+	RequestedBy uuid.UUID  `json:"requested_by"`
+	Error       string     `json:"error,omitempty"`
+}
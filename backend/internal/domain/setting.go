@@ -3,6 +3,8 @@ package domain
 import (
 	"database/sql/driver"
 	"errors"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -49,3 +51,66 @@ type SettingService interface {
 	GetSettings() (map[string]interface{}, error)
 	UpdateSettings(settings map[string]interface{}, userID uuid.UUID) error
 }
+
+// SettingInt reads an integer setting, accepting JSON numbers and numeric strings
+func SettingInt(settings map[string]interface{}, key string, def int) int {
+	switch v := settings[key].(type) {
+	case float64:
+		return int(v)
+	case int:
+		return v
+	case string:
+		if i, err := strconv.Atoi(v); err == nil {
+			return i
+		}
+	}
+	return def
+}
+
+// SettingFloat reads a numeric setting, accepting JSON numbers and numeric strings
+func SettingFloat(settings map[string]interface{}, key string, def float64) float64 {
+	switch v := settings[key].(type) {
+	case float64:
+		return v
+	case int:
+		return float64(v)
+	case string:
+		if f, err := strconv.ParseFloat(v, 64); err == nil {
+			return f
+		}
+	}
+	return def
+}
+
+// SettingBool reads a boolean setting, accepting JSON booleans and "true"/"false" strings
+func SettingBool(settings map[string]interface{}, key string, def bool) bool {
+	switch v := settings[key].(type) {
+	case bool:
+		return v
+	case string:
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return def
+}
+
+// SettingStrings reads a list setting stored either as a JSON array or a comma/newline separated string
+func SettingStrings(settings map[string]interface{}, key string) []string {
+	var out []string
+	switch v := settings[key].(type) {
+	case []interface{}:
+		for _, item := range v {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				out = append(out, strings.TrimSpace(s))
+			}
+		}
+	case string:
+		for _, s := range strings.FieldsFunc(v, func(r rune) bool { return r == ',' || r == '\n' }) {
+			if s = strings.TrimSpace(s); s != "" {
+				out = append(out, s)
+			}
+		}
+	}
+	return out
+}
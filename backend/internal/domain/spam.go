@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"github.com/google/uuid"
+)
+
+// SpamCheckInput is what spam checkers see of a new comment
+type SpamCheckInput struct {
+	Content   string
+	UserID    uuid.UUID
+	ArticleID uuid.UUID
+}
+
+// SpamReason explains one checker's contribution to a verdict
+type SpamReason struct {
+	Check  string  `json:"check"`
+	Score  float64 `json:"score"`
+	Detail string  `json:"detail"`
+}
+
+// SpamVerdict is a spam probability in [0, 1] with the reasons behind it.
+// It feeds the moderation queue; it is never a hard rejection on its own.
+type SpamVerdict struct {
+	Score   float64      `json:"score"`
+	Reasons []SpamReason `json:"reasons"`
+}
+
+// SpamChecker scores comment content for spam
+type SpamChecker interface {
+	Name() string
+	Check(input SpamCheckInput) (SpamVerdict, error)
+}
+
+// SpamTrainer is implemented by checkers that learn from moderator spam/ham decisions
+type SpamTrainer interface {
+	Train(content string, spam bool) error
+}
+
+// SpamToken holds naive-Bayes token counts learned from moderation decisions
+type SpamToken struct {
+	Token     string `gorm:"type:varchar(100);primaryKey" json:"token"`
+	SpamCount int64  `gorm:"not null;default:0" json:"spam_count"`
+	HamCount  int64  `gorm:"not null;default:0" json:"ham_count"`
+}
+
+type SpamTokenRepository interface {
+	GetAll() ([]SpamToken, error)
+	// Increment adds one to the spam or ham count of every token, creating missing rows
+	Increment(tokens []string, spam bool) error
+}
@@ -30,6 +30,9 @@ type AnalyticsData struct {
 	Date     string `json:"date"`
 	Articles int64  `json:"articles"`
 	Views    int64  `json:"views"`
+	Uniques  int64  `json:"uniques"`
+	Comments int64  `json:"comments"`
+	Ratings  int64  `json:"ratings"`
 }
 
 type CategoryDistribution struct {
@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TrafficChannel is the normalized origin of a view
+type TrafficChannel string
+
+const (
+	ChannelDirect   TrafficChannel = "DIRECT"
+	ChannelInternal TrafficChannel = "INTERNAL"
+	ChannelSearch   TrafficChannel = "SEARCH"
+	ChannelSocial   TrafficChannel = "SOCIAL"
+	ChannelEmail    TrafficChannel = "EMAIL"
+	ChannelPaid     TrafficChannel = "PAID"
+	ChannelReferral TrafficChannel = "REFERRAL"
+	// ChannelCampaign is tagged traffic whose UTM source and medium are not recognised
+	ChannelCampaign TrafficChannel = "CAMPAIGN"
+)
+
+// DeviceClass is the form factor parsed from a user agent
+type DeviceClass string
+
+const (
+	DeviceDesktop DeviceClass = "DESKTOP"
+	DeviceMobile  DeviceClass = "MOBILE"
+	DeviceTablet  DeviceClass = "TABLET"
+	DeviceOther   DeviceClass = "OTHER"
+)
+
+// ViewVisit is what the client reports with a view. Referrer is document.referrer
+// of the article page and SiteHost the host the page was served from, used to tell
+// internal navigation apart.
+type ViewVisit struct {
+	IPAddress   string
+	UserAgent   string
+	Referrer    string
+	UTMSource   string
+	UTMMedium   string
+	UTMCampaign string
+	SiteHost    string
+}
+
+// ViewSource is the normalized attribution stored on each view. Only the referrer
+// host is kept, never the full URL.
+type ViewSource struct {
+	Channel      TrafficChannel `gorm:"type:varchar(20);not null;default:DIRECT" json:"channel"`
+	ReferrerHost string         `gorm:"type:varchar(255)" json:"referrer_host,omitempty"`
+	UTMSource    string         `gorm:"column:utm_source;type:varchar(100)" json:"utm_source,omitempty"`
+	UTMMedium    string         `gorm:"column:utm_medium;type:varchar(100)" json:"utm_medium,omitempty"`
+	UTMCampaign  string         `gorm:"column:utm_campaign;type:varchar(100)" json:"utm_campaign,omitempty"`
+	DeviceClass  DeviceClass    `gorm:"type:varchar(20);not null;default:OTHER" json:"device_class"`
+	Browser      string         `gorm:"type:varchar(50)" json:"browser,omitempty"`
+	OS           string         `gorm:"column:os;type:varchar(50)" json:"os,omitempty"`
+}
+
+// ViewSourceResolver normalizes a visit's referrer, UTM tags and user agent
+type ViewSourceResolver interface {
+	Resolve(visit ViewVisit) ViewSource
+}
+
+// ViewSourceGroup is a column views can be broken down by
+type ViewSourceGroup string
+
+const (
+	SourceByChannel  ViewSourceGroup = "channel"
+	SourceByReferrer ViewSourceGroup = "referrer"
+	SourceByCampaign ViewSourceGroup = "campaign"
+	SourceByDevice   ViewSourceGroup = "device"
+	SourceByBrowser  ViewSourceGroup = "browser"
+	SourceByOS       ViewSourceGroup = "os"
+)
+
+// ViewSourceQuery selects human views in [From, To), optionally of one article
+type ViewSourceQuery struct {
+	From      time.Time
+	To        time.Time
+	ArticleID *uuid.UUID
+	Limit     int
+}
+
+// ViewSourceCount is one row of a breakdown. Campaign rows also carry the UTM source
+// and medium they were tagged with. Uniques counts daily visitor IDs, so a reader
+// returning on another day counts again.
+type ViewSourceCount struct {
+	Key        string  `json:"key"`
+	Source     string  `json:"source,omitempty"`
+	Medium     string  `json:"medium,omitempty"`
+	Views      int64   `json:"views"`
+	Uniques    int64   `json:"uniques"`
+	AvgSession float64 `json:"avg_session"`
+}
+
+// ViewSourceStats holds the requested breakdowns keyed by group
+type ViewSourceStats struct {
+	From       time.Time                             `json:"from"`
+	To         time.Time                             `json:"to"`
+	ArticleID  *uuid.UUID                            `json:"article_id,omitempty"`
+	Breakdowns map[ViewSourceGroup][]ViewSourceCount `json:"breakdowns"`
+}
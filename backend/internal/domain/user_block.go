@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// BlockKind is how strongly one user shuts out another
+type BlockKind string
+
+const (
+	// BlockKindMute collapses the muted user's comments for the muter only
+	BlockKindMute BlockKind = "MUTE"
+	// BlockKindBlock also collapses their comments and stops them commenting on the blocker's articles
+	BlockKindBlock BlockKind = "BLOCK"
+)
+
+// IsValid reports whether k is a known block kind
+func (k BlockKind) IsValid() bool {
+	return k == BlockKindMute || k == BlockKindBlock
+}
+
+// MaxUserBlocks caps each user's list of one kind
+const MaxUserBlocks = 1000
+
+// UserBlock is one user's mute or block of another. A user may hold both kinds on the same person.
+type UserBlock struct {
+	BlockerID uuid.UUID `gorm:"type:uuid;primaryKey" json:"blocker_id"`
+	BlockedID uuid.UUID `gorm:"type:uuid;primaryKey" json:"blocked_id"`
+	Kind      BlockKind `gorm:"type:varchar(10);primaryKey" json:"kind"`
+	CreatedAt time.Time `json:"created_at"`
+
+	Blocker *User `gorm:"foreignKey:BlockerID" json:"blocker,omitempty"`
+	Blocked *User `gorm:"foreignKey:BlockedID" json:"blocked,omitempty"`
+}
+
+// UserBlockStats is how often a user is blocked or muted, for abuse investigations
+type UserBlockStats struct {
+	UserID         uuid.UUID  `json:"user_id"`
+	FullName       string     `json:"full_name"`
+	Email          string     `json:"email"`
+	BlockedByCount int64      `json:"blocked_by_count"`
+	MutedByCount   int64      `json:"muted_by_count"`
+	BlockingCount  int64      `json:"blocking_count"`
+	LastBlockedAt  *time.Time `json:"last_blocked_at"`
+}
+
+type UserBlockRepository interface {
+	// Add stores the block; adding an existing one is a no-op
+	Add(block *UserBlock) error
+	Remove(blockerID, blockedID uuid.UUID, kind BlockKind) (bool, error)
+	List(blockerID uuid.UUID, kind BlockKind, offset, limit int) ([]UserBlock, int64, error)
+	Count(blockerID uuid.UUID, kind BlockKind) (int64, error)
+	IsBlocked(blockerID, blockedID uuid.UUID) (bool, error)
+	// BlockedBy returns which of blockerIDs block blockedID
+	BlockedBy(blockedID uuid.UUID, blockerIDs []uuid.UUID) (map[uuid.UUID]bool, error)
+	// HiddenAuthors returns everyone the viewer muted or blocked
+	HiddenAuthors(viewerID uuid.UUID) (map[uuid.UUID]bool, error)
+
+	// Admin
+	Stats(offset, limit int) ([]UserBlockStats, int64, error)
+	ListReceived(blockedID uuid.UUID, offset, limit int) ([]UserBlock, int64, error)
+}
+
+type UserBlockService interface {
+	Add(blockerID, blockedID uuid.UUID, kind BlockKind) (*UserBlock, error)
+	Remove(blockerID, blockedID uuid.UUID, kind BlockKind) error
+	// List returns the user's blocks of one kind, or of both when kind is empty
+	List(blockerID uuid.UUID, kind BlockKind, page, limit int) ([]UserBlock, int64, error)
+	Stats(page, limit int) ([]UserBlockStats, int64, error)
+	ListReceived(blockedID uuid.UUID, page, limit int) ([]UserBlock, int64, error)
+}
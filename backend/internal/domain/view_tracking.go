@@ -8,12 +8,19 @@ import (
 
 // ArticleView represents a single view of an article
 type ArticleView struct {
-	ID              uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
-	ArticleID       uuid.UUID `gorm:"type:uuid;not null" json:"article_id"`
-	IPAddress       string    `gorm:"type:inet" json:"ip_address"`
+	ID        uuid.UUID `gorm:"type:uuid;primary_key;default:gen_random_uuid()" json:"id"`
+	ArticleID uuid.UUID `gorm:"type:uuid;not null" json:"article_id"`
+	// IPAddress is only kept for view_ip_retention_days; VisitorID replaces it for dedup and uniques
+	IPAddress       *string   `gorm:"type:inet" json:"ip_address,omitempty"`
+	VisitorID       string    `gorm:"type:varchar(64);index" json:"visitor_id"`
 	UserAgent       string    `gorm:"type:text" json:"user_agent"`
 	SessionDuration int       `gorm:"type:integer" json:"session_duration"` // Duration in seconds
 	ViewedAt        time.Time `gorm:"default:now()" json:"viewed_at"`
+	// Classification is set by the ViewClassifier; only HUMAN views are counted
+	Classification ViewClass `gorm:"type:varchar(20);not null;default:HUMAN;index" json:"classification"`
+	ClassReason    string    `gorm:"type:varchar(100)" json:"class_reason,omitempty"`
+	// ViewSource holds the channel, campaign and device the view came from
+	ViewSource `gorm:"embedded"`
 }
 
 // TableName specifies the table name for ArticleView
@@ -26,6 +33,43 @@ func (v *ArticleView) IsValid() bool {
 	return v.SessionDuration >= 30
 }
 
+// ViewClass labels the traffic a view came from
+type ViewClass string
+
+const (
+	ViewHuman      ViewClass = "HUMAN"
+	ViewBot        ViewClass = "BOT"
+	ViewSuspicious ViewClass = "SUSPICIOUS"
+)
+
+// ViewClassification is a classifier verdict; Reason names the rule that matched
+type ViewClassification struct {
+	Class  ViewClass `json:"class"`
+	Reason string    `json:"reason,omitempty"`
+}
+
+// ViewClassifier labels views by user agent and IP reputation
+type ViewClassifier interface {
+	Classify(ipAddress, userAgent string) ViewClassification
+}
+
+// ViewTrafficCount is one row of a traffic breakdown
+type ViewTrafficCount struct {
+	Class ViewClass `json:"class"`
+	Key   string    `json:"key,omitempty"`
+	Count int64     `json:"count"`
+}
+
+// ViewTrafficStats breaks stored views down by classification for admins
+type ViewTrafficStats struct {
+	From          time.Time          `json:"from"`
+	To            time.Time          `json:"to"`
+	ByClass       []ViewTrafficCount `json:"by_class"`
+	TopReasons    []ViewTrafficCount `json:"top_reasons"`
+	TopUserAgents []ViewTrafficCount `json:"top_user_agents"`
+	TopIPs        []ViewTrafficCount `json:"top_ips"`
+}
+
 // ViewTrackingRepository defines the interface for view tracking data access
 type ViewTrackingRepository interface {
 	// RecordView saves a new article view (only if session_duration >= 30)
@@ -34,24 +78,102 @@ type ViewTrackingRepository interface {
 	// GetValidViewCount returns the count of valid views for an article
 	GetValidViewCount(articleID uuid.UUID) (int64, error)
 
-	// HasRecentView checks if the IP address has viewed this article recently
-	HasRecentView(articleID uuid.UUID, ipAddress string, within time.Duration) (bool, error)
-
 	// GetViewsByArticle returns all valid views for an article with pagination
 	GetViewsByArticle(articleID uuid.UUID, page, limit int) ([]ArticleView, int64, error)
 
 	// GetRecentViews returns recent valid views across all articles
 	GetRecentViews(limit int) ([]ArticleView, error)
+
+	// RecordViews bulk-inserts views with multi-row INSERTs
+	RecordViews(views []ArticleView) error
+
+	// RecentViewKeys returns which of the keys already have a valid view since the given time
+	RecentViewKeys(keys []ViewKey, since time.Time) (map[ViewKey]bool, error)
+
+	// AnonymizeBefore clears the raw IPs and user agents of views older than before
+	AnonymizeBefore(before time.Time) (int64, error)
+
+	// DeleteBefore removes raw views older than before, chunk rows per statement
+	DeleteBefore(before time.Time, chunk int) (int64, error)
+
+	// CountUniqueVisitors sums the distinct human visitors of each day in [from, to),
+	// read from the daily rollups
+	CountUniqueVisitors(articleID *uuid.UUID, from, to time.Time) (int64, error)
+	// DailyUniqueVisitors returns the distinct human visitors per day in [from, to)
+	DailyUniqueVisitors(articleID *uuid.UUID, from, to time.Time) ([]DailyUniques, error)
+
+	// TrafficStats counts the views stored between from and to per class, and the
+	// most frequent reasons, user agents and IPs among filtered (non-human) views
+	TrafficStats(from, to time.Time, limit int) (*ViewTrafficStats, error)
+
+	// SourceBreakdown counts human views by one source column, most viewed first
+	SourceBreakdown(group ViewSourceGroup, q ViewSourceQuery) ([]ViewSourceCount, error)
+}
+
+// ViewKey identifies a reader of an article for deduplication. Visitor IDs change
+// every UTC day, so a reader counts once per article per day.
+type ViewKey struct {
+	ArticleID uuid.UUID
+	VisitorID string
+}
+
+// ViewDropPolicy decides what the ingestion pipeline does when its buffer is full
+type ViewDropPolicy string
+
+const (
+	// ViewDropNewest rejects the incoming view
+	ViewDropNewest ViewDropPolicy = "drop_newest"
+	// ViewDropOldest evicts the oldest buffered view to make room
+	ViewDropOldest ViewDropPolicy = "drop_oldest"
+	// ViewBlock waits briefly for room, then drops the incoming view
+	ViewBlock ViewDropPolicy = "block"
+)
+
+// ViewIngester accepts views for asynchronous, batched storage
+type ViewIngester interface {
+	// Enqueue buffers a view. It returns false when the view was dropped, and true
+	// for duplicates, which are accepted but not stored.
+	Enqueue(view ArticleView) bool
+}
+
+// ViewIngestStats reports the ingestion pipeline's throughput and backpressure
+type ViewIngestStats struct {
+	Workers       int            `json:"workers"`
+	DropPolicy    ViewDropPolicy `json:"drop_policy"`
+	QueueLength   int            `json:"queue_length"`
+	QueueCapacity int            `json:"queue_capacity"`
+	// Enqueued counts accepted views, Deduplicated those found to be repeats
+	// (in memory or in the database), Dropped those lost to a full buffer and
+	// Orphaned those whose article was deleted before they were written
+	Enqueued     int64      `json:"enqueued"`
+	Deduplicated int64      `json:"deduplicated"`
+	Dropped      int64      `json:"dropped"`
+	Orphaned     int64      `json:"orphaned"`
+	Written      int64      `json:"written"`
+	Failed       int64      `json:"failed"`
+	Batches      int64      `json:"batches"`
+	LastFlushAt  *time.Time `json:"last_flush_at,omitempty"`
+	LastError    string     `json:"last_error,omitempty"`
 }
 
 // ViewTrackingService defines the business logic interface for view tracking
 type ViewTrackingService interface {
-	// TrackView processes and records a view if it meets all criteria
-	TrackView(articleSlug string, ipAddress string, userAgent string, sessionDuration int) error
+	// TrackView validates a view and queues it for recording
+	TrackView(articleSlug string, visit ViewVisit, sessionDuration int) error
+	// TrackEngagement validates an engagement ping and buffers it
+	TrackEngagement(articleSlug string, visit ViewVisit, ping EngagementPing) error
 
 	// GetArticleViewCount returns the count of valid views for an article by slug
 	GetArticleViewCount(articleSlug string) (int64, error)
 
 	// GetArticleViewCountByID returns the count of valid views for an article by ID
 	GetArticleViewCountByID(articleID uuid.UUID) (int64, error)
+
+	GetTrafficStats(from, to time.Time) (*ViewTrafficStats, error)
+	GetUniqueVisitors(articleID *uuid.UUID) (*UniqueVisitorStats, error)
+	// GetSourceStats breaks human views down by each of the groups
+	GetSourceStats(groups []ViewSourceGroup, q ViewSourceQuery) (*ViewSourceStats, error)
+
+	// ForgetArticle drops a deleted article from the identifier cache
+	ForgetArticle(id uuid.UUID)
 }
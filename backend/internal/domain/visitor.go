@@ -0,0 +1,63 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Readers are identified by a salted hash of IP and user agent. The salt rotates
+// every UTC day and old salts are deleted, so a hash cannot be linked back to an IP
+// or to the same reader on another day. Weekly and monthly uniques are therefore
+// the sum of distinct daily visitors: a reader returning on another day counts again.
+
+// DefaultViewIPRetentionDays applies when view_ip_retention_days is unset; 0 stops
+// storing raw IPs altogether
+const DefaultViewIPRetentionDays = 7
+
+// VisitorSalt is the secret of one UTC day
+type VisitorSalt struct {
+	Day       time.Time `gorm:"type:date;primaryKey" json:"day"`
+	Salt      []byte    `gorm:"not null" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type VisitorSaltRepository interface {
+	// GetOrCreate returns the salt of day, storing candidate if the day has none yet
+	GetOrCreate(day time.Time, candidate []byte) ([]byte, error)
+	DeleteBefore(day time.Time) error
+}
+
+// VisitorIdentity is how a view is attributed: the hashed visitor ID, and whether
+// the raw IP and user agent may still be stored under the retention setting
+type VisitorIdentity struct {
+	VisitorID string
+	KeepIP    bool
+}
+
+// VisitorIdentifier derives the daily visitor ID of a request
+type VisitorIdentifier interface {
+	Identify(ipAddress, userAgent string, at time.Time) (VisitorIdentity, error)
+}
+
+// VisitorDay is the UTC day a view's visitor ID belongs to
+func VisitorDay(t time.Time) time.Time {
+	y, m, d := t.UTC().Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+}
+
+// DailyUniques is the number of distinct visitors on one day
+type DailyUniques struct {
+	Date     time.Time `json:"date"`
+	Visitors int64     `json:"visitors"`
+}
+
+// UniqueVisitorStats summarises human unique visitors, site-wide or for one article.
+// Daily, Weekly and Monthly cover the last 1, 7 and 30 days including today.
+type UniqueVisitorStats struct {
+	ArticleID *uuid.UUID     `json:"article_id,omitempty"`
+	Daily     int64          `json:"daily"`
+	Weekly    int64          `json:"weekly"`
+	Monthly   int64          `json:"monthly"`
+	Series    []DailyUniques `json:"series"`
+}
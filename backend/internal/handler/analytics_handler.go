@@ -0,0 +1,169 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/core/response"
+	"backend/internal/domain"
+	"backend/internal/worker"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type AnalyticsHandler struct {
+	service domain.AnalyticsService
+	worker  *worker.AnalyticsRollupWorker
+}
+
+func NewAnalyticsHandler(service domain.AnalyticsService, worker *worker.AnalyticsRollupWorker) *AnalyticsHandler {
+	return &AnalyticsHandler{service: service, worker: worker}
+}
+
+// GetSeries handles GET /api/admin/analytics/rollups
+// Query: dimension (SITE|ARTICLE|CATEGORY|AUTHOR), id, granularity (HOUR|DAY), from, to.
+// Without id every value of the dimension is returned.
+func (h *AnalyticsHandler) GetSeries(c *gin.Context) {
+	q, err := rollupQuery(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	rows, svcErr := h.service.GetSeries(*q)
+	if svcErr != nil {
+		c.Error(svcErr)
+		return
+	}
+	response.Success(c, rows)
+}
+
+// GetTop handles GET /api/admin/analytics/top
+// Query: dimension (ARTICLE|CATEGORY|AUTHOR), granularity, from, to, limit (default 10)
+func (h *AnalyticsHandler) GetTop(c *gin.Context) {
+	q, err := rollupQuery(c)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	rows, svcErr := h.service.GetTop(*q, limit)
+	if svcErr != nil {
+		c.Error(svcErr)
+		return
+	}
+	response.Success(c, rows)
+}
+
+// GetArticleEngagement handles GET /api/articles/:id/engagement
+// Query: from, to (default last 30 days). Completion rate, median active time,
+// scroll reach and drop-off per heading section; authors only see their own articles.
+func (h *AnalyticsHandler) GetArticleEngagement(c *gin.Context) {
+	articleID, parseErr := uuid.Parse(c.Param("id"))
+	if parseErr != nil {
+		c.Error(apperrors.NewBadRequest("ID bài viết không hợp lệ"))
+		return
+	}
+	from, to, err := queryRange(c, 30)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	stats, svcErr := h.service.GetArticleEngagement(articleID, currentUserID(c), currentRoles(c), from, to)
+	if svcErr != nil {
+		c.Error(svcErr)
+		return
+	}
+	response.Success(c, stats)
+}
+
+// GetMyAnalytics handles GET /api/me/analytics
+// Query: granularity (HOUR|DAY), from, to (default last 30 days), limit (top articles).
+// The signed-in author's own dashboard; there is no way to ask for someone else's.
+func (h *AnalyticsHandler) GetMyAnalytics(c *gin.Context) {
+	h.authorAnalytics(c, currentUserID(c))
+}
+
+// GetAuthorAnalytics handles GET /api/admin/analytics/authors/:id
+// Same query and response as GetMyAnalytics, for any author
+func (h *AnalyticsHandler) GetAuthorAnalytics(c *gin.Context) {
+	authorID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID tác giả không hợp lệ"))
+		return
+	}
+	h.authorAnalytics(c, authorID)
+}
+
+func (h *AnalyticsHandler) authorAnalytics(c *gin.Context, authorID uuid.UUID) {
+	granularity := domain.RollupGranularity(strings.ToUpper(c.DefaultQuery("granularity", string(domain.RollupDay))))
+	from, to, err := queryRange(c, 30)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	result, svcErr := h.service.GetAuthorAnalytics(authorID, granularity, from, to, limit)
+	if svcErr != nil {
+		c.Error(svcErr)
+		return
+	}
+	response.Success(c, result)
+}
+
+// RunRollup handles POST /api/admin/analytics/rollups/run
+// Aggregates up to the current hour now instead of waiting for the worker
+func (h *AnalyticsHandler) RunRollup(c *gin.Context) {
+	state, err := h.worker.RunOnce()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, state)
+}
+
+// rollupQuery reads the rollup filters; the range defaults to the last 30 days
+func rollupQuery(c *gin.Context) (*domain.RollupQuery, *apperrors.AppError) {
+	q := &domain.RollupQuery{
+		Granularity: domain.RollupGranularity(strings.ToUpper(c.DefaultQuery("granularity", string(domain.RollupDay)))),
+		Dimension:   domain.RollupDimension(strings.ToUpper(c.DefaultQuery("dimension", string(domain.RollupSite)))),
+	}
+	id, err := queryUUID(c, "id")
+	if err != nil {
+		return nil, err
+	}
+	q.DimensionID = id
+
+	from, to, err := queryRange(c, 30)
+	if err != nil {
+		return nil, err
+	}
+	q.From, q.To = from, to
+	return q, nil
+}
+
+// queryRange reads from / to, defaulting to the given number of days up to now
+func queryRange(c *gin.Context, defaultDays int) (time.Time, time.Time, *apperrors.AppError) {
+	from, err := queryTime(c, "from", false)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	to, err := queryTime(c, "to", true)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	end := time.Now().UTC()
+	if to != nil {
+		end = *to
+	}
+	start := end.AddDate(0, 0, -defaultDays)
+	if from != nil {
+		start = *from
+	}
+	if !start.Before(end) {
+		return time.Time{}, time.Time{}, apperrors.NewBadRequest("Khoảng thời gian không hợp lệ")
+	}
+	return start, end, nil
+}
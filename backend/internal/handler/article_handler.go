@@ -20,14 +20,16 @@ import (
 
 type ArticleHandler struct {
 	service        domain.ArticleService
+	views          domain.ViewTrackingService
 	imageProcessor *utils.ImageProcessor
 	cache          *middleware.ResponseCache
 	hub            *ws.Hub
 }
 
-func NewArticleHandler(service domain.ArticleService, cache *middleware.ResponseCache, hub *ws.Hub) *ArticleHandler {
+func NewArticleHandler(service domain.ArticleService, views domain.ViewTrackingService, cache *middleware.ResponseCache, hub *ws.Hub) *ArticleHandler {
 	return &ArticleHandler{
 		service:        service,
+		views:          views,
 		imageProcessor: utils.NewImageProcessor(),
 		cache:          cache,
 		hub:            hub,
@@ -36,18 +38,20 @@ func NewArticleHandler(service domain.ArticleService, cache *middleware.Response
 
 // ArticleCreateRequest represents the request DTO with base64 images
 type ArticleCreateRequest struct {
-	Title             string               `json:"title" binding:"required"`
-	Content           string               `json:"content" binding:"required"`
-	Summary           string               `json:"summary" binding:"required"`
-	Slug              string               `json:"slug"`
-	CategoryID        *uuid.UUID           `json:"category_id" binding:"required"`
-	Status            domain.ArticleStatus `json:"status"`
-	IsFeatured        bool                 `json:"is_featured"`
-	AllowComment      bool                 `json:"allow_comment"`
-	Tags              []domain.Tag         `json:"tags" binding:"required,min=1"`
-	Images            []Base64Image        `json:"images" binding:"required,min=1,max=30"` // base64 images, max 30 limit
-	SeoMetadata       *domain.SeoMetadata  `json:"seo_metadata"`
-	RelatedArticleIDs []uuid.UUID          `json:"related_article_ids"`
+	Title             string                `json:"title" binding:"required"`
+	Content           string                `json:"content" binding:"required"`
+	Summary           string                `json:"summary" binding:"required"`
+	Slug              string                `json:"slug"`
+	CategoryID        *uuid.UUID            `json:"category_id" binding:"required"`
+	Status            domain.ArticleStatus  `json:"status"`
+	IsFeatured        bool                  `json:"is_featured"`
+	AllowComment      bool                  `json:"allow_comment"`
+	CommentPolicy     *domain.CommentPolicy `json:"comment_policy"` // nil inherits from the category
+	Language          string                `json:"language" binding:"omitempty,max=10"`
+	Tags              []domain.Tag          `json:"tags" binding:"required,min=1"`
+	Images            []Base64Image         `json:"images" binding:"required,min=1,max=30"` // base64 images, max 30 limit
+	SeoMetadata       *domain.SeoMetadata   `json:"seo_metadata"`
+	RelatedArticleIDs []uuid.UUID           `json:"related_article_ids"`
 }
 
 type Base64Image struct {
@@ -64,16 +68,22 @@ func (h *ArticleHandler) CreateArticle(c *gin.Context) {
 		c.Error(apperrors.TranslateValidationError(err))
 		return
 	}
+	if err := req.CommentPolicy.Normalize(); err != nil {
+		c.Error(apperrors.NewBadRequest(err.Error()))
+		return
+	}
 
 	// Create article first to get ID
 	article := domain.Article{
-		Title:        req.Title,
-		Content:      req.Content,
-		Summary:      req.Summary,
-		Slug:         req.Slug,
-		Status:       req.Status,
-		IsFeatured:   req.IsFeatured,
-		AllowComment: req.AllowComment,
+		Title:         req.Title,
+		Content:       req.Content,
+		Summary:       req.Summary,
+		Slug:          req.Slug,
+		Status:        req.Status,
+		IsFeatured:    req.IsFeatured,
+		AllowComment:  req.AllowComment,
+		CommentPolicy: req.CommentPolicy,
+		Language:      req.Language,
 	}
 
 	if req.CategoryID != nil {
@@ -261,27 +271,32 @@ func (h *ArticleHandler) GetArticles(c *gin.Context) {
 		page = 1
 	}
 
-	filter := make(map[string]interface{})
-	if status := c.Query("status"); status != "" {
-		filter["status"] = status
-	}
-	if search := c.Query("search"); search != "" {
-		filter["search"] = search
-	}
-	if categoryID := c.Query("category_id"); categoryID != "" {
-		filter["category_id"] = categoryID
-	}
-	if tagID := c.Query("tag_id"); tagID != "" {
-		filter["tag_id"] = tagID
-	}
-	if minimal := c.Query("minimal"); minimal == "true" {
-		filter["minimal"] = true
+	query, appErr := parseArticleQuery(c)
+	if appErr != nil {
+		c.Error(appErr)
+		return
 	}
-	if sort := c.Query("sort"); sort != "" {
-		filter["sort"] = sort
+
+	// Keyset pagination for infinite scroll (?after=<cursor>)
+	if req, ok := cursorRequest(c, limit); ok {
+		result, err := h.service.GetArticlesByCursor(req, query)
+		if err != nil {
+			c.Error(paginationError(err))
+			return
+		}
+		meta := gin.H{
+			"limit":       limit,
+			"next_cursor": result.Cursor.NextCursor,
+			"has_more":    result.Cursor.HasMore,
+		}
+		if result.Cursor.TotalKnown {
+			meta["total"] = result.Pagination.TotalRows
+		}
+		response.SuccessWithMeta(c, result.Data, meta)
+		return
 	}
 
-	articles, total, err := h.service.GetArticles(page, limit, filter)
+	articles, total, err := h.service.GetArticles(page, limit, query)
 	if err != nil {
 		c.Error(apperrors.NewInternalError(err))
 		return
@@ -342,6 +357,10 @@ func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 		c.Error(apperrors.TranslateValidationError(err))
 		return
 	}
+	if err := req.CommentPolicy.Normalize(); err != nil {
+		c.Error(apperrors.NewBadRequest(err.Error()))
+		return
+	}
 
 	// Get existing article
 	article, err := h.service.GetArticleByID(id)
@@ -365,6 +384,10 @@ func (h *ArticleHandler) UpdateArticle(c *gin.Context) {
 	article.Status = req.Status
 	article.IsFeatured = req.IsFeatured
 	article.AllowComment = req.AllowComment
+	article.CommentPolicy = req.CommentPolicy
+	if req.Language != "" {
+		article.Language = req.Language
+	}
 
 	if req.CategoryID != nil {
 		article.CategoryID = *req.CategoryID
@@ -549,7 +572,8 @@ func (h *ArticleHandler) DeleteArticle(c *gin.Context) {
 		return
 	}
 
-	// Invalidate cache and broadcast update
+	// Invalidate caches and broadcast update
+	h.views.ForgetArticle(id)
 	h.cache.ClearByPrefix("/api/v1/articles")
 	h.cache.ClearByPrefix("/api/v1/admin")
 	h.hub.BroadcastEvent("admin_data_updated", gin.H{"module": "articles", "action": "delete"})
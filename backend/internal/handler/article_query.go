@@ -0,0 +1,258 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const maxArticleQueryIDs = 20
+
+// legacySortAliases keeps the old single-word ?sort= values working
+var legacySortAliases = map[string]domain.ArticleSortKey{
+	"newest":     {Field: domain.ArticleSortCreatedAt, Desc: true},
+	"oldest":     {Field: domain.ArticleSortCreatedAt, Desc: false},
+	"published":  {Field: domain.ArticleSortPublishedAt, Desc: true},
+	"views":      {Field: domain.ArticleSortViews, Desc: true},
+	"comments":   {Field: domain.ArticleSortComments, Desc: true},
+	"engagement": {Field: domain.ArticleSortEngagement, Desc: true},
+	"rating":     {Field: domain.ArticleSortRating, Desc: true},
+	"top_rated":  {Field: domain.ArticleSortRatingScore, Desc: true},
+}
+
+var articleSortFields = map[string]bool{
+	domain.ArticleSortCreatedAt:   true,
+	domain.ArticleSortPublishedAt: true,
+	domain.ArticleSortUpdatedAt:   true,
+	domain.ArticleSortTitle:       true,
+	domain.ArticleSortViews:       true,
+	domain.ArticleSortComments:    true,
+	domain.ArticleSortRating:      true,
+	domain.ArticleSortRatingScore: true,
+	domain.ArticleSortEngagement:  true,
+}
+
+// parseArticleQuery reads the article list filters from the query string:
+//
+//	status, search, is_featured, minimal,
+//	author_id (repeatable or comma separated), category_id, include_subcategories,
+//	tag_id (repeatable or comma separated), tag_match=any|all,
+//	created_from/created_to, published_from/published_to, updated_from/updated_to
+//	(RFC3339 or YYYY-MM-DD; a bare "to" date covers the whole day),
+//	min_rating/max_rating, min_views/max_views, has_image, language,
+//	sort=-published_at,title (prefix "-" for descending)
+func parseArticleQuery(c *gin.Context) (domain.ArticleQuery, *apperrors.AppError) {
+	var q domain.ArticleQuery
+	var err *apperrors.AppError
+
+	if status := c.Query("status"); status != "" {
+		q.Status = domain.ArticleStatus(strings.ToUpper(status))
+		switch q.Status {
+		case domain.StatusDraft, domain.StatusReview, domain.StatusPublished, domain.StatusScheduled, domain.StatusArchived:
+		default:
+			return q, apperrors.NewBadRequest(fmt.Sprintf("Trạng thái không hợp lệ: %s", status))
+		}
+	}
+	q.Search = strings.TrimSpace(c.Query("search"))
+	q.Minimal = c.Query("minimal") == "true"
+	q.Language = strings.TrimSpace(c.Query("language"))
+	if len(q.Language) > 10 {
+		return q, apperrors.NewBadRequest("Mã ngôn ngữ không hợp lệ")
+	}
+
+	if q.IsFeatured, err = queryBool(c, "is_featured"); err != nil {
+		return q, err
+	}
+	if q.HasImage, err = queryBool(c, "has_image"); err != nil {
+		return q, err
+	}
+
+	if q.AuthorIDs, err = queryUUIDs(c, "author_id"); err != nil {
+		return q, err
+	}
+	if q.TagIDs, err = queryUUIDs(c, "tag_id"); err != nil {
+		return q, err
+	}
+	switch mode := domain.TagMatchMode(strings.ToLower(c.DefaultQuery("tag_match", string(domain.TagMatchAny)))); mode {
+	case domain.TagMatchAny, domain.TagMatchAll:
+		q.TagMatch = mode
+	default:
+		return q, apperrors.NewBadRequest("tag_match phải là 'any' hoặc 'all'")
+	}
+
+	if raw := c.Query("category_id"); raw != "" {
+		id, parseErr := uuid.Parse(raw)
+		if parseErr != nil {
+			return q, apperrors.NewBadRequest("category_id không hợp lệ")
+		}
+		q.CategoryID = &id
+	}
+	q.IncludeSubcategories = c.Query("include_subcategories") == "true"
+
+	ranges := []struct {
+		from, to string
+		dstFrom  **time.Time
+		dstTo    **time.Time
+	}{
+		{"created_from", "created_to", &q.CreatedFrom, &q.CreatedTo},
+		{"published_from", "published_to", &q.PublishedFrom, &q.PublishedTo},
+		{"updated_from", "updated_to", &q.UpdatedFrom, &q.UpdatedTo},
+	}
+	for _, r := range ranges {
+		if *r.dstFrom, err = queryTime(c, r.from, false); err != nil {
+			return q, err
+		}
+		if *r.dstTo, err = queryTime(c, r.to, true); err != nil {
+			return q, err
+		}
+		if *r.dstFrom != nil && *r.dstTo != nil && (*r.dstFrom).After(**r.dstTo) {
+			return q, apperrors.NewBadRequest(fmt.Sprintf("%s phải trước %s", r.from, r.to))
+		}
+	}
+
+	if q.MinRating, err = queryFloat(c, "min_rating", 0, 5); err != nil {
+		return q, err
+	}
+	if q.MaxRating, err = queryFloat(c, "max_rating", 0, 5); err != nil {
+		return q, err
+	}
+	if q.MinRating != nil && q.MaxRating != nil && *q.MinRating > *q.MaxRating {
+		return q, apperrors.NewBadRequest("min_rating không được lớn hơn max_rating")
+	}
+	if q.MinViews, err = queryNonNegativeInt(c, "min_views"); err != nil {
+		return q, err
+	}
+	if q.MaxViews, err = queryNonNegativeInt(c, "max_views"); err != nil {
+		return q, err
+	}
+	if q.MinViews != nil && q.MaxViews != nil && *q.MinViews > *q.MaxViews {
+		return q, apperrors.NewBadRequest("min_views không được lớn hơn max_views")
+	}
+
+	if q.Sort, err = parseArticleSort(c.Query("sort")); err != nil {
+		return q, err
+	}
+
+	return q, nil
+}
+
+// parseArticleSort parses "-published_at,title" into sort keys
+func parseArticleSort(raw string) ([]domain.ArticleSortKey, *apperrors.AppError) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, nil
+	}
+	if alias, ok := legacySortAliases[raw]; ok {
+		return []domain.ArticleSortKey{alias}, nil
+	}
+
+	var keys []domain.ArticleSortKey
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key := domain.ArticleSortKey{Field: part}
+		if strings.HasPrefix(part, "-") {
+			key = domain.ArticleSortKey{Field: part[1:], Desc: true}
+		} else if strings.HasPrefix(part, "+") {
+			key.Field = part[1:]
+		}
+		if !articleSortFields[key.Field] {
+			return nil, apperrors.NewBadRequest(fmt.Sprintf("Không thể sắp xếp theo trường: %s", key.Field))
+		}
+		if seen[key.Field] {
+			return nil, apperrors.NewBadRequest(fmt.Sprintf("Trường sắp xếp bị lặp: %s", key.Field))
+		}
+		seen[key.Field] = true
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+func queryBool(c *gin.Context, name string) (*bool, *apperrors.AppError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseBool(raw)
+	if err != nil {
+		return nil, apperrors.NewBadRequest(fmt.Sprintf("%s phải là true hoặc false", name))
+	}
+	return &v, nil
+}
+
+// queryUUIDs accepts both ?name=a&name=b and ?name=a,b
+func queryUUIDs(c *gin.Context, name string) ([]uuid.UUID, *apperrors.AppError) {
+	var ids []uuid.UUID
+	for _, raw := range c.QueryArray(name) {
+		for _, part := range strings.Split(raw, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			id, err := uuid.Parse(part)
+			if err != nil {
+				return nil, apperrors.NewBadRequest(fmt.Sprintf("%s không hợp lệ: %s", name, part))
+			}
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) > maxArticleQueryIDs {
+		return nil, apperrors.NewBadRequest(fmt.Sprintf("Tối đa %d giá trị cho %s", maxArticleQueryIDs, name))
+	}
+	return ids, nil
+}
+
+// queryTime parses RFC3339 or a plain date. A plain date used as an upper
+// bound is extended to the end of that day so the range stays inclusive.
+func queryTime(c *gin.Context, name string, endOfDay bool) (*time.Time, *apperrors.AppError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	if t, err := time.Parse(time.RFC3339, raw); err == nil {
+		return &t, nil
+	}
+	t, err := time.Parse("2006-01-02", raw)
+	if err != nil {
+		return nil, apperrors.NewBadRequest(fmt.Sprintf("%s phải có định dạng YYYY-MM-DD hoặc RFC3339", name))
+	}
+	if endOfDay {
+		t = t.Add(24*time.Hour - time.Nanosecond)
+	}
+	return &t, nil
+}
+
+func queryFloat(c *gin.Context, name string, min, max float64) (*float64, *apperrors.AppError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseFloat(raw, 64)
+	// NaN fails every comparison, so it is rejected explicitly
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) || v < min || v > max {
+		return nil, apperrors.NewBadRequest(fmt.Sprintf("%s phải nằm trong khoảng %g-%g", name, min, max))
+	}
+	return &v, nil
+}
+
+func queryNonNegativeInt(c *gin.Context, name string) (*int, *apperrors.AppError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	v, err := strconv.Atoi(raw)
+	if err != nil || v < 0 {
+		return nil, apperrors.NewBadRequest(fmt.Sprintf("%s phải là số nguyên không âm", name))
+	}
+	return &v, nil
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"backend/internal/domain"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -32,6 +33,30 @@ func (h *AuditHandler) GetAuditLogs(c *gin.Context) {
 		filter["search"] = search
 	}
 
+	// Keyset pagination keeps pages stable while new entries are being written
+	if req, ok := cursorRequest(c, limit); ok {
+		result, err := h.service.GetAuditLogsByCursor(req, filter)
+		if err != nil {
+			if errors.Is(err, domain.ErrInvalidCursor) {
+				c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		body := gin.H{
+			"data":        result.Data,
+			"limit":       result.Pagination.Limit,
+			"next_cursor": result.Cursor.NextCursor,
+			"has_more":    result.Cursor.HasMore,
+		}
+		if result.Cursor.TotalKnown {
+			body["total"] = result.Pagination.TotalRows
+		}
+		c.JSON(http.StatusOK, body)
+		return
+	}
+
 	logs, total, err := h.service.GetAuditLogs(page, limit, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -4,6 +4,7 @@ import (
 	"backend/internal/domain"
 	"backend/internal/service"
 	"backend/internal/ws"
+	"errors"
 	"net/http"
 	"strconv"
 
@@ -12,14 +13,16 @@ import (
 )
 
 type CommentHandler struct {
-	service service.CommentService
-	hub     *ws.Hub
+	service    service.CommentService
+	challenges domain.GuestChallengeService
+	hub        *ws.Hub
 }
 
-func NewCommentHandler(service service.CommentService, hub *ws.Hub) *CommentHandler {
+func NewCommentHandler(service service.CommentService, challenges domain.GuestChallengeService, hub *ws.Hub) *CommentHandler {
 	return &CommentHandler{
-		service: service,
-		hub:     hub,
+		service:    service,
+		challenges: challenges,
+		hub:        hub,
 	}
 }
 
@@ -49,8 +52,8 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 		ParentID:  req.ParentID,
 	}
 
-	if err := h.service.Create(comment); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if err := h.service.Create(comment, currentRoles(c)); err != nil {
+		c.Error(err)
 		return
 	}
 
@@ -61,10 +64,15 @@ func (h *CommentHandler) CreateComment(c *gin.Context) {
 		completeComment = comment
 	}
 
-	// Broadcast to article room with complete user data
-	go func() {
-		h.hub.BroadcastToRoom(req.ArticleID.String(), "new_comment", completeComment)
-	}()
+	if completeComment.Status == domain.CommentApproved {
+		// Broadcast to article room with complete user data
+		go func() {
+			h.hub.BroadcastToRoom(req.ArticleID.String(), "new_comment", completeComment)
+		}()
+	} else {
+		// Held for moderation: only the author (via the response) and moderators see it
+		h.hub.BroadcastEvent("admin_data_updated", gin.H{"module": "comments", "action": "pending"})
+	}
 
 	c.JSON(http.StatusCreated, completeComment)
 }
@@ -77,8 +85,23 @@ func (h *CommentHandler) GetComments(c *gin.Context) {
 	}
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	opts, ok := threadOptions(c, domain.CommentSortNewest)
+	if !ok {
+		return
+	}
+
+	// Keyset pagination for infinite scroll (?after=<cursor>)
+	if req, ok := cursorRequest(c, limit); ok {
+		result, err := h.service.GetByArticleIDByCursor(articleID, opts, req)
+		if err != nil {
+			writeCommentListError(c, err)
+			return
+		}
+		writeCommentCursorPage(c, result)
+		return
+	}
 
-	comments, total, err := h.service.GetByArticleID(articleID, page, limit)
+	comments, total, err := h.service.GetByArticleID(articleID, opts, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -94,12 +117,28 @@ func (h *CommentHandler) GetComments(c *gin.Context) {
 	})
 }
 
+// GetReplies lists the direct replies of a comment, each with its own reply subtree.
+// A comment's replies_cursor is passed as ?after= to continue where a thread listing stopped.
 func (h *CommentHandler) GetReplies(c *gin.Context) {
 	parentID := c.Param("commentId")
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	opts, ok := threadOptions(c, domain.CommentSortOldest)
+	if !ok {
+		return
+	}
+
+	if req, ok := cursorRequest(c, limit); ok {
+		result, err := h.service.GetRepliesByParentIDByCursor(parentID, opts, req)
+		if err != nil {
+			writeCommentListError(c, err)
+			return
+		}
+		writeCommentCursorPage(c, result)
+		return
+	}
 
-	replies, total, err := h.service.GetRepliesByParentID(parentID, page, limit)
+	replies, total, err := h.service.GetRepliesByParentID(parentID, opts, page, limit)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -115,6 +154,56 @@ func (h *CommentHandler) GetReplies(c *gin.Context) {
 	})
 }
 
+// threadOptions reads ?sort=oldest|newest|top for the listed comments,
+// ?reply_sort= for the replies below them (oldest by default), ?depth= reply
+// levels and ?replies_limit= replies per comment. Unset limits use the site settings.
+func threadOptions(c *gin.Context, defaultSort domain.CommentSort) (domain.CommentThreadOptions, bool) {
+	opts := domain.CommentThreadOptions{
+		ViewerID:     viewerID(c),
+		Sort:         domain.CommentSort(c.DefaultQuery("sort", string(defaultSort))),
+		ReplySort:    domain.CommentSort(c.DefaultQuery("reply_sort", string(domain.CommentSortOldest))),
+		Depth:        -1,
+		RepliesLimit: -1,
+	}
+	if !opts.Sort.IsValid() || !opts.ReplySort.IsValid() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "sort must be oldest, newest or top"})
+		return opts, false
+	}
+	for name, dst := range map[string]*int{"depth": &opts.Depth, "replies_limit": &opts.RepliesLimit} {
+		raw, ok := c.GetQuery(name)
+		if !ok {
+			continue
+		}
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid " + name})
+			return opts, false
+		}
+		*dst = n
+	}
+	return opts, true
+}
+
+func writeCommentListError(c *gin.Context, err error) {
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
+
+func writeCommentCursorPage(c *gin.Context, result *domain.PaginatedResult[domain.Comment]) {
+	meta := gin.H{
+		"limit":       result.Pagination.Limit,
+		"next_cursor": result.Cursor.NextCursor,
+		"has_more":    result.Cursor.HasMore,
+	}
+	if result.Cursor.TotalKnown {
+		meta["total"] = result.Pagination.TotalRows
+	}
+	c.JSON(http.StatusOK, gin.H{"data": result.Data, "meta": meta})
+}
+
 func (h *CommentHandler) DeleteComment(c *gin.Context) {
 	id := c.Param("id")
 	userIDStr, exists := c.Get("user_id")
@@ -173,9 +262,15 @@ func (h *CommentHandler) UpdateComment(c *gin.Context) {
 		return
 	}
 
-	// Broadcast to article room so all viewers get the update
+	// Broadcast to article room so all viewers get the update. An edit the spam check
+	// held back is taken off open pages without sending its new content.
 	go func() {
-		h.hub.BroadcastToRoom(completeComment.ArticleID.String(), "comment_updated", completeComment)
+		room := completeComment.ArticleID.String()
+		if completeComment.Status != domain.CommentApproved {
+			h.hub.BroadcastToRoom(room, "comment_deleted", gin.H{"id": completeComment.ID})
+			return
+		}
+		h.hub.BroadcastToRoom(room, "comment_updated", completeComment)
 	}()
 
 	c.JSON(http.StatusOK, completeComment)
@@ -209,3 +304,16 @@ func (h *CommentHandler) RestoreComment(c *gin.Context) {
 
 	c.JSON(http.StatusOK, gin.H{"message": "Comment restored"})
 }
+
+// viewerID returns the signed-in user on public routes using OptionalAuthMiddleware, or nil
+func viewerID(c *gin.Context) *uuid.UUID {
+	val, exists := c.Get("user_id")
+	if !exists {
+		return nil
+	}
+	id, ok := val.(uuid.UUID)
+	if !ok {
+		return nil
+	}
+	return &id
+}
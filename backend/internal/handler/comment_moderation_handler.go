@@ -0,0 +1,163 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/core/response"
+	"backend/internal/domain"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetModerationQueue handles GET /api/v1/admin/comments
+// ?status=PENDING|APPROVED|REJECTED|SPAM (default PENDING, "all" for every status)
+// &article_id=&user_id=&search=&from=&to=&min_spam_score=&sort=spam_score&page=&limit=
+func (h *CommentHandler) GetModerationQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	filter := domain.CommentModerationFilter{
+		Search:          strings.TrimSpace(c.Query("search")),
+		SortBySpamScore: c.Query("sort") == "spam_score",
+	}
+
+	switch status := strings.ToUpper(c.DefaultQuery("status", string(domain.CommentPending))); status {
+	case "ALL":
+	default:
+		filter.Status = domain.CommentStatus(status)
+		if !filter.Status.IsValid() {
+			c.Error(apperrors.NewBadRequest("Trạng thái kiểm duyệt không hợp lệ"))
+			return
+		}
+	}
+
+	var err *apperrors.AppError
+	if filter.ArticleID, err = queryUUID(c, "article_id"); err != nil {
+		c.Error(err)
+		return
+	}
+	if filter.UserID, err = queryUUID(c, "user_id"); err != nil {
+		c.Error(err)
+		return
+	}
+	if filter.From, err = queryTime(c, "from", false); err != nil {
+		c.Error(err)
+		return
+	}
+	if filter.To, err = queryTime(c, "to", true); err != nil {
+		c.Error(err)
+		return
+	}
+	if filter.MinSpamScore, err = queryFloat(c, "min_spam_score", 0, 1); err != nil {
+		c.Error(err)
+		return
+	}
+
+	comments, total, queryErr := h.service.GetModerationQueue(filter, page, limit)
+	if queryErr != nil {
+		c.Error(apperrors.NewInternalError(queryErr))
+		return
+	}
+
+	items := make([]domain.CommentQueueItem, len(comments))
+	for i, cm := range comments {
+		items[i] = domain.CommentQueueItem{
+			Comment:          cm,
+			ModerationReason: cm.ModerationReason,
+			ModeratedBy:      cm.ModeratedBy,
+			ModeratedAt:      cm.ModeratedAt,
+			SpamScore:        cm.SpamScore,
+			SpamReasons:      cm.SpamReasons,
+		}
+	}
+	response.SuccessWithMeta(c, items, gin.H{
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// ModerateComments handles POST /api/v1/admin/comments/moderate
+// Body: {"ids": [...], "action": "approve|reject|spam|pending", "reason": "..."}
+func (h *CommentHandler) ModerateComments(c *gin.Context) {
+	var req struct {
+		IDs    []uuid.UUID `json:"ids" binding:"required,min=1,max=100"`
+		Action string      `json:"action" binding:"required"`
+		Reason string      `json:"reason" binding:"max=500"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	statuses := map[string]domain.CommentStatus{
+		"approve": domain.CommentApproved,
+		"reject":  domain.CommentRejected,
+		"spam":    domain.CommentSpam,
+		"pending": domain.CommentPending,
+	}
+	status, ok := statuses[strings.ToLower(req.Action)]
+	if !ok {
+		c.Error(apperrors.NewBadRequest("Hành động phải là approve, reject, spam hoặc pending"))
+		return
+	}
+
+	result, err := h.service.Moderate(domain.CommentModerationAction{
+		IDs:    req.IDs,
+		Status: status,
+		Reason: req.Reason,
+	}, currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Keep open article pages in sync with the moderation decision
+	for _, change := range result.Changes {
+		room := change.Comment.ArticleID.String()
+		switch {
+		case change.Comment.Status == domain.CommentApproved:
+			h.hub.BroadcastToRoom(room, "new_comment", change.Comment)
+		case change.From == domain.CommentApproved:
+			h.hub.BroadcastToRoom(room, "comment_deleted", gin.H{"id": change.Comment.ID})
+		}
+		h.hub.SendToUser(change.Comment.UserID, "comment_moderated", gin.H{
+			"id":         change.Comment.ID,
+			"article_id": change.Comment.ArticleID,
+			"status":     change.Comment.Status,
+			"reason":     change.Comment.ModerationReason,
+		})
+	}
+	h.hub.BroadcastEvent("admin_data_updated", gin.H{"module": "comments", "action": "moderate"})
+
+	response.Success(c, result)
+}
+
+func queryUUID(c *gin.Context, name string) (*uuid.UUID, *apperrors.AppError) {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil, nil
+	}
+	id, err := uuid.Parse(raw)
+	if err != nil {
+		return nil, apperrors.NewBadRequest(name + " không hợp lệ")
+	}
+	return &id, nil
+}
+
+// GetCommentRevisions handles GET /api/v1/admin/comments/:id/revisions
+func (h *CommentHandler) GetCommentRevisions(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID bình luận không hợp lệ"))
+		return
+	}
+	history, err := h.service.GetHistory(id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, history)
+}
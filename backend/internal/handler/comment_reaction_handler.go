@@ -0,0 +1,43 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/core/response"
+	"backend/internal/domain"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// ToggleReaction handles POST /api/v1/comments/:id/reactions {"type": "LIKE"}
+// Posting a reaction the user already left removes it. The new counts are pushed
+// to the article room as "comment_reactions_updated".
+func (h *CommentHandler) ToggleReaction(c *gin.Context) {
+	commentID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID bình luận không hợp lệ"))
+		return
+	}
+	var req struct {
+		Type string `json:"type" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	summary, err := h.service.ToggleReaction(commentID, currentUserID(c), domain.ReactionType(strings.ToUpper(req.Type)))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// Only the shared state goes to the room; my_reactions is the caller's own
+	h.hub.BroadcastToRoom(summary.ArticleID.String(), "comment_reactions_updated", gin.H{
+		"comment_id":      summary.CommentID,
+		"reaction_counts": summary.Counts,
+		"score":           summary.Score,
+	})
+	response.Success(c, summary)
+}
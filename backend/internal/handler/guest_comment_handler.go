@@ -0,0 +1,66 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/core/response"
+	"backend/internal/domain"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetGuestChallenge handles GET /api/v1/comments/guest/challenge
+func (h *CommentHandler) GetGuestChallenge(c *gin.Context) {
+	challenge, err := h.challenges.Issue()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, challenge)
+}
+
+// CreateGuestComment handles POST /api/v1/comments/guest
+// Body: {"article_id", "parent_id", "content", "name", "email", "challenge", "solution"}
+// where challenge is a token from GetGuestChallenge and solution its proof of work.
+// Guest comments always start PENDING and are shown once a moderator approves them.
+func (h *CommentHandler) CreateGuestComment(c *gin.Context) {
+	var req struct {
+		ArticleID uuid.UUID  `json:"article_id" binding:"required"`
+		ParentID  *uuid.UUID `json:"parent_id"`
+		Content   string     `json:"content" binding:"required"`
+		Name      string     `json:"name" binding:"required,max=100"`
+		Email     string     `json:"email" binding:"omitempty,email,max=255"`
+		Challenge string     `json:"challenge" binding:"required,max=512"`
+		Solution  string     `json:"solution" binding:"required,max=64"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+	name := strings.Join(strings.Fields(req.Name), " ")
+	if len([]rune(name)) < 2 {
+		c.Error(apperrors.NewBadRequest("Tên hiển thị phải có ít nhất 2 ký tự"))
+		return
+	}
+
+	if err := h.challenges.Verify(req.Challenge, req.Solution); err != nil {
+		c.Error(err)
+		return
+	}
+
+	comment := &domain.Comment{
+		ArticleID:  req.ArticleID,
+		ParentID:   req.ParentID,
+		Content:    req.Content,
+		GuestName:  name,
+		GuestEmail: strings.ToLower(strings.TrimSpace(req.Email)),
+	}
+	if err := h.service.Create(comment, nil); err != nil {
+		c.Error(err)
+		return
+	}
+
+	h.hub.BroadcastEvent("admin_data_updated", gin.H{"module": "comments", "action": "pending"})
+	response.Created(c, comment)
+}
@@ -0,0 +1,133 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/core/response"
+	"backend/internal/domain"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	service domain.NotificationService
+}
+
+func NewNotificationHandler(service domain.NotificationService) *NotificationHandler {
+	return &NotificationHandler{service: service}
+}
+
+// ListNotifications handles GET /api/v1/notifications?unread=true&page=&limit=
+func (h *NotificationHandler) ListNotifications(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	notifications, total, unread, err := h.service.List(currentUserID(c), c.Query("unread") == "true", page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.SuccessWithMeta(c, notifications, gin.H{
+		"total":  total,
+		"unread": unread,
+		"page":   page,
+		"limit":  limit,
+	})
+}
+
+// MarkNotificationsRead handles POST /api/v1/notifications/read {"ids": [...]}
+// An empty or missing list marks every notification as read.
+func (h *NotificationHandler) MarkNotificationsRead(c *gin.Context) {
+	var req struct {
+		IDs []uuid.UUID `json:"ids"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			c.Error(apperrors.TranslateValidationError(err))
+			return
+		}
+	}
+	if err := h.service.MarkRead(currentUserID(c), req.IDs); err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Đã đánh dấu đã đọc"})
+}
+
+// GetPreferences handles GET /api/v1/notifications/preferences
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	pref, err := h.service.GetPreference(currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, pref)
+}
+
+// UpdatePreferences handles PUT /api/v1/notifications/preferences
+// {"mute_mentions": bool, "mute_replies": bool, "email_digest": bool, "muted_until": RFC3339|null}
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var req struct {
+		MuteMentions bool       `json:"mute_mentions"`
+		MuteReplies  bool       `json:"mute_replies"`
+		EmailDigest  bool       `json:"email_digest"`
+		MutedUntil   *time.Time `json:"muted_until"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	pref := &domain.NotificationPreference{
+		MuteMentions: req.MuteMentions,
+		MuteReplies:  req.MuteReplies,
+		EmailDigest:  req.EmailDigest,
+		MutedUntil:   req.MutedUntil,
+	}
+	if err := h.service.UpdatePreference(currentUserID(c), pref); err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, pref)
+}
+
+// MuteArticle handles POST /api/v1/notifications/mutes/:articleId
+func (h *NotificationHandler) MuteArticle(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("articleId"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID bài viết không hợp lệ"))
+		return
+	}
+	if err := h.service.MuteArticle(currentUserID(c), articleID); err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Đã tắt thông báo cho bài viết"})
+}
+
+// UnmuteArticle handles DELETE /api/v1/notifications/mutes/:articleId
+func (h *NotificationHandler) UnmuteArticle(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("articleId"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID bài viết không hợp lệ"))
+		return
+	}
+	if err := h.service.UnmuteArticle(currentUserID(c), articleID); err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Đã bật lại thông báo cho bài viết"})
+}
+
+// SuggestMentions handles GET /api/v1/comments/mention-suggestions?q=
+// The editor inserts the chosen user as @[Full Name](id).
+func (h *NotificationHandler) SuggestMentions(c *gin.Context) {
+	suggestions, err := h.service.SuggestMentions(c.Query("q"))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, suggestions)
+}
@@ -0,0 +1,32 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+// cursorRequest reports whether the client asked for keyset pagination.
+// Cursor mode is selected by the presence of ?after= (empty for the first page);
+// ?with_total=true additionally counts all matching rows.
+func cursorRequest(c *gin.Context, limit int) (domain.CursorRequest, bool) {
+	after, ok := c.GetQuery("after")
+	if !ok {
+		return domain.CursorRequest{}, false
+	}
+	return domain.CursorRequest{
+		After:     after,
+		Limit:     limit,
+		WithTotal: c.Query("with_total") == "true",
+	}, true
+}
+
+// paginationError maps repository pagination errors to client-facing errors
+func paginationError(err error) *apperrors.AppError {
+	if errors.Is(err, domain.ErrInvalidCursor) {
+		return apperrors.NewBadRequest("Cursor phân trang không hợp lệ")
+	}
+	return apperrors.NewInternalError(err)
+}
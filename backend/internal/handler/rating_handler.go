@@ -1,8 +1,10 @@
 package handler
 
 import (
+	apperrors "backend/internal/core/error"
 	"backend/internal/core/response"
 	"backend/internal/domain"
+	"backend/internal/ws"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
@@ -11,10 +13,11 @@ import (
 
 type RatingHandler struct {
 	service domain.RatingService
+	hub     *ws.Hub
 }
 
-func NewRatingHandler(service domain.RatingService) *RatingHandler {
-	return &RatingHandler{service: service}
+func NewRatingHandler(service domain.RatingService, hub *ws.Hub) *RatingHandler {
+	return &RatingHandler{service: service, hub: hub}
 }
 
 func (h *RatingHandler) RateArticle(c *gin.Context) {
@@ -50,16 +53,21 @@ func (h *RatingHandler) GetRating(c *gin.Context) {
 		return
 	}
 
-	var userRating *domain.RatingDetail
+	var userRating gin.H
 	userIDVal, exists := c.Get("user_id")
 	if exists {
 		userID := userIDVal.(uuid.UUID).String()
 		rating, err := h.service.GetUserRating(articleID, userID)
 		if err == nil && rating != nil {
-			userRating = &domain.RatingDetail{
-				Content:   rating.ContentScore,
-				Clarity:   rating.ClarityScore,
-				Relevance: rating.RelevanceScore,
+			// The reviewer also sees their own review while it awaits moderation
+			userRating = gin.H{
+				"content":           rating.ContentScore,
+				"clarity":           rating.ClarityScore,
+				"relevance":         rating.RelevanceScore,
+				"review":            rating.Review,
+				"review_status":     rating.ReviewStatus,
+				"moderation_reason": rating.ModerationReason,
+				"voided":            rating.VoidedAt != nil,
 			}
 		}
 	}
@@ -70,3 +78,20 @@ func (h *RatingHandler) GetRating(c *gin.Context) {
 		"user_rating": userRating,
 	})
 }
+
+// GetRatingBreakdown handles GET /api/v1/articles/:id/rating/breakdown
+// Returns per-criterion averages and 1-5 star histograms, the exact overall
+// average and the Bayesian-adjusted score used for ranking.
+func (h *RatingHandler) GetRatingBreakdown(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID bài viết không hợp lệ"))
+		return
+	}
+	breakdown, err := h.service.GetBreakdown(articleID)
+	if err != nil {
+		c.Error(apperrors.NewInternalError(err))
+		return
+	}
+	response.Success(c, breakdown)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/core/response"
+	"backend/internal/domain"
+	"backend/internal/worker"
+	"backend/internal/ws"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type RatingIntegrityHandler struct {
+	service domain.RatingService
+	worker  *worker.RatingIntegrityWorker
+	hub     *ws.Hub
+}
+
+func NewRatingIntegrityHandler(service domain.RatingService, worker *worker.RatingIntegrityWorker, hub *ws.Hub) *RatingIntegrityHandler {
+	return &RatingIntegrityHandler{service: service, worker: worker, hub: hub}
+}
+
+// GetIntegrityReport handles GET /api/v1/admin/ratings/integrity
+// Returns the last reconciliation report (null before the first run)
+func (h *RatingIntegrityHandler) GetIntegrityReport(c *gin.Context) {
+	response.Success(c, h.worker.Report())
+}
+
+// RunIntegrityCheck handles POST /api/v1/admin/ratings/integrity
+// Recomputes all rating aggregates now and reports the drift it fixed
+func (h *RatingIntegrityHandler) RunIntegrityCheck(c *gin.Context) {
+	report, err := h.worker.RunOnce()
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, report)
+}
+
+// GetAnomalies handles GET /api/v1/admin/ratings/anomalies
+// Bursts of 1 or 5 star ratings from new accounts on one article, see the
+// rating_anomaly_* settings
+func (h *RatingIntegrityHandler) GetAnomalies(c *gin.Context) {
+	anomalies, err := h.service.FindAnomalies()
+	if err != nil {
+		c.Error(apperrors.NewInternalError(err))
+		return
+	}
+	response.Success(c, anomalies)
+}
+
+// VoidRating handles POST /api/v1/admin/ratings/:id/void
+// Body: {"reason": "..."}
+func (h *RatingIntegrityHandler) VoidRating(c *gin.Context) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID đánh giá không hợp lệ"))
+		return
+	}
+	var req struct {
+		Reason string `json:"reason" binding:"required,max=500"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	rating, err := h.service.VoidRating(id, currentUserID(c), req.Reason)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	h.hub.BroadcastEvent("admin_data_updated", gin.H{"module": "ratings", "action": "void"})
+	response.Success(c, rating)
+}
@@ -0,0 +1,137 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/core/response"
+	"backend/internal/domain"
+	"backend/internal/service"
+	"backend/internal/ws"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type ReportHandler struct {
+	service service.ReportService
+	hub     *ws.Hub
+}
+
+func NewReportHandler(service service.ReportService, hub *ws.Hub) *ReportHandler {
+	return &ReportHandler{service: service, hub: hub}
+}
+
+type reportRequest struct {
+	Reason  string `json:"reason" binding:"required"`
+	Details string `json:"details" binding:"max=1000"`
+}
+
+// ReportComment handles POST /api/v1/comments/:id/report {"reason": "SPAM", "details": "..."}
+func (h *ReportHandler) ReportComment(c *gin.Context) {
+	h.report(c, domain.ReportTargetComment)
+}
+
+// ReportArticle handles POST /api/v1/articles/:id/report {"reason": "MISINFORMATION", "details": "..."}
+func (h *ReportHandler) ReportArticle(c *gin.Context) {
+	h.report(c, domain.ReportTargetArticle)
+}
+
+func (h *ReportHandler) report(c *gin.Context, targetType domain.ReportTargetType) {
+	targetID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID nội dung không hợp lệ"))
+		return
+	}
+	var req reportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	result, err := h.service.Report(currentUserID(c), targetType, targetID,
+		domain.ReportReason(strings.ToUpper(req.Reason)), req.Details)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	// An auto-hidden comment disappears from open article pages right away
+	if comment := result.HiddenComment; comment != nil {
+		h.hub.BroadcastToRoom(comment.ArticleID.String(), "comment_deleted", gin.H{"id": comment.ID})
+	}
+	response.Created(c, gin.H{"id": result.Report.ID, "message": "Cảm ơn bạn đã báo cáo, chúng tôi sẽ xem xét sớm"})
+}
+
+// GetReports handles GET /api/v1/admin/reports
+// ?status=OPEN|ACTIONED|DISMISSED (default OPEN, "all" for every state)
+// &target_type=COMMENT|ARTICLE&target_id=&reason=&sort=most_reported&page=&limit=
+func (h *ReportHandler) GetReports(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	filter := domain.ReportFilter{MostReported: c.Query("sort") == "most_reported"}
+	if status := strings.ToUpper(c.DefaultQuery("status", string(domain.ReportOpen))); status != "ALL" {
+		filter.Status = domain.ReportStatus(status)
+		if !filter.Status.IsValid() {
+			c.Error(apperrors.NewBadRequest("Trạng thái báo cáo không hợp lệ"))
+			return
+		}
+	}
+	switch t := domain.ReportTargetType(strings.ToUpper(c.Query("target_type"))); t {
+	case "", domain.ReportTargetComment, domain.ReportTargetArticle:
+		filter.TargetType = t
+	default:
+		c.Error(apperrors.NewBadRequest("Loại nội dung báo cáo không hợp lệ"))
+		return
+	}
+	if reason := c.Query("reason"); reason != "" {
+		filter.Reason = domain.ReportReason(strings.ToUpper(reason))
+		if !filter.Reason.IsValid() {
+			c.Error(apperrors.NewBadRequest("Lý do báo cáo không hợp lệ"))
+			return
+		}
+	}
+	var appErr *apperrors.AppError
+	if filter.TargetID, appErr = queryUUID(c, "target_id"); appErr != nil {
+		c.Error(appErr)
+		return
+	}
+
+	reports, total, err := h.service.List(filter, page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.SuccessWithMeta(c, reports, gin.H{
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// ResolveReports handles POST /api/v1/admin/reports/resolve
+// Body: {"ids": [...], "status": "ACTIONED|DISMISSED|OPEN", "note": "..."}
+func (h *ReportHandler) ResolveReports(c *gin.Context) {
+	var req struct {
+		IDs    []uuid.UUID `json:"ids" binding:"required,min=1,max=100"`
+		Status string      `json:"status" binding:"required"`
+		Note   string      `json:"note" binding:"max=500"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	updated, err := h.service.Resolve(domain.ReportResolution{
+		IDs:    req.IDs,
+		Status: domain.ReportStatus(strings.ToUpper(req.Status)),
+		Note:   req.Note,
+	}, currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	h.hub.BroadcastEvent("admin_data_updated", gin.H{"module": "reports", "action": "resolve"})
+	response.Success(c, gin.H{"updated": updated})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/core/response"
+	"backend/internal/domain"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+// GetReviews handles GET /api/v1/articles/:id/reviews
+// ?sort=helpful|newest|highest&page=&limit=
+// Lists approved written reviews; signed-in readers also get their own vote per review.
+func (h *RatingHandler) GetReviews(c *gin.Context) {
+	articleID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID bài viết không hợp lệ"))
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	sort := domain.ReviewSort(strings.ToLower(c.DefaultQuery("sort", string(domain.ReviewSortHelpful))))
+	if !sort.IsValid() {
+		c.Error(apperrors.NewBadRequest("Kiểu sắp xếp phải là helpful, newest hoặc highest"))
+		return
+	}
+
+	reviews, total, err := h.service.ListReviews(articleID, sort, viewerID(c), page, limit)
+	if err != nil {
+		c.Error(apperrors.NewInternalError(err))
+		return
+	}
+	response.SuccessWithMeta(c, reviews, gin.H{
+		"total": total,
+		"page":  page,
+		"limit": limit,
+		"sort":  sort,
+	})
+}
+
+// VoteReview handles POST /api/v1/reviews/:id/vote
+// Body: {"helpful": true|false}; voting again replaces the earlier vote.
+func (h *RatingHandler) VoteReview(c *gin.Context) {
+	reviewID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID nhận xét không hợp lệ"))
+		return
+	}
+	var req struct {
+		Helpful *bool `json:"helpful" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	review, err := h.service.VoteReview(reviewID, currentUserID(c), req.Helpful)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, review)
+}
+
+// RemoveReviewVote handles DELETE /api/v1/reviews/:id/vote
+func (h *RatingHandler) RemoveReviewVote(c *gin.Context) {
+	reviewID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID nhận xét không hợp lệ"))
+		return
+	}
+	review, err := h.service.VoteReview(reviewID, currentUserID(c), nil)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, review)
+}
+
+// GetReviewQueue handles GET /api/v1/admin/reviews
+// ?status=PENDING|APPROVED|REJECTED|SPAM (default PENDING, "all" for every status)&article_id=&search=&page=&limit=
+func (h *RatingHandler) GetReviewQueue(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	filter := domain.ReviewFilter{Search: strings.TrimSpace(c.Query("search"))}
+	switch status := strings.ToUpper(c.DefaultQuery("status", string(domain.CommentPending))); status {
+	case "ALL":
+	default:
+		filter.Status = domain.CommentStatus(status)
+		if !filter.Status.IsValid() {
+			c.Error(apperrors.NewBadRequest("Trạng thái kiểm duyệt không hợp lệ"))
+			return
+		}
+	}
+	var appErr *apperrors.AppError
+	if filter.ArticleID, appErr = queryUUID(c, "article_id"); appErr != nil {
+		c.Error(appErr)
+		return
+	}
+
+	reviews, total, err := h.service.GetReviewQueue(filter, page, limit)
+	if err != nil {
+		c.Error(apperrors.NewInternalError(err))
+		return
+	}
+	items := make([]domain.ReviewQueueItem, len(reviews))
+	for i, r := range reviews {
+		items[i] = domain.ReviewQueueItem{ArticleRating: r, ModeratedBy: r.ModeratedBy, SpamScore: r.SpamScore}
+	}
+	response.SuccessWithMeta(c, items, gin.H{
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// ModerateReviews handles POST /api/v1/admin/reviews/moderate
+// Body: {"ids": [...], "action": "approve|reject|spam|pending", "reason": "..."}
+func (h *RatingHandler) ModerateReviews(c *gin.Context) {
+	var req struct {
+		IDs    []uuid.UUID `json:"ids" binding:"required,min=1,max=100"`
+		Action string      `json:"action" binding:"required"`
+		Reason string      `json:"reason" binding:"max=500"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	statuses := map[string]domain.CommentStatus{
+		"approve": domain.CommentApproved,
+		"reject":  domain.CommentRejected,
+		"spam":    domain.CommentSpam,
+		"pending": domain.CommentPending,
+	}
+	status, ok := statuses[strings.ToLower(req.Action)]
+	if !ok {
+		c.Error(apperrors.NewBadRequest("Hành động phải là approve, reject, spam hoặc pending"))
+		return
+	}
+
+	result, err := h.service.ModerateReviews(domain.ReviewModerationAction{
+		IDs:    req.IDs,
+		Status: status,
+		Reason: req.Reason,
+	}, currentUserID(c))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+
+	for _, review := range result.Changes {
+		h.hub.SendToUser(review.UserID, "review_moderated", gin.H{
+			"id":         review.ID,
+			"article_id": review.ArticleID,
+			"status":     review.ReviewStatus,
+			"reason":     review.ModerationReason,
+		})
+	}
+	h.hub.BroadcastEvent("admin_data_updated", gin.H{"module": "reviews", "action": "moderate"})
+
+	response.Success(c, result)
+}
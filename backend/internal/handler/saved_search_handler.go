@@ -0,0 +1,154 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/core/response"
+	"backend/internal/domain"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SavedSearchHandler struct {
+	service domain.SavedSearchService
+}
+
+func NewSavedSearchHandler(service domain.SavedSearchService) *SavedSearchHandler {
+	return &SavedSearchHandler{service: service}
+}
+
+type savedSearchRequest struct {
+	Name       string                      `json:"name" binding:"max=100"`
+	Query      string                      `json:"query" binding:"required,max=200"`
+	CategoryID *uuid.UUID                  `json:"category_id"`
+	Frequency  domain.SavedSearchFrequency `json:"frequency"`
+}
+
+func (r savedSearchRequest) toDomain() *domain.SavedSearch {
+	return &domain.SavedSearch{
+		Name:       r.Name,
+		Query:      r.Query,
+		CategoryID: r.CategoryID,
+		Frequency:  r.Frequency,
+	}
+}
+
+// ListSavedSearches handles GET /api/v1/saved-searches
+func (h *SavedSearchHandler) ListSavedSearches(c *gin.Context) {
+	searches, err := h.service.List(currentUserID(c))
+	if err != nil {
+		c.Error(apperrors.NewInternalError(err))
+		return
+	}
+	response.Success(c, searches)
+}
+
+// CreateSavedSearch handles POST /api/v1/saved-searches
+func (h *SavedSearchHandler) CreateSavedSearch(c *gin.Context) {
+	var req savedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	search := req.toDomain()
+	if err := h.service.Create(currentUserID(c), search); err != nil {
+		c.Error(err)
+		return
+	}
+	response.Created(c, search)
+}
+
+// GetSavedSearch handles GET /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) GetSavedSearch(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+	search, err := h.service.Get(currentUserID(c), id)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, search)
+}
+
+// UpdateSavedSearch handles PUT /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) UpdateSavedSearch(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+	var req savedSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	search := req.toDomain()
+	search.ID = id
+	if err := h.service.Update(currentUserID(c), search); err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, search)
+}
+
+// DeleteSavedSearch handles DELETE /api/v1/saved-searches/:id
+func (h *SavedSearchHandler) DeleteSavedSearch(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+	if err := h.service.Delete(currentUserID(c), id); err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Đã xóa tìm kiếm"})
+}
+
+// GetSavedSearchMatches handles GET /api/v1/saved-searches/:id/matches?page=&limit=
+// Listing the matches marks them as read.
+func (h *SavedSearchHandler) GetSavedSearchMatches(c *gin.Context) {
+	id, ok := savedSearchID(c)
+	if !ok {
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	matches, total, err := h.service.GetMatches(currentUserID(c), id, page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.SuccessWithMeta(c, matches, gin.H{
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+func savedSearchID(c *gin.Context) (uuid.UUID, bool) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID tìm kiếm không hợp lệ"))
+		return uuid.Nil, false
+	}
+	return id, true
+}
+
+// currentUserID returns the authenticated user's ID set by AuthMiddleware
+func currentUserID(c *gin.Context) uuid.UUID {
+	val, _ := c.Get("user_id")
+	id, _ := val.(uuid.UUID)
+	return id
+}
+
+// currentRoles returns the role names set by the auth middleware, nil for guests
+func currentRoles(c *gin.Context) []string {
+	val, _ := c.Get("roles")
+	roles, _ := val.([]string)
+	return roles
+}
@@ -0,0 +1,36 @@
+package handler
+
+import (
+	"backend/internal/core/response"
+	"backend/internal/worker"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type SearchHandler struct {
+	reindexWorker *worker.SearchReindexWorker
+}
+
+func NewSearchHandler(reindexWorker *worker.SearchReindexWorker) *SearchHandler {
+	return &SearchHandler{reindexWorker: reindexWorker}
+}
+
+// StartReindex handles POST /api/v1/admin/search/reindex
+// Progress is pushed to the caller as "search_reindex_progress" WebSocket events
+func (h *SearchHandler) StartReindex(c *gin.Context) {
+	userIDVal, _ := c.Get("user_id")
+	userID, _ := userIDVal.(uuid.UUID)
+
+	if err := h.reindexWorker.Start(userID); err != nil {
+		response.Error(c, err)
+		return
+	}
+
+	response.Success(c, h.reindexWorker.Status())
+}
+
+// GetReindexStatus handles GET /api/v1/admin/search/reindex
+func (h *SearchHandler) GetReindexStatus(c *gin.Context) {
+	response.Success(c, h.reindexWorker.Status())
+}
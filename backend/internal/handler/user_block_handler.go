@@ -0,0 +1,112 @@
+package handler
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/core/response"
+	"backend/internal/domain"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type UserBlockHandler struct {
+	service domain.UserBlockService
+}
+
+func NewUserBlockHandler(service domain.UserBlockService) *UserBlockHandler {
+	return &UserBlockHandler{service: service}
+}
+
+// ListBlocks handles GET /api/v1/blocks?kind=MUTE|BLOCK&page=&limit=
+func (h *UserBlockHandler) ListBlocks(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	kind := domain.BlockKind(strings.ToUpper(c.Query("kind")))
+
+	blocks, total, err := h.service.List(currentUserID(c), kind, page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.SuccessWithMeta(c, blocks, gin.H{
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// AddBlock handles POST /api/v1/blocks {"user_id": "...", "kind": "MUTE|BLOCK"}
+func (h *UserBlockHandler) AddBlock(c *gin.Context) {
+	var req struct {
+		UserID uuid.UUID `json:"user_id" binding:"required"`
+		Kind   string    `json:"kind" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+	block, err := h.service.Add(currentUserID(c), req.UserID, domain.BlockKind(strings.ToUpper(req.Kind)))
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.Created(c, block)
+}
+
+// RemoveBlock handles DELETE /api/v1/blocks/:userId?kind=MUTE|BLOCK
+func (h *UserBlockHandler) RemoveBlock(c *gin.Context) {
+	blockedID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID người dùng không hợp lệ"))
+		return
+	}
+	kind := domain.BlockKind(strings.ToUpper(c.DefaultQuery("kind", string(domain.BlockKindBlock))))
+	if err := h.service.Remove(currentUserID(c), blockedID, kind); err != nil {
+		c.Error(err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Đã bỏ chặn người dùng"})
+}
+
+// GetBlockStats handles GET /api/v1/admin/user-blocks?page=&limit=
+// Lists users by how many others blocked or muted them.
+func (h *UserBlockHandler) GetBlockStats(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	stats, total, err := h.service.Stats(page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.SuccessWithMeta(c, stats, gin.H{
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
+
+// GetReceivedBlocks handles GET /api/v1/admin/user-blocks/:userId?page=&limit=
+// Lists who blocked or muted the user, newest first.
+func (h *UserBlockHandler) GetReceivedBlocks(c *gin.Context) {
+	userID, err := uuid.Parse(c.Param("userId"))
+	if err != nil {
+		c.Error(apperrors.NewBadRequest("ID người dùng không hợp lệ"))
+		return
+	}
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	blocks, total, err := h.service.ListReceived(userID, page, limit)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	response.SuccessWithMeta(c, blocks, gin.H{
+		"total": total,
+		"page":  page,
+		"limit": limit,
+	})
+}
@@ -1,33 +1,51 @@
 package handler
 
 import (
+	"backend/internal/botdetect"
 	apperrors "backend/internal/core/error"
 	"backend/internal/core/response"
 	"backend/internal/domain"
 	"backend/internal/logger"
+	"backend/internal/worker"
+	"net/url"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 )
 
+// maxSourceRange bounds the source breakdowns, which scan raw views
+const maxSourceRange = 366 * 24 * time.Hour
+
 type ViewTrackingHandler struct {
-	service domain.ViewTrackingService
+	service    domain.ViewTrackingService
+	pipeline   *worker.ViewIngestPipeline
+	classifier *botdetect.Classifier
 }
 
 // NewViewTrackingHandler creates a new view tracking handler
-func NewViewTrackingHandler(service domain.ViewTrackingService) *ViewTrackingHandler {
+func NewViewTrackingHandler(service domain.ViewTrackingService, pipeline *worker.ViewIngestPipeline, classifier *botdetect.Classifier) *ViewTrackingHandler {
 	return &ViewTrackingHandler{
-		service: service,
+		service:    service,
+		pipeline:   pipeline,
+		classifier: classifier,
 	}
 }
 
-// TrackViewRequest represents the request body for tracking a view
+// TrackViewRequest represents the request body for tracking a view. Referrer is the
+// page's document.referrer and the utm_* fields come from its URL; all are optional.
 type TrackViewRequest struct {
-	SessionDuration int `json:"session_duration" binding:"required,min=30"`
+	SessionDuration int    `json:"session_duration" binding:"required,min=30"`
+	Referrer        string `json:"referrer"`
+	UTMSource       string `json:"utm_source"`
+	UTMMedium       string `json:"utm_medium"`
+	UTMCampaign     string `json:"utm_campaign"`
 }
 
 // TrackView handles POST /api/articles/:id/track-view
-// Records a view if session duration >= 30 seconds and no duplicate within 24h
+// Queues a view if session duration >= 30 seconds; duplicates within 24h are
+// discarded by the ingestion pipeline
 func (h *ViewTrackingHandler) TrackView(c *gin.Context) {
 	idOrSlug := c.Param("id")
 
@@ -42,12 +60,21 @@ func (h *ViewTrackingHandler) TrackView(c *gin.Context) {
 		return
 	}
 
-	// Get IP address and user agent from request
+	// Get IP address and user agent from request; the page the ping is sent from
+	// tells internal referrers apart
 	ipAddress := c.ClientIP()
-	userAgent := c.Request.UserAgent()
+	visit := domain.ViewVisit{
+		IPAddress:   ipAddress,
+		UserAgent:   c.Request.UserAgent(),
+		Referrer:    req.Referrer,
+		UTMSource:   req.UTMSource,
+		UTMMedium:   req.UTMMedium,
+		UTMCampaign: req.UTMCampaign,
+		SiteHost:    requestSiteHost(c),
+	}
 
 	// Track the view
-	err := h.service.TrackView(idOrSlug, ipAddress, userAgent, req.SessionDuration)
+	err := h.service.TrackView(idOrSlug, visit, req.SessionDuration)
 	if err != nil {
 		// Log error for debugging
 		logger.Get().Error("Failed to track view", "error", err, "identifier", idOrSlug, "ip", ipAddress)
@@ -73,6 +100,51 @@ func (h *ViewTrackingHandler) TrackView(c *gin.Context) {
 	})
 }
 
+// TrackEngagementRequest is a periodic read-depth ping. SessionID is random per page
+// load; ActiveSeconds is the non-idle reading time so far.
+type TrackEngagementRequest struct {
+	SessionID     string `json:"session_id" binding:"required"`
+	ScrollDepth   int    `json:"scroll_depth" binding:"min=0,max=100"`
+	ActiveSeconds int    `json:"active_seconds" binding:"min=0"`
+	ReachedEnd    bool   `json:"reached_end"`
+}
+
+// TrackEngagement handles POST /api/articles/:id/engagement
+// Merges the ping into the reading session; bot pings are accepted but not stored
+func (h *ViewTrackingHandler) TrackEngagement(c *gin.Context) {
+	var req TrackEngagementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.Error(apperrors.TranslateValidationError(err))
+		return
+	}
+
+	visit := domain.ViewVisit{
+		IPAddress: c.ClientIP(),
+		UserAgent: c.Request.UserAgent(),
+		SiteHost:  requestSiteHost(c),
+	}
+	ping := domain.EngagementPing{
+		SessionID:     req.SessionID,
+		ScrollDepth:   req.ScrollDepth,
+		ActiveSeconds: req.ActiveSeconds,
+		ReachedEnd:    req.ReachedEnd,
+	}
+	if err := h.service.TrackEngagement(c.Param("id"), visit, ping); err != nil {
+		switch err.Error() {
+		case "article not found":
+			c.Error(apperrors.NewNotFound("Article not found"))
+		case "invalid session id", "invalid engagement ping":
+			c.Error(apperrors.NewBadRequest("Dữ liệu đọc bài không hợp lệ"))
+		default:
+			// Like views, a lost ping is not the reader's problem
+			logger.Get().Error("Failed to track engagement", "error", err, "identifier", c.Param("id"))
+			response.Success(c, gin.H{"tracked": false})
+		}
+		return
+	}
+	response.Success(c, gin.H{"tracked": true})
+}
+
 // GetViewCount handles GET /api/articles/:id/views
 // Returns the count of valid views for an article
 func (h *ViewTrackingHandler) GetViewCount(c *gin.Context) {
@@ -125,3 +197,127 @@ func (h *ViewTrackingHandler) GetViewCountByID(c *gin.Context) {
 		"view_count": count,
 	})
 }
+
+// GetIngestStats handles GET /api/admin/views/ingest
+// Returns the view ingestion pipeline's queue depth, throughput and drop counters
+func (h *ViewTrackingHandler) GetIngestStats(c *gin.Context) {
+	response.Success(c, h.pipeline.Stats())
+}
+
+// GetTrafficStats handles GET /api/admin/views/traffic?from=&to=
+// Breaks stored views down into human, bot and suspicious traffic (default: last 7 days)
+func (h *ViewTrackingHandler) GetTrafficStats(c *gin.Context) {
+	from, err := queryTime(c, "from", false)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	to, err := queryTime(c, "to", true)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	end := time.Now().UTC()
+	if to != nil {
+		end = *to
+	}
+	start := end.AddDate(0, 0, -7)
+	if from != nil {
+		start = *from
+	}
+	if !start.Before(end) {
+		c.Error(apperrors.NewBadRequest("Khoảng thời gian không hợp lệ"))
+		return
+	}
+
+	stats, statsErr := h.service.GetTrafficStats(start, end)
+	if statsErr != nil {
+		c.Error(apperrors.NewInternalError(statsErr))
+		return
+	}
+	response.Success(c, stats)
+}
+
+// GetChannels handles GET /api/admin/views/channels
+// Query: from, to (default last 30 days), article_id, limit. Breaks human views
+// down by channel and by referrer host.
+func (h *ViewTrackingHandler) GetChannels(c *gin.Context) {
+	h.sourceStats(c, domain.SourceByChannel, domain.SourceByReferrer)
+}
+
+// GetCampaigns handles GET /api/admin/views/campaigns
+// Same query as GetChannels; views tagged with utm_campaign, per source and medium
+func (h *ViewTrackingHandler) GetCampaigns(c *gin.Context) {
+	h.sourceStats(c, domain.SourceByCampaign)
+}
+
+// GetDevices handles GET /api/admin/views/devices
+// Same query as GetChannels; views by device class, browser and OS
+func (h *ViewTrackingHandler) GetDevices(c *gin.Context) {
+	h.sourceStats(c, domain.SourceByDevice, domain.SourceByBrowser, domain.SourceByOS)
+}
+
+func (h *ViewTrackingHandler) sourceStats(c *gin.Context, groups ...domain.ViewSourceGroup) {
+	from, to, err := queryRange(c, 30)
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	if to.Sub(from) > maxSourceRange {
+		c.Error(apperrors.NewBadRequest("Khoảng thời gian tối đa là 366 ngày"))
+		return
+	}
+	articleID, err := queryUUID(c, "article_id")
+	if err != nil {
+		c.Error(err)
+		return
+	}
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+
+	stats, statsErr := h.service.GetSourceStats(groups, domain.ViewSourceQuery{From: from, To: to, ArticleID: articleID, Limit: limit})
+	if statsErr != nil {
+		c.Error(apperrors.NewInternalError(statsErr))
+		return
+	}
+	response.Success(c, stats)
+}
+
+// requestSiteHost is the host of the page that sent the request, from the Origin
+// header or, failing that, the Referer header
+func requestSiteHost(c *gin.Context) string {
+	for _, header := range []string{"Origin", "Referer"} {
+		if u, err := url.Parse(c.GetHeader(header)); err == nil && u.Host != "" {
+			return u.Host
+		}
+	}
+	return ""
+}
+
+// GetBotRules handles GET /api/admin/views/bot-rules
+// Returns the active classifier ruleset (built-in and view_* settings)
+func (h *ViewTrackingHandler) GetBotRules(c *gin.Context) {
+	response.Success(c, h.classifier.Rules())
+}
+
+// ReloadBotRules handles POST /api/admin/views/bot-rules/reload
+// Applies edited view_* settings immediately instead of within a minute
+func (h *ViewTrackingHandler) ReloadBotRules(c *gin.Context) {
+	response.Success(c, h.classifier.Reload())
+}
+
+// GetUniqueVisitors handles GET /api/admin/views/uniques?article_id=
+// Daily, weekly and monthly unique visitors from the daily visitor hashes, site-wide
+// or for one article
+func (h *ViewTrackingHandler) GetUniqueVisitors(c *gin.Context) {
+	articleID, appErr := queryUUID(c, "article_id")
+	if appErr != nil {
+		c.Error(appErr)
+		return
+	}
+	stats, err := h.service.GetUniqueVisitors(articleID)
+	if err != nil {
+		c.Error(apperrors.NewInternalError(err))
+		return
+	}
+	response.Success(c, stats)
+}
@@ -0,0 +1,58 @@
+// Package mail sends plain-text email over SMTP.
+package mail
+
+import (
+	"backend/internal/config"
+	"backend/internal/domain"
+	"fmt"
+	"log"
+	"mime"
+	"net/smtp"
+	"strings"
+)
+
+// New returns an SMTP mailer, or a LogMailer when SMTP_HOST is not configured
+func New(cfg *config.Config) domain.Mailer {
+	if cfg.SMTPHost == "" {
+		return LogMailer{}
+	}
+	return &SMTPMailer{
+		addr: cfg.SMTPHost + ":" + cfg.SMTPPort,
+		host: cfg.SMTPHost,
+		user: cfg.SMTPUser,
+		pass: cfg.SMTPPassword,
+		from: cfg.MailFrom,
+	}
+}
+
+type SMTPMailer struct {
+	addr, host, user, pass, from string
+}
+
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	var auth smtp.Auth
+	if m.user != "" {
+		auth = smtp.PlainAuth("", m.user, m.pass, m.host)
+	}
+	msg := strings.Join([]string{
+		"From: " + m.from,
+		"To: " + to,
+		"Subject: " + mime.QEncoding.Encode("utf-8", subject),
+		"MIME-Version: 1.0",
+		"Content-Type: text/plain; charset=utf-8",
+		"",
+		body,
+	}, "\r\n")
+	if err := smtp.SendMail(m.addr, auth, m.from, []string{to}, []byte(msg)); err != nil {
+		return fmt.Errorf("send mail to %s: %w", to, err)
+	}
+	return nil
+}
+
+// LogMailer writes emails to the log instead of sending them (development setups)
+type LogMailer struct{}
+
+func (LogMailer) Send(to, subject, body string) error {
+	log.Printf("Mail (not sent, SMTP_HOST unset) to=%s subject=%q\n%s", to, subject, body)
+	return nil
+}
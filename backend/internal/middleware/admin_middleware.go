@@ -39,3 +39,34 @@ func AdminMiddleware() gin.HandlerFunc {
 		c.Next()
 	}
 }
+
+// RequireRoles allows the request when the user holds at least one of the given roles
+func RequireRoles(allowed ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		roles, exists := c.Get("roles")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "Không tìm thấy thông tin quyền hạn"})
+			c.Abort()
+			return
+		}
+
+		roleList, ok := roles.([]string)
+		if !ok {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "Lỗi định dạng quyền hạn"})
+			c.Abort()
+			return
+		}
+
+		for _, role := range roleList {
+			for _, a := range allowed {
+				if role == a {
+					c.Next()
+					return
+				}
+			}
+		}
+
+		c.JSON(http.StatusForbidden, gin.H{"error": "Bạn không có quyền truy cập tính năng này"})
+		c.Abort()
+	}
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type analyticsRollupRepository struct {
+	db *gorm.DB
+}
+
+func NewAnalyticsRollupRepository(db *gorm.DB) domain.AnalyticsRollupRepository {
+	return &analyticsRollupRepository{db: db}
+}
+
+// rollupSQL aggregates views, approved comments, non-voided ratings and reading
+// sessions into buckets for every article, category, author and the whole site in
+// one pass using GROUPING SETS. @unit is the date_trunc unit matching the granularity.
+const rollupSQL = `
+INSERT INTO analytics_rollups
+	(granularity, bucket, dimension, dimension_id, views, bot_views, uniques, session_seconds, comments, ratings, rating_sum,
+	 reads, completions, active_seconds, updated_at)
+SELECT @granularity, bucket, dimension, dimension_id, views, bot_views, uniques, session_seconds, comments, ratings, rating_sum,
+	reads, completions, active_seconds, NOW()
+FROM (
+	SELECT
+		e.bucket,
+		CASE
+			WHEN GROUPING(e.article_id) = 0 THEN 'ARTICLE'
+			WHEN GROUPING(a.category_id) = 0 THEN 'CATEGORY'
+			WHEN GROUPING(a.author_id) = 0 THEN 'AUTHOR'
+			ELSE 'SITE'
+		END AS dimension,
+		COALESCE(
+			CASE
+				WHEN GROUPING(e.article_id) = 0 THEN e.article_id
+				WHEN GROUPING(a.category_id) = 0 THEN a.category_id
+				WHEN GROUPING(a.author_id) = 0 THEN a.author_id
+				ELSE '00000000-0000-0000-0000-000000000000'::uuid
+			END,
+			'00000000-0000-0000-0000-000000000000'::uuid
+		) AS dimension_id,
+		COUNT(*) FILTER (WHERE e.kind = 'view' AND e.class = 'HUMAN') AS views,
+		COUNT(*) FILTER (WHERE e.kind = 'view' AND e.class <> 'HUMAN') AS bot_views,
+		COUNT(DISTINCT e.visitor_id) FILTER (WHERE e.kind = 'view' AND e.class = 'HUMAN' AND e.visitor_id <> '') AS uniques,
+		COALESCE(SUM(e.duration) FILTER (WHERE e.kind = 'view' AND e.class = 'HUMAN'), 0) AS session_seconds,
+		COUNT(*) FILTER (WHERE e.kind = 'comment') AS comments,
+		COUNT(*) FILTER (WHERE e.kind = 'rating') AS ratings,
+		COALESCE(SUM(e.score) FILTER (WHERE e.kind = 'rating'), 0) AS rating_sum,
+		COUNT(*) FILTER (WHERE e.kind = 'read') AS reads,
+		COALESCE(SUM(e.score) FILTER (WHERE e.kind = 'read'), 0) AS completions,
+		COALESCE(SUM(e.duration) FILTER (WHERE e.kind = 'read'), 0) AS active_seconds
+	FROM (
+		SELECT date_trunc(@unit, ev.at) AS bucket, ev.*
+		FROM (
+			SELECT article_id, viewed_at AS at, 'view' AS kind, classification AS class, visitor_id, session_duration AS duration, 0 AS score
+			FROM article_views
+			WHERE viewed_at >= @from AND viewed_at < @to AND session_duration >= 30
+			UNION ALL
+			SELECT article_id, created_at, 'comment', '', '', 0, 0
+			FROM comments
+			WHERE created_at >= @from AND created_at < @to AND status = 'APPROVED' AND is_deleted = false
+			UNION ALL
+			SELECT article_id, created_at, 'rating', '', '', 0, score
+			FROM article_ratings
+			WHERE created_at >= @from AND created_at < @to AND voided_at IS NULL
+			UNION ALL
+			SELECT article_id, started_at, 'read', '', '', active_seconds, CASE WHEN reached_end THEN 1 ELSE 0 END
+			FROM article_engagements
+			WHERE started_at >= @from AND started_at < @to
+		) ev
+	) e
+	JOIN articles a ON a.id = e.article_id
+	GROUP BY GROUPING SETS (
+		(e.bucket, e.article_id),
+		(e.bucket, a.category_id),
+		(e.bucket, a.author_id),
+		(e.bucket)
+	)
+) r
+WHERE dimension = 'SITE' OR dimension_id <> '00000000-0000-0000-0000-000000000000'::uuid`
+
+// Rebuild replaces the buckets in range, so rows whose sources disappeared (deleted
+// comments, voided ratings) do not linger
+func (r *analyticsRollupRepository) Rebuild(granularity domain.RollupGranularity, from, to time.Time) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Where("granularity = ? AND bucket >= ? AND bucket < ?", granularity, from, to).
+			Delete(&domain.AnalyticsRollup{}).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec(rollupSQL, map[string]interface{}{
+			"granularity": granularity,
+			"unit":        strings.ToLower(string(granularity)),
+			"from":        from,
+			"to":          to,
+		}).Error
+	})
+}
+
+func (r *analyticsRollupRepository) EarliestEvent() (*time.Time, error) {
+	var earliest *time.Time
+	err := r.db.Raw(`
+		SELECT LEAST(
+			(SELECT MIN(viewed_at) FROM article_views),
+			(SELECT MIN(created_at) FROM comments),
+			(SELECT MIN(created_at) FROM article_ratings)
+		)`).Scan(&earliest).Error
+	return earliest, err
+}
+
+func (r *analyticsRollupRepository) GetState(name string) (*domain.RollupState, error) {
+	var state domain.RollupState
+	err := r.db.First(&state, "name = ?", name).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &state, nil
+}
+
+func (r *analyticsRollupRepository) SaveState(state *domain.RollupState) error {
+	return r.db.Clauses(clause.OnConflict{UpdateAll: true}).Create(state).Error
+}
+
+func (r *analyticsRollupRepository) Series(q domain.RollupQuery) ([]domain.AnalyticsRollup, error) {
+	var rows []domain.AnalyticsRollup
+	query := r.db.Where("granularity = ? AND dimension = ? AND bucket >= ? AND bucket < ?",
+		q.Granularity, q.Dimension, q.From, q.To)
+	if q.Dimension == domain.RollupSite {
+		query = query.Where("dimension_id = ?", uuid.Nil)
+	} else if q.DimensionID != nil {
+		query = query.Where("dimension_id = ?", *q.DimensionID)
+	}
+	err := query.Order("bucket ASC, dimension_id ASC").Find(&rows).Error
+	return rows, err
+}
+
+func (r *analyticsRollupRepository) Top(q domain.RollupQuery, limit int) ([]domain.RollupTotal, error) {
+	var nameExpr, join string
+	switch q.Dimension {
+	case domain.RollupArticle:
+		nameExpr, join = "MAX(a.title)", "LEFT JOIN articles a ON a.id = r.dimension_id"
+	case domain.RollupCategory:
+		nameExpr, join = "MAX(c.name)", "LEFT JOIN categories c ON c.id = r.dimension_id"
+	case domain.RollupAuthor:
+		nameExpr, join = "MAX(u.full_name)", "LEFT JOIN users u ON u.id = r.dimension_id"
+	default:
+		nameExpr = "''"
+	}
+
+	query := r.db.Table("analytics_rollups r")
+	if q.AuthorID != nil && q.Dimension == domain.RollupArticle {
+		query = query.Where("a.author_id = ?", *q.AuthorID)
+	}
+
+	var rows []domain.RollupTotal
+	err := query.
+		Select(`r.dimension, r.dimension_id, `+nameExpr+` AS name,
+			SUM(r.views) AS views, SUM(r.bot_views) AS bot_views, SUM(r.uniques) AS uniques,
+			SUM(r.session_seconds) AS session_seconds, SUM(r.comments) AS comments,
+			SUM(r.ratings) AS ratings, SUM(r.rating_sum) AS rating_sum,
+			SUM(r.reads) AS reads, SUM(r.completions) AS completions, SUM(r.active_seconds) AS active_seconds`).
+		Joins(join).
+		Where("r.granularity = ? AND r.dimension = ? AND r.bucket >= ? AND r.bucket < ?",
+			q.Granularity, q.Dimension, q.From, q.To).
+		Group("r.dimension, r.dimension_id").
+		Order("views DESC, r.dimension_id").
+		Limit(limit).
+		Scan(&rows).Error
+	return rows, err
+}
+
+// AuthorMedians takes each author with a published article, sums their rollups over
+// the range and returns the median of every metric. Authors without activity count
+// as zero; ratios are only taken over authors that have the denominator.
+func (r *analyticsRollupRepository) AuthorMedians(granularity domain.RollupGranularity, from, to time.Time) (*domain.AuthorBenchmark, error) {
+	var median domain.AuthorBenchmark
+	err := r.db.Raw(`
+		WITH authors AS (
+			SELECT DISTINCT author_id FROM articles WHERE status = ? AND author_id IS NOT NULL
+		), totals AS (
+			SELECT
+				au.author_id,
+				COALESCE(SUM(r.views), 0) AS views,
+				COALESCE(SUM(r.uniques), 0) AS uniques,
+				COALESCE(SUM(r.comments), 0) AS comments,
+				COALESCE(SUM(r.ratings), 0) AS ratings,
+				SUM(r.rating_sum)::float / NULLIF(SUM(r.ratings), 0) AS avg_rating,
+				SUM(r.completions)::float / NULLIF(SUM(r.reads), 0) AS completion_rate,
+				SUM(r.active_seconds)::float / NULLIF(SUM(r.reads), 0) AS avg_active
+			FROM authors au
+			LEFT JOIN analytics_rollups r
+				ON r.dimension = ? AND r.dimension_id = au.author_id
+				AND r.granularity = ? AND r.bucket >= ? AND r.bucket < ?
+			GROUP BY au.author_id
+		)
+		SELECT
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY views), 0) AS views,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY uniques), 0) AS uniques,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY comments), 0) AS comments,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY ratings), 0) AS ratings,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY avg_rating), 0) AS avg_rating,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY completion_rate), 0) AS completion_rate,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY avg_active), 0) AS avg_active
+		FROM totals`,
+		domain.StatusPublished, domain.RollupAuthor, granularity, from, to).
+		Scan(&median).Error
+	if err != nil {
+		return nil, err
+	}
+	return &median, nil
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// loadArticleListAssociations fills Category, Author, Tags and Images for a page of
+// articles using one query per association (4 total), regardless of page size.
+// It is used where articles come from raw SQL and GORM Preload is not available.
+func loadArticleListAssociations(db *gorm.DB, articles []domain.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	articleIDs := make([]uuid.UUID, 0, len(articles))
+	categoryIDs := make([]uuid.UUID, 0, len(articles))
+	authorIDs := make([]uuid.UUID, 0, len(articles))
+	seenCat := make(map[uuid.UUID]bool)
+	seenAuthor := make(map[uuid.UUID]bool)
+	for _, a := range articles {
+		articleIDs = append(articleIDs, a.ID)
+		if a.CategoryID != uuid.Nil && !seenCat[a.CategoryID] {
+			seenCat[a.CategoryID] = true
+			categoryIDs = append(categoryIDs, a.CategoryID)
+		}
+		if a.AuthorID != uuid.Nil && !seenAuthor[a.AuthorID] {
+			seenAuthor[a.AuthorID] = true
+			authorIDs = append(authorIDs, a.AuthorID)
+		}
+	}
+
+	// 1. Categories
+	categories := make(map[uuid.UUID]domain.Category)
+	if len(categoryIDs) > 0 {
+		var rows []domain.Category
+		if err := db.Where("id IN ?", categoryIDs).Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, c := range rows {
+			categories[c.ID] = c
+		}
+	}
+
+	// 2. Authors
+	authors := make(map[uuid.UUID]domain.User)
+	if len(authorIDs) > 0 {
+		var rows []domain.User
+		if err := db.Where("id IN ?", authorIDs).Find(&rows).Error; err != nil {
+			return err
+		}
+		for _, u := range rows {
+			authors[u.ID] = u
+		}
+	}
+
+	// 3. Tags through the join table
+	var tagRows []struct {
+		ArticleID uuid.UUID
+		domain.Tag
+	}
+	if err := db.Table("tags").
+		Select("article_tags.article_id, tags.id, tags.name, tags.slug").
+		Joins("JOIN article_tags ON article_tags.tag_id = tags.id").
+		Where("article_tags.article_id IN ?", articleIDs).
+		Order("tags.name ASC").
+		Scan(&tagRows).Error; err != nil {
+		return err
+	}
+	tags := make(map[uuid.UUID][]domain.Tag)
+	for _, t := range tagRows {
+		tags[t.ArticleID] = append(tags[t.ArticleID], t.Tag)
+	}
+
+	// 4. Images (primary first so PopulateImageURL picks it without scanning)
+	var imageRows []domain.ArticleImage
+	if err := db.Where("article_id IN ?", articleIDs).
+		Order("is_primary DESC, created_at ASC").
+		Find(&imageRows).Error; err != nil {
+		return err
+	}
+	images := make(map[uuid.UUID][]domain.ArticleImage)
+	for _, img := range imageRows {
+		images[img.ArticleID] = append(images[img.ArticleID], img)
+	}
+
+	for i := range articles {
+		a := &articles[i]
+		a.Category = categories[a.CategoryID]
+		a.Author = authors[a.AuthorID]
+		a.Tags = tags[a.ID]
+		if a.Tags == nil {
+			a.Tags = []domain.Tag{}
+		}
+		a.Images = images[a.ID]
+		if a.Images == nil {
+			a.Images = []domain.ArticleImage{}
+		}
+		a.PopulateImageURL()
+	}
+	return nil
+}
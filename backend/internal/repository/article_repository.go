@@ -4,6 +4,8 @@ import (
 	"backend/internal/domain"
 	"backend/internal/utils"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 	"gorm.io/gorm"
@@ -38,18 +40,192 @@ func (r *articleRepository) Create(article *domain.Article) error {
 	})
 }
 
-func (r *articleRepository) GetAll(offset, limit int, filter map[string]interface{}) ([]domain.Article, int64, error) {
+func (r *articleRepository) GetAll(offset, limit int, q domain.ArticleQuery) ([]domain.Article, int64, error) {
 	var articles []domain.Article
 	var total int64
 
-	query := r.db.Model(&domain.Article{})
-	minimal := false
-	if m, ok := filter["minimal"]; ok {
-		minimal = m.(bool)
+	query := r.listQuery(q)
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if err := query.Offset(offset).Limit(limit).Order(articleOrder(q.Sort)).Find(&articles).Error; err != nil {
+		return nil, 0, err
+	}
+
+	for i := range articles {
+		articles[i].PopulateImageURL()
+	}
+
+	return articles, total, nil
+}
+
+// articleSortColumns maps ArticleQuery sort fields to SQL expressions and cursor value kinds
+var articleSortColumns = map[string]struct {
+	Expr string
+	Kind keysetKind
+}{
+	domain.ArticleSortCreatedAt:   {"articles.created_at", keysetTime},
+	domain.ArticleSortPublishedAt: {"COALESCE(articles.published_at, articles.created_at)", keysetTime},
+	domain.ArticleSortUpdatedAt:   {"articles.updated_at", keysetTime},
+	domain.ArticleSortTitle:       {"articles.title", keysetString},
+	domain.ArticleSortViews:       {"articles.view_count", keysetInt},
+	domain.ArticleSortComments:    {"articles.comment_count", keysetInt},
+	domain.ArticleSortRating:      {"articles.rating_avg", keysetFloat},
+	domain.ArticleSortRatingScore: {"articles.rating_score", keysetFloat},
+	domain.ArticleSortEngagement:  {"(articles.view_count + articles.comment_count)", keysetInt},
+}
+
+// articleOrder renders a combined ORDER BY with the ID as final tiebreaker
+func articleOrder(keys []domain.ArticleSortKey) string {
+	if len(keys) == 0 {
+		keys = []domain.ArticleSortKey{{Field: domain.ArticleSortCreatedAt, Desc: true}}
+	}
+	parts := make([]string, 0, len(keys)+1)
+	for _, k := range keys {
+		col, ok := articleSortColumns[k.Field]
+		if !ok {
+			continue
+		}
+		dir := "ASC"
+		if k.Desc {
+			dir = "DESC"
+		}
+		parts = append(parts, col.Expr+" "+dir)
+	}
+	parts = append(parts, "articles.id DESC")
+	return strings.Join(parts, ", ")
+}
+
+// articleKeyset returns the keyset for a sort key. Cursor pagination orders by
+// a single column, so only the primary key of a combined sort is honoured.
+func articleKeyset(k domain.ArticleSortKey) keysetKey {
+	col, ok := articleSortColumns[k.Field]
+	if !ok {
+		k = domain.ArticleSortKey{Field: domain.ArticleSortCreatedAt, Desc: true}
+		col = articleSortColumns[k.Field]
+	}
+	name := k.Field
+	if k.Desc {
+		name = "-" + name
+	}
+	return keysetKey{Name: name, Expr: col.Expr, IDExpr: "articles.id", Desc: k.Desc, Kind: col.Kind}
+}
+
+func articleCursor(key keysetKey, field string, a domain.Article) domain.Cursor {
+	c := domain.Cursor{Sort: key.Name, ID: a.ID}
+	switch field {
+	case domain.ArticleSortPublishedAt:
+		if a.PublishedAt != nil {
+			c.Value = keysetTimeValue(*a.PublishedAt)
+		} else {
+			c.Value = keysetTimeValue(a.CreatedAt)
+		}
+	case domain.ArticleSortUpdatedAt:
+		c.Value = keysetTimeValue(a.UpdatedAt)
+	case domain.ArticleSortTitle:
+		c.Value = a.Title
+	case domain.ArticleSortViews:
+		c.Value = keysetIntValue(int64(a.ViewCount))
+	case domain.ArticleSortComments:
+		c.Value = keysetIntValue(int64(a.CommentCount))
+	case domain.ArticleSortRating:
+		c.Value = keysetFloatValue(a.RatingAvg)
+	case domain.ArticleSortRatingScore:
+		c.Value = keysetFloatValue(a.RatingScore)
+	case domain.ArticleSortEngagement:
+		c.Value = keysetIntValue(int64(a.ViewCount + a.CommentCount))
+	default:
+		c.Value = keysetTimeValue(a.CreatedAt)
+	}
+	return c
+}
+
+// GetAllByCursor is the keyset-paginated variant of GetAll. The total is only counted when requested.
+func (r *articleRepository) GetAllByCursor(req domain.CursorRequest, q domain.ArticleQuery) (*domain.PaginatedResult[domain.Article], error) {
+	cur, err := domain.DecodeCursor(req.After)
+	if err != nil {
+		return nil, err
+	}
+
+	sort := q.PrimarySort()
+	if _, ok := articleSortColumns[sort.Field]; !ok {
+		sort = domain.ArticleSortKey{Field: domain.ArticleSortCreatedAt, Desc: true}
+	}
+	key := articleKeyset(sort)
+
+	result := &domain.PaginatedResult[domain.Article]{
+		Pagination: domain.Pagination{Limit: req.Limit},
+	}
+
+	if req.WithTotal {
+		if err := r.listQuery(q).Count(&result.Pagination.TotalRows).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := key.apply(r.listQuery(q), cur)
+	if err != nil {
+		return nil, err
+	}
+
+	var articles []domain.Article
+	if err := query.Limit(req.Limit + 1).Find(&articles).Error; err != nil {
+		return nil, err
+	}
+
+	articles, page := keysetPage(articles, req.Limit, func(a domain.Article) domain.Cursor {
+		return articleCursor(key, sort.Field, a)
+	})
+	for i := range articles {
+		articles[i].PopulateImageURL()
+	}
+
+	page.TotalKnown = req.WithTotal
+	result.Data = articles
+	result.Cursor = &page
+	return result, nil
+}
+
+func (r *articleRepository) ExistingIDs(ids []uuid.UUID) (map[uuid.UUID]bool, error) {
+	existing := make(map[uuid.UUID]bool, len(ids))
+	if len(ids) == 0 {
+		return existing, nil
+	}
+	var found []uuid.UUID
+	if err := r.db.Model(&domain.Article{}).Where("id IN ?", ids).Pluck("id", &found).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range found {
+		existing[id] = true
+	}
+	return existing, nil
+}
+
+func (r *articleRepository) GetAfterID(afterID uuid.UUID, limit int) ([]domain.Article, error) {
+	var articles []domain.Article
+	err := r.listQuery(domain.ArticleQuery{}).
+		Where("articles.id > ?", afterID).
+		Order("articles.id").
+		Limit(limit).
+		Find(&articles).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range articles {
+		articles[i].PopulateImageURL()
 	}
+	return articles, nil
+}
+
+// listQuery builds the filtered base query shared by offset and cursor listings
+func (r *articleRepository) listQuery(q domain.ArticleQuery) *gorm.DB {
+	query := r.db.Model(&domain.Article{})
 
-	if minimal {
-		query = query.Select("id", "title", "slug")
+	if q.Minimal {
+		// Sort keys are included so cursor pagination can build the next cursor
+		query = query.Select("id", "title", "slug", "created_at", "published_at", "updated_at", "view_count", "comment_count", "rating_avg", "rating_score")
 	} else {
 		query = query.
 			Preload("Category").
@@ -61,25 +237,76 @@ func (r *articleRepository) GetAll(offset, limit int, filter map[string]interfac
 			Preload("MediaList.Media") // Preload ArticleMedia and the internal MediaFile
 	}
 
-	if status, ok := filter["status"]; ok && status != "" {
-		query = query.Where("articles.status = ?", status)
+	if q.Status != "" {
+		query = query.Where("articles.status = ?", q.Status)
+	}
+
+	if q.CategoryID != nil {
+		if q.IncludeSubcategories {
+			query = query.Where(`articles.category_id IN (
+				WITH RECURSIVE subtree AS (
+					SELECT c.id FROM categories c WHERE c.id = ?
+					UNION
+					SELECT c.id FROM categories c JOIN subtree s ON c.parent_id = s.id
+				)
+				SELECT id FROM subtree)`, *q.CategoryID)
+		} else {
+			query = query.Where("articles.category_id = ?", *q.CategoryID)
+		}
+	}
+
+	if len(q.AuthorIDs) > 0 {
+		query = query.Where("articles.author_id IN ?", q.AuthorIDs)
+	}
+
+	if len(q.TagIDs) > 0 {
+		// Subqueries rather than a join so multi-tag matches don't duplicate rows
+		if q.TagMatch == domain.TagMatchAll && len(q.TagIDs) > 1 {
+			query = query.Where(`articles.id IN (
+				SELECT article_id FROM article_tags WHERE tag_id IN ?
+				GROUP BY article_id HAVING COUNT(DISTINCT tag_id) = ?)`, q.TagIDs, len(q.TagIDs))
+		} else {
+			query = query.Where("articles.id IN (SELECT article_id FROM article_tags WHERE tag_id IN ?)", q.TagIDs)
+		}
+	}
+
+	if q.IsFeatured != nil {
+		query = query.Where("articles.is_featured = ?", *q.IsFeatured)
 	}
 
-	if categoryID, ok := filter["category_id"]; ok && categoryID != "" {
-		query = query.Where("articles.category_id = ?", categoryID)
+	query = whereTimeRange(query, "articles.created_at", q.CreatedFrom, q.CreatedTo)
+	query = whereTimeRange(query, "articles.published_at", q.PublishedFrom, q.PublishedTo)
+	query = whereTimeRange(query, "articles.updated_at", q.UpdatedFrom, q.UpdatedTo)
+
+	if q.MinRating != nil {
+		query = query.Where("articles.rating_avg >= ?", *q.MinRating)
+	}
+	if q.MaxRating != nil {
+		query = query.Where("articles.rating_avg <= ?", *q.MaxRating)
+	}
+	if q.MinViews != nil {
+		query = query.Where("articles.view_count >= ?", *q.MinViews)
 	}
-	if tagID, ok := filter["tag_id"]; ok && tagID != "" {
-		query = query.Joins("JOIN article_tags ON article_tags.article_id = articles.id").
-			Where("article_tags.tag_id = ?", tagID)
+	if q.MaxViews != nil {
+		query = query.Where("articles.view_count <= ?", *q.MaxViews)
+	}
+
+	if q.HasImage != nil {
+		exists := "EXISTS (SELECT 1 FROM article_images ai WHERE ai.article_id = articles.id)"
+		if *q.HasImage {
+			query = query.Where(exists)
+		} else {
+			query = query.Where("NOT " + exists)
+		}
 	}
 
-	if isFeatured, ok := filter["is_featured"]; ok {
-		query = query.Where("articles.is_featured = ?", isFeatured)
+	if q.Language != "" {
+		query = query.Where("articles.language = ?", q.Language)
 	}
 
-	if search, ok := filter["search"]; ok && search != "" {
-		searchTerm := "%" + search.(string) + "%"
-		if minimal {
+	if q.Search != "" {
+		searchTerm := "%" + q.Search + "%"
+		if q.Minimal {
 			query = query.Where("(title ILIKE ? OR slug ILIKE ?)", searchTerm, searchTerm)
 		} else {
 			query = query.
@@ -90,36 +317,18 @@ func (r *articleRepository) GetAll(offset, limit int, filter map[string]interfac
 		}
 	}
 
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	// Handle Sorting
-	order := "articles.created_at DESC"
-	if sort, ok := filter["sort"]; ok && sort != "" {
-		switch sort.(string) {
-		case "engagement":
-			order = "(articles.view_count + articles.comment_count) DESC"
-		case "views":
-			order = "articles.view_count DESC"
-		case "comments":
-			order = "articles.comment_count DESC"
-		case "newest":
-			order = "articles.created_at DESC"
-		case "oldest":
-			order = "articles.created_at ASC"
-		}
-	}
+	return query
+}
 
-	if err := query.Offset(offset).Limit(limit).Order(order).Find(&articles).Error; err != nil {
-		return nil, 0, err
+// whereTimeRange adds an inclusive [from, to] bound on column
+func whereTimeRange(query *gorm.DB, column string, from, to *time.Time) *gorm.DB {
+	if from != nil {
+		query = query.Where(column+" >= ?", *from)
 	}
-
-	for i := range articles {
-		articles[i].PopulateImageURL()
+	if to != nil {
+		query = query.Where(column+" <= ?", *to)
 	}
-
-	return articles, total, nil
+	return query
 }
 
 func (r *articleRepository) GetByID(id uuid.UUID) (*domain.Article, error) {
@@ -158,6 +367,33 @@ func (r *articleRepository) getByID(id uuid.UUID, full bool) (*domain.Article, e
 	return &article, nil
 }
 
+func (r *articleRepository) GetByIDs(ids []uuid.UUID) ([]domain.Article, error) {
+	if len(ids) == 0 {
+		return []domain.Article{}, nil
+	}
+
+	var found []domain.Article
+	if err := r.db.Where("id IN ?", ids).Find(&found).Error; err != nil {
+		return nil, err
+	}
+	if err := loadArticleListAssociations(r.db, found); err != nil {
+		return nil, err
+	}
+
+	byID := make(map[uuid.UUID]domain.Article, len(found))
+	for _, a := range found {
+		byID[a.ID] = a
+	}
+
+	articles := make([]domain.Article, 0, len(found))
+	for _, id := range ids {
+		if a, ok := byID[id]; ok {
+			articles = append(articles, a)
+		}
+	}
+	return articles, nil
+}
+
 func (r *articleRepository) GetBySlug(slug string) (*domain.Article, error) {
 	var article domain.Article
 	err := r.db.Preload("Category").
@@ -183,7 +419,7 @@ func (r *articleRepository) GetBySlug(slug string) (*domain.Article, error) {
 
 func (r *articleRepository) Update(article *domain.Article) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Model(article).Select("Title", "Slug", "Summary", "Content", "CategoryID", "Status", "IsFeatured", "AllowComment", "PublishedAt", "UpdatedAt", "ViewCount", "Complexity", "Depth", "Impact").
+		if err := tx.Model(article).Select("Title", "Slug", "Summary", "Content", "CategoryID", "Status", "IsFeatured", "AllowComment", "CommentPolicy", "Language", "PublishedAt", "UpdatedAt", "ViewCount", "Complexity", "Depth", "Impact").
 			Updates(article).Error; err != nil {
 			return err
 		}
@@ -418,6 +654,22 @@ func (r *articleRepository) IncrementViewCount(id uuid.UUID) error {
 		UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
 }
 
+func (r *articleRepository) IncrementViewCounts(counts map[uuid.UUID]int) error {
+	if len(counts) == 0 {
+		return nil
+	}
+	values := make([]string, 0, len(counts))
+	args := make([]interface{}, 0, len(counts)*2)
+	for id, n := range counts {
+		values = append(values, "(?::uuid, ?::int)")
+		args = append(args, id, n)
+	}
+	return r.db.Exec(`
+		UPDATE articles SET view_count = view_count + v.n
+		FROM (VALUES `+strings.Join(values, ", ")+`) AS v(id, n)
+		WHERE articles.id = v.id`, args...).Error
+}
+
 func (r *articleRepository) IncrementCommentCount(id uuid.UUID) error {
 	return r.db.Model(&domain.Article{}).Where("id = ?", id).
 		UpdateColumn("comment_count", gorm.Expr("comment_count + 1")).Error
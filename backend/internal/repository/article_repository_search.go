@@ -3,6 +3,7 @@ package repository
 import (
 	"backend/internal/domain"
 	"errors"
+	"time"
 )
 
 // SearchArticles implements full-text search with PostgreSQL FTS and pg_trgm fuzzy matching
@@ -26,6 +27,9 @@ func (r *articleRepository) SearchArticles(query string, offset, limit int, filt
 		categoryFilter = categoryID.(string)
 	}
 
+	// Only articles published after this instant (used by saved-search alerts)
+	publishedAfter, _ := filters["published_after"].(time.Time)
+
 	// Count total distinct articles matching the search
 	countQuery := r.db.Model(&domain.Article{}).
 		Joins("LEFT JOIN categories ON categories.id = articles.category_id").
@@ -45,6 +49,9 @@ func (r *articleRepository) SearchArticles(query string, offset, limit int, filt
 	if categoryFilter != "" {
 		countQuery = countQuery.Where("articles.category_id = ?", categoryFilter)
 	}
+	if !publishedAfter.IsZero() {
+		countQuery = countQuery.Where("articles.published_at > ?", publishedAfter)
+	}
 
 	// Use distinct count on article id
 	if err := countQuery.Distinct("articles.id").Count(&total).Error; err != nil {
@@ -83,6 +90,10 @@ func (r *articleRepository) SearchArticles(query string, offset, limit int, filt
 		searchQuery += " AND articles.category_id = ?"
 		args = append(args, categoryFilter)
 	}
+	if !publishedAfter.IsZero() {
+		searchQuery += " AND articles.published_at > ?"
+		args = append(args, publishedAfter)
+	}
 
 	// Close subquery and add ordering
 	searchQuery += `
@@ -100,13 +111,9 @@ func (r *articleRepository) SearchArticles(query string, offset, limit int, filt
 		return nil, 0, err
 	}
 
-	// Load associations manually since we used raw query
-	for i := range articles {
-		r.db.Model(&articles[i]).Association("Category").Find(&articles[i].Category)
-		r.db.Model(&articles[i]).Association("Author").Find(&articles[i].Author)
-		r.db.Model(&articles[i]).Association("Tags").Find(&articles[i].Tags)
-		r.db.Model(&articles[i]).Association("Images").Find(&articles[i].Images)
-		articles[i].PopulateImageURL()
+	// Load associations in batch since we used raw query
+	if err := loadArticleListAssociations(r.db, articles); err != nil {
+		return nil, 0, err
 	}
 
 	return articles, total, nil
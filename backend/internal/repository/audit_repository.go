@@ -28,6 +28,57 @@ func (r *auditRepository) GetAuditLogs(page, limit int, filter map[string]interf
 	var logs []domain.AuditLog
 	var total int64
 
+	query := r.auditLogQuery(filter)
+
+	err := query.Count(&total).Error
+	if err != nil {
+		return nil, 0, err
+	}
+
+	offset := (page - 1) * limit
+	err = query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&logs).Error
+	return logs, total, err
+}
+
+// auditLogKeyset orders audit logs newest first with the ID as tiebreaker
+var auditLogKeyset = keysetKey{Name: "newest", Expr: "audit_logs.created_at", IDExpr: "audit_logs.id", Desc: true, Kind: keysetTime}
+
+// GetAuditLogsByCursor is the keyset-paginated variant of GetAuditLogs, stable under concurrent inserts
+func (r *auditRepository) GetAuditLogsByCursor(req domain.CursorRequest, filter map[string]interface{}) (*domain.PaginatedResult[domain.AuditLog], error) {
+	cur, err := domain.DecodeCursor(req.After)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.PaginatedResult[domain.AuditLog]{
+		Pagination: domain.Pagination{Limit: req.Limit},
+	}
+	if req.WithTotal {
+		if err := r.auditLogQuery(filter).Count(&result.Pagination.TotalRows).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	query, err := auditLogKeyset.apply(r.auditLogQuery(filter), cur)
+	if err != nil {
+		return nil, err
+	}
+
+	var logs []domain.AuditLog
+	if err := query.Limit(req.Limit + 1).Find(&logs).Error; err != nil {
+		return nil, err
+	}
+
+	logs, page := keysetPage(logs, req.Limit, func(l domain.AuditLog) domain.Cursor {
+		return domain.Cursor{Sort: auditLogKeyset.Name, Value: keysetTimeValue(l.CreatedAt), ID: l.ID}
+	})
+	page.TotalKnown = req.WithTotal
+	result.Data = logs
+	result.Cursor = &page
+	return result, nil
+}
+
+func (r *auditRepository) auditLogQuery(filter map[string]interface{}) *gorm.DB {
 	query := r.db.Model(&domain.AuditLog{}).Preload("User")
 
 	if userID, ok := filter["user_id"]; ok {
@@ -43,15 +94,7 @@ func (r *auditRepository) GetAuditLogs(page, limit int, filter map[string]interf
 		s := "%" + search.(string) + "%"
 		query = query.Where("action ILIKE ? OR table_name ILIKE ? OR record_id ILIKE ?", s, s, s)
 	}
-
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	offset := (page - 1) * limit
-	err = query.Order("created_at DESC").Offset(offset).Limit(limit).Find(&logs).Error
-	return logs, total, err
+	return query
 }
 
 func (r *auditRepository) GetSystemLogs(page, limit int, filter map[string]interface{}) ([]domain.SystemLog, int64, error) {
@@ -38,9 +38,9 @@ func (r *categoryRepository) GetTree() ([]domain.Category, error) {
 		return nil, err
 	}
 
-	// Recursively load children for each top-level category
-	for i := range categories {
-		r.loadChildren(&categories[i])
+	// Attach the full subtree of each top-level category
+	if err := r.loadChildren(categories); err != nil {
+		return nil, err
 	}
 
 	return categories, nil
@@ -127,8 +127,8 @@ func (r *categoryRepository) GetList(page, limit int, search, sortBy, order stri
 
 	if !minimal {
 		// Build full tree for each category only if not minimal
-		for i := range categories {
-			r.loadChildren(&categories[i])
+		if err := r.loadChildren(categories); err != nil {
+			return nil, err
 		}
 	}
 
@@ -143,25 +143,64 @@ func (r *categoryRepository) GetList(page, limit int, search, sortBy, order stri
 	}, nil
 }
 
-// loadChildren recursively loads all children for a category
-func (r *categoryRepository) loadChildren(category *domain.Category) {
-	var children []domain.Category
+// loadChildren attaches the full subtree of every given category.
+// All categories are fetched in a single query and the tree is assembled in memory.
+func (r *categoryRepository) loadChildren(categories []domain.Category) error {
+	var all []domain.Category
+	if err := r.db.Order("name ASC").Find(&all).Error; err != nil {
+		return err
+	}
 
-	err := r.db.Where("parent_id = ?", category.ID).
-		Preload("Parent").
-		Find(&children).Error
+	byID := make(map[uuid.UUID]domain.Category, len(all))
+	byParent := make(map[uuid.UUID][]domain.Category)
+	for _, c := range all {
+		byID[c.ID] = c
+		if c.ParentID != nil {
+			byParent[*c.ParentID] = append(byParent[*c.ParentID], c)
+		}
+	}
+
+	var attach func(category *domain.Category, visited map[uuid.UUID]bool)
+	attach = func(category *domain.Category, visited map[uuid.UUID]bool) {
+		// Guard against cycles in bad data
+		if visited[category.ID] {
+			category.Children = []domain.Category{}
+			return
+		}
+		visited[category.ID] = true
 
-	if err != nil || len(children) == 0 {
-		category.Children = []domain.Category{}
-		return
+		children := append([]domain.Category(nil), byParent[category.ID]...)
+		for i := range children {
+			parent := byID[category.ID]
+			children[i].Parent = &parent
+			attach(&children[i], visited)
+		}
+		if children == nil {
+			children = []domain.Category{}
+		}
+		category.Children = children
 	}
 
-	// Recursively load children for each child
-	for i := range children {
-		r.loadChildren(&children[i])
+	for i := range categories {
+		attach(&categories[i], make(map[uuid.UUID]bool))
 	}
+	return nil
+}
 
-	category.Children = children
+func (r *categoryRepository) GetAncestors(id uuid.UUID) ([]domain.Category, error) {
+	var categories []domain.Category
+	// The level bound stops a parent_id cycle from looping forever
+	err := r.db.Raw(`
+		WITH RECURSIVE ancestors AS (
+			SELECT c.*, 0 AS level FROM categories c WHERE c.id = ?
+			UNION ALL
+			SELECT p.*, a.level + 1 FROM categories p
+			INNER JOIN ancestors a ON p.id = a.parent_id
+			WHERE a.level < 32
+		)
+		SELECT * FROM ancestors ORDER BY level
+	`, id).Scan(&categories).Error
+	return categories, err
 }
 
 func (r *categoryRepository) Update(category *domain.Category) error {
@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"encoding/json"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ToggleReaction adds the user's reaction of the given type, or removes it if it
+// was already there, then recounts the comment's reactions and stores the
+// denormalized counts and Wilson score.
+func (r *commentRepository) ToggleReaction(commentID, userID uuid.UUID, reaction domain.ReactionType) (*domain.CommentReactionSummary, error) {
+	summary := &domain.CommentReactionSummary{CommentID: commentID, Reaction: reaction}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// Lock the comment so concurrent toggles recount one after another
+		var comment domain.Comment
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "article_id").
+			First(&comment, "id = ?", commentID).Error; err != nil {
+			return err
+		}
+		summary.ArticleID = comment.ArticleID
+
+		res := tx.Where("comment_id = ? AND user_id = ? AND type = ?", commentID, userID, reaction).
+			Delete(&domain.CommentReaction{})
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			if err := tx.Create(&domain.CommentReaction{CommentID: commentID, UserID: userID, Type: reaction}).Error; err != nil {
+				return err
+			}
+			summary.Added = true
+		}
+
+		var rows []struct {
+			Type  domain.ReactionType
+			Count int
+		}
+		if err := tx.Model(&domain.CommentReaction{}).
+			Select("type, COUNT(*) AS count").
+			Where("comment_id = ?", commentID).
+			Group("type").
+			Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		summary.Counts = make(map[domain.ReactionType]int, len(rows))
+		var positive, negative int
+		for _, row := range rows {
+			summary.Counts[row.Type] = row.Count
+			switch row.Type.Sentiment() {
+			case 1:
+				positive += row.Count
+			case -1:
+				negative += row.Count
+			}
+		}
+		summary.Score = domain.WilsonScore(positive, positive+negative)
+
+		counts, err := json.Marshal(summary.Counts)
+		if err != nil {
+			return err
+		}
+		if err := tx.Model(&domain.Comment{}).Where("id = ?", commentID).UpdateColumns(map[string]interface{}{
+			"reaction_counts": domain.JSONB(counts),
+			"score":           summary.Score,
+		}).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(&domain.CommentReaction{}).
+			Where("comment_id = ? AND user_id = ?", commentID, userID).
+			Order("created_at").
+			Pluck("type", &summary.MyReactions).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// userReactions returns the user's reactions on the given comments
+func userReactions(db *gorm.DB, userID uuid.UUID, commentIDs []uuid.UUID) (map[uuid.UUID][]domain.ReactionType, error) {
+	out := make(map[uuid.UUID][]domain.ReactionType)
+	if len(commentIDs) == 0 {
+		return out, nil
+	}
+	var reactions []domain.CommentReaction
+	if err := db.Where("user_id = ? AND comment_id IN ?", userID, commentIDs).
+		Order("created_at").
+		Find(&reactions).Error; err != nil {
+		return nil, err
+	}
+	for _, re := range reactions {
+		out[re.CommentID] = append(out[re.CommentID], re.Type)
+	}
+	return out, nil
+}
@@ -5,18 +5,42 @@ import (
 	"context"
 	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type CommentRepository interface {
 	Create(comment *domain.Comment) error
 	GetByID(id string) (*domain.Comment, error)
-	GetByArticleID(articleID string, page, limit int) ([]domain.Comment, int64, error)
-	GetRepliesByParentID(parentID string, page, limit int) ([]domain.Comment, int64, error)
+	// Listing methods only return approved comments, plus the viewer's own pending ones when
+	// opts.ViewerID is set, each with its reply subtree as limited by opts
+	GetByArticleID(articleID string, opts domain.CommentThreadOptions, page, limit int) ([]domain.Comment, int64, error)
+	GetByArticleIDByCursor(articleID string, opts domain.CommentThreadOptions, req domain.CursorRequest) (*domain.PaginatedResult[domain.Comment], error)
+	GetRepliesByParentID(parentID string, opts domain.CommentThreadOptions, page, limit int) ([]domain.Comment, int64, error)
+	GetRepliesByParentIDByCursor(parentID string, opts domain.CommentThreadOptions, req domain.CursorRequest) (*domain.PaginatedResult[domain.Comment], error)
+	AdjustReplyCount(id uuid.UUID, delta int) error
+	// ReplaceMentions swaps the comment's mention records for the given ones
+	ReplaceMentions(commentID uuid.UUID, mentions []domain.CommentMention) error
+
+	// Reactions
+	ToggleReaction(commentID, userID uuid.UUID, reaction domain.ReactionType) (*domain.CommentReactionSummary, error)
 	GetLastCommentByUserID(userID string) (*domain.Comment, error)
-	Update(comment *domain.Comment) error
+	// Edit saves new content and keeps the replaced text as the next revision
+	Edit(comment *domain.Comment, previous *domain.CommentRevision) error
+	GetRevisions(commentID uuid.UUID) ([]domain.CommentRevision, error)
 	Delete(id string) error
 	Restore(id string) error
+
+	// Moderation
+	GetByIDs(ids []uuid.UUID) ([]domain.Comment, error)
+	GetModerationQueue(filter domain.CommentModerationFilter, offset, limit int) ([]domain.Comment, int64, error)
+	// SetStatus records a moderation decision; moderatorID is nil for automatic ones
+	SetStatus(ids []uuid.UUID, status domain.CommentStatus, reason string, moderatorID *uuid.UUID, at time.Time) error
+
+	// Spam signals
+	CountDuplicateContent(content string, excludeUserID uuid.UUID, since time.Time) (int64, error)
+	CountByUserAndStatus(userID uuid.UUID, status domain.CommentStatus) (int64, error)
 }
 
 type commentRepository struct {
@@ -33,83 +57,72 @@ func (r *commentRepository) Create(comment *domain.Comment) error {
 
 func (r *commentRepository) GetByID(id string) (*domain.Comment, error) {
 	var comment domain.Comment
-	err := r.db.Preload("User").First(&comment, "id = ?", id).Error
+	err := r.db.Preload("User").Preload("Mentions").First(&comment, "id = ?", id).Error
 	return &comment, err
 }
 
-func (r *commentRepository) GetByArticleID(articleID string, page, limit int) ([]domain.Comment, int64, error) {
-	var comments []domain.Comment
-	var total int64
-	offset := (page - 1) * limit
+// visibleTo restricts q to approved comments, plus the viewer's own pending ones
+func visibleTo(q *gorm.DB, viewerID *uuid.UUID) *gorm.DB {
+	if viewerID == nil || *viewerID == uuid.Nil {
+		return q.Where("comments.status = ?", domain.CommentApproved)
+	}
+	return q.Where("(comments.status = ? OR (comments.status = ? AND comments.user_id = ?))",
+		domain.CommentApproved, domain.CommentPending, *viewerID)
+}
 
+func (r *commentRepository) GetByArticleID(articleID string, opts domain.CommentThreadOptions, page, limit int) ([]domain.Comment, int64, error) {
 	// Add timeout context to prevent long-running queries
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(r.db.Statement.Context, 5*time.Second)
 	defer cancel()
+	db := r.db.WithContext(ctx)
 
-	query := r.db.WithContext(ctx).Model(&domain.Comment{}).Where("article_id = ? AND parent_id IS NULL AND is_deleted = false", articleID)
-
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
+	return listCommentPage(db, r.topLevel(db, articleID, opts.ViewerID), opts, page, limit)
+}
 
-	err = query.
-		Preload("User").
-		Preload("Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc").Limit(100) }).
-		Preload("Replies.User").
-		Preload("Replies.Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc").Limit(100) }).
-		Preload("Replies.Replies.User").
-		Preload("Replies.Replies.Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc").Limit(100) }).
-		Preload("Replies.Replies.Replies.User").
-		Preload("Replies.Replies.Replies.Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc").Limit(100) }).
-		Preload("Replies.Replies.Replies.Replies.User").
-		Preload("Replies.Replies.Replies.Replies.Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc").Limit(100) }).
-		Preload("Replies.Replies.Replies.Replies.Replies.User").
-		Preload("Replies.Replies.Replies.Replies.Replies.Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc").Limit(100) }).
-		Preload("Replies.Replies.Replies.Replies.Replies.Replies.User").
-		Preload("Replies.Replies.Replies.Replies.Replies.Replies.Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc").Limit(100) }).
-		Preload("Replies.Replies.Replies.Replies.Replies.Replies.Replies.User").
-		Preload("Replies.Replies.Replies.Replies.Replies.Replies.Replies.Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc").Limit(100) }).
-		Preload("Replies.Replies.Replies.Replies.Replies.Replies.Replies.Replies.User").
-		Preload("Replies.Replies.Replies.Replies.Replies.Replies.Replies.Replies.Replies", func(db *gorm.DB) *gorm.DB { return db.Order("created_at asc").Limit(100) }).
-		Preload("Replies.Replies.Replies.Replies.Replies.Replies.Replies.Replies.Replies.User").
-		Order("created_at desc").
-		Offset(offset).
-		Limit(limit).
-		Find(&comments).Error
+// GetByArticleIDByCursor is the keyset-paginated variant of GetByArticleID
+func (r *commentRepository) GetByArticleIDByCursor(articleID string, opts domain.CommentThreadOptions, req domain.CursorRequest) (*domain.PaginatedResult[domain.Comment], error) {
+	ctx, cancel := context.WithTimeout(r.db.Statement.Context, 5*time.Second)
+	defer cancel()
+	db := r.db.WithContext(ctx)
 
-	return comments, total, err
+	return listCommentsByCursor(db, r.topLevel(db, articleID, opts.ViewerID), opts, req)
 }
 
-func (r *commentRepository) GetRepliesByParentID(parentID string, page, limit int) ([]domain.Comment, int64, error) {
-	var replies []domain.Comment
-	var total int64
-	offset := (page - 1) * limit
+func (r *commentRepository) topLevel(db *gorm.DB, articleID string, viewerID *uuid.UUID) func() *gorm.DB {
+	return func() *gorm.DB {
+		return visibleTo(db.Model(&domain.Comment{}).Where("comments.article_id = ? AND comments.parent_id IS NULL AND comments.is_deleted = false", articleID), viewerID)
+	}
+}
 
+func (r *commentRepository) GetRepliesByParentID(parentID string, opts domain.CommentThreadOptions, page, limit int) ([]domain.Comment, int64, error) {
 	// Add timeout context
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	ctx, cancel := context.WithTimeout(r.db.Statement.Context, 5*time.Second)
 	defer cancel()
+	db := r.db.WithContext(ctx)
 
-	query := r.db.WithContext(ctx).Model(&domain.Comment{}).Where("parent_id = ? AND is_deleted = false", parentID)
+	return listCommentPage(db, r.repliesTo(db, parentID, opts.ViewerID), opts, page, limit)
+}
 
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
+// GetRepliesByParentIDByCursor is the keyset-paginated variant of GetRepliesByParentID,
+// used to follow a comment's RepliesCursor
+func (r *commentRepository) GetRepliesByParentIDByCursor(parentID string, opts domain.CommentThreadOptions, req domain.CursorRequest) (*domain.PaginatedResult[domain.Comment], error) {
+	ctx, cancel := context.WithTimeout(r.db.Statement.Context, 5*time.Second)
+	defer cancel()
+	db := r.db.WithContext(ctx)
 
-	// Fetch replies with limited nested replies (1 level deep to prevent excessive loading)
-	err = query.
-		Preload("User").
-		Preload("Replies", func(db *gorm.DB) *gorm.DB {
-			return db.Where("is_deleted = false").Order("created_at asc").Limit(5)
-		}).
-		Preload("Replies.User").
-		Order("created_at asc").
-		Offset(offset).
-		Limit(limit).
-		Find(&replies).Error
+	return listCommentsByCursor(db, r.repliesTo(db, parentID, opts.ViewerID), opts, req)
+}
 
-	return replies, total, err
+func (r *commentRepository) repliesTo(db *gorm.DB, parentID string, viewerID *uuid.UUID) func() *gorm.DB {
+	return func() *gorm.DB {
+		return visibleTo(db.Model(&domain.Comment{}).Where("comments.parent_id = ? AND comments.is_deleted = false", parentID), viewerID)
+	}
+}
+
+// AdjustReplyCount moves a comment's reply_count by delta, never below zero
+func (r *commentRepository) AdjustReplyCount(id uuid.UUID, delta int) error {
+	return r.db.Model(&domain.Comment{}).Where("id = ?", id).
+		UpdateColumn("reply_count", gorm.Expr("GREATEST(reply_count + ?, 0)", delta)).Error
 }
 
 func (r *commentRepository) GetLastCommentByUserID(userID string) (*domain.Comment, error) {
@@ -121,8 +134,54 @@ func (r *commentRepository) GetLastCommentByUserID(userID string) (*domain.Comme
 	return &comment, nil
 }
 
-func (r *commentRepository) Update(comment *domain.Comment) error {
-	return r.db.Save(comment).Error
+func (r *commentRepository) Edit(comment *domain.Comment, previous *domain.CommentRevision) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		// The row lock numbers concurrent edits one after another
+		var editCount int
+		if err := tx.Model(&domain.Comment{}).
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", comment.ID).
+			Pluck("edit_count", &editCount).Error; err != nil {
+			return err
+		}
+		previous.CommentID = comment.ID
+		previous.Revision = editCount + 1
+		if err := tx.Create(previous).Error; err != nil {
+			return err
+		}
+
+		comment.EditCount = previous.Revision
+		comment.EditedAt = &previous.EditedAt
+		comment.IsEdited = true
+		// Mentions change through ReplaceMentions, which also drops removed ones
+		return tx.Model(comment).Select("content", "edit_count", "edited_at", "is_edited", "updated_at",
+			"status", "is_spam", "spam_score", "spam_reasons").Updates(comment).Error
+	})
+}
+
+func (r *commentRepository) GetRevisions(commentID uuid.UUID) ([]domain.CommentRevision, error) {
+	revisions := []domain.CommentRevision{}
+	err := r.db.
+		Preload("Editor", func(db *gorm.DB) *gorm.DB { return db.Select("id", "full_name", "avatar_url") }).
+		Where("comment_id = ?", commentID).
+		Order("revision ASC").
+		Find(&revisions).Error
+	return revisions, err
+}
+
+func (r *commentRepository) ReplaceMentions(commentID uuid.UUID, mentions []domain.CommentMention) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("comment_id = ?", commentID).Delete(&domain.CommentMention{}).Error; err != nil {
+			return err
+		}
+		if len(mentions) == 0 {
+			return nil
+		}
+		for i := range mentions {
+			mentions[i].CommentID = commentID
+		}
+		return tx.Create(&mentions).Error
+	})
 }
 
 func (r *commentRepository) Delete(id string) error {
@@ -132,3 +191,90 @@ func (r *commentRepository) Delete(id string) error {
 func (r *commentRepository) Restore(id string) error {
 	return r.db.Model(&domain.Comment{}).Where("id = ?", id).Update("is_deleted", false).Error
 }
+
+func (r *commentRepository) GetByIDs(ids []uuid.UUID) ([]domain.Comment, error) {
+	var comments []domain.Comment
+	if len(ids) == 0 {
+		return comments, nil
+	}
+	err := r.db.Preload("User").Preload("Mentions").Where("id IN ?", ids).Find(&comments).Error
+	return comments, err
+}
+
+// GetModerationQueue lists comments for moderators, oldest first so the queue is worked in order
+func (r *commentRepository) GetModerationQueue(filter domain.CommentModerationFilter, offset, limit int) ([]domain.Comment, int64, error) {
+	var comments []domain.Comment
+	var total int64
+
+	query := r.db.Model(&domain.Comment{}).Where("comments.is_deleted = false")
+	if filter.Status != "" {
+		query = query.Where("comments.status = ?", filter.Status)
+	}
+	if filter.ArticleID != nil {
+		query = query.Where("comments.article_id = ?", *filter.ArticleID)
+	}
+	if filter.UserID != nil {
+		query = query.Where("comments.user_id = ?", *filter.UserID)
+	}
+	if filter.Search != "" {
+		query = query.Where("comments.content ILIKE ?", "%"+filter.Search+"%")
+	}
+	if filter.From != nil {
+		query = query.Where("comments.created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("comments.created_at <= ?", *filter.To)
+	}
+	if filter.MinSpamScore != nil {
+		query = query.Where("comments.spam_score >= ?", *filter.MinSpamScore)
+	}
+
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "comments.created_at ASC, comments.id ASC"
+	if filter.SortBySpamScore {
+		order = "comments.spam_score DESC, " + order
+	}
+
+	err := query.
+		Preload("User").
+		Preload("Article", func(db *gorm.DB) *gorm.DB { return db.Select("id", "title", "slug", "category_id", "author_id") }).
+		Order(order).
+		Offset(offset).
+		Limit(limit).
+		Find(&comments).Error
+	return comments, total, err
+}
+
+func (r *commentRepository) SetStatus(ids []uuid.UUID, status domain.CommentStatus, reason string, moderatorID *uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&domain.Comment{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"status":            status,
+		"is_spam":           status == domain.CommentSpam,
+		"moderation_reason": reason,
+		"moderated_by":      moderatorID,
+		"moderated_at":      at,
+	}).Error
+}
+
+// CountDuplicateContent counts recent comments by other users or guests with the same text (case and surrounding space ignored)
+func (r *commentRepository) CountDuplicateContent(content string, excludeUserID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Comment{}).
+		Where("created_at >= ? AND (user_id IS NULL OR user_id <> ?)", since, excludeUserID).
+		Where("lower(btrim(content)) = lower(btrim(?))", content).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *commentRepository) CountByUserAndStatus(userID uuid.UUID, status domain.CommentStatus) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Comment{}).
+		Where("user_id = ? AND status = ? AND is_deleted = false", userID, status).
+		Count(&count).Error
+	return count, err
+}
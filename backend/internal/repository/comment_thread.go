@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"strings"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// commentKeysets are the orders of one thread level, each with the ID as tiebreaker
+var commentKeysets = map[domain.CommentSort]keysetKey{
+	domain.CommentSortOldest: {Name: "oldest", Expr: "comments.created_at", IDExpr: "comments.id", Kind: keysetTime},
+	domain.CommentSortNewest: {Name: "newest", Expr: "comments.created_at", IDExpr: "comments.id", Desc: true, Kind: keysetTime},
+	domain.CommentSortTop:    {Name: "top", Expr: "comments.score", IDExpr: "comments.id", Desc: true, Kind: keysetFloat},
+}
+
+// commentKeyset returns the keyset for sort, newest first when unset
+func commentKeyset(sort domain.CommentSort) keysetKey {
+	if k, ok := commentKeysets[sort]; ok {
+		return k
+	}
+	return commentKeysets[domain.CommentSortNewest]
+}
+
+func commentCursor(k keysetKey, c domain.Comment) domain.Cursor {
+	cur := domain.Cursor{Sort: k.Name, ID: c.ID}
+	switch k.Kind {
+	case keysetFloat:
+		cur.Value = keysetFloatValue(c.Score)
+	default:
+		cur.Value = keysetTimeValue(c.CreatedAt)
+	}
+	return cur
+}
+
+// commentThreadRow is a reply loaded by attachThread with its rank among its siblings
+type commentThreadRow struct {
+	domain.Comment
+	SiblingRank  int
+	SiblingCount int
+}
+
+// listCommentPage loads one offset page of the comments matched by base with their reply subtrees
+func listCommentPage(db *gorm.DB, base func() *gorm.DB, opts domain.CommentThreadOptions, page, limit int) ([]domain.Comment, int64, error) {
+	var total int64
+	if err := base().Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var comments []domain.Comment
+	query, _ := commentKeyset(opts.Sort).apply(base(), nil)
+	if err := query.Offset((page - 1) * limit).Limit(limit).Find(&comments).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := attachThread(db, comments, opts); err != nil {
+		return nil, 0, err
+	}
+	return comments, total, nil
+}
+
+// listCommentsByCursor is the keyset-paginated variant of listCommentPage
+func listCommentsByCursor(db *gorm.DB, base func() *gorm.DB, opts domain.CommentThreadOptions, req domain.CursorRequest) (*domain.PaginatedResult[domain.Comment], error) {
+	cur, err := domain.DecodeCursor(req.After)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &domain.PaginatedResult[domain.Comment]{
+		Pagination: domain.Pagination{Limit: req.Limit},
+	}
+	if req.WithTotal {
+		if err := base().Count(&result.Pagination.TotalRows).Error; err != nil {
+			return nil, err
+		}
+	}
+
+	key := commentKeyset(opts.Sort)
+	query, err := key.apply(base(), cur)
+	if err != nil {
+		return nil, err
+	}
+
+	var comments []domain.Comment
+	if err := query.Limit(req.Limit + 1).Find(&comments).Error; err != nil {
+		return nil, err
+	}
+
+	comments, page := keysetPage(comments, req.Limit, func(c domain.Comment) domain.Cursor {
+		return commentCursor(key, c)
+	})
+	if err := attachThread(db, comments, opts); err != nil {
+		return nil, err
+	}
+	page.TotalKnown = req.WithTotal
+	result.Data = comments
+	result.Cursor = &page
+	return result, nil
+}
+
+// attachThread fills in the authors and the reply subtrees of a page of sibling
+// comments in at most four queries, however deep the thread: one windowed query
+// over the page's subtrees (selected by path prefix) keeps the first RepliesLimit
+// replies of every comment down to Depth levels, then one query each loads the
+// authors, the mentions and the viewer's reactions.
+// Replies whose parent fell outside those limits are dropped; their parent gets
+// HasMoreReplies and a RepliesCursor instead.
+func attachThread(db *gorm.DB, items []domain.Comment, opts domain.CommentThreadOptions) error {
+	if len(items) == 0 {
+		return nil
+	}
+	key := commentKeyset(opts.ReplySort)
+	// Siblings share a depth
+	baseDepth := items[0].Depth
+
+	var rows []commentThreadRow
+	if opts.Depth > 0 && opts.RepliesLimit > 0 {
+		// One LIKE per subtree so the text_pattern_ops path index applies; path
+		// segments are hex, so they need no escaping
+		subtrees := make([]string, len(items))
+		prefixes := make([]interface{}, len(items))
+		for i, c := range items {
+			subtrees[i] = "comments.path LIKE ?"
+			prefixes[i] = c.Path + ".%"
+		}
+		ranked := visibleTo(db.Model(&domain.Comment{}).
+			Select("comments.*, "+
+				"ROW_NUMBER() OVER (PARTITION BY comments.parent_id ORDER BY "+key.order()+") AS sibling_rank, "+
+				"COUNT(*) OVER (PARTITION BY comments.parent_id) AS sibling_count").
+			Where("comments.article_id = ? AND comments.is_deleted = false", items[0].ArticleID).
+			Where("comments.depth > ? AND comments.depth <= ?", baseDepth, baseDepth+opts.Depth).
+			Where(strings.Join(subtrees, " OR "), prefixes...), opts.ViewerID)
+
+		err := db.Table("(?) AS thread", ranked).
+			Where("sibling_rank <= ?", opts.RepliesLimit).
+			Order("depth, sibling_rank").
+			Scan(&rows).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	children := make(map[uuid.UUID][]domain.Comment)
+	siblingTotal := make(map[uuid.UUID]int)
+	userIDs := make([]uuid.UUID, 0, len(items)+len(rows))
+	commentIDs := make([]uuid.UUID, 0, len(items)+len(rows))
+	for _, c := range items {
+		userIDs = append(userIDs, c.UserID)
+		commentIDs = append(commentIDs, c.ID)
+	}
+	for _, row := range rows {
+		if row.ParentID == nil {
+			continue
+		}
+		children[*row.ParentID] = append(children[*row.ParentID], row.Comment)
+		siblingTotal[*row.ParentID] = row.SiblingCount
+		userIDs = append(userIDs, row.UserID)
+		commentIDs = append(commentIDs, row.ID)
+	}
+
+	var users []domain.User
+	if err := db.Where("id IN ?", userIDs).Find(&users).Error; err != nil {
+		return err
+	}
+	usersByID := make(map[uuid.UUID]*domain.User, len(users))
+	for i := range users {
+		usersByID[users[i].ID] = &users[i]
+	}
+
+	var mentions []domain.CommentMention
+	if err := db.Where("comment_id IN ?", commentIDs).Find(&mentions).Error; err != nil {
+		return err
+	}
+	mentionsOf := make(map[uuid.UUID][]domain.CommentMention)
+	for _, m := range mentions {
+		mentionsOf[m.CommentID] = append(mentionsOf[m.CommentID], m)
+	}
+
+	var mine map[uuid.UUID][]domain.ReactionType
+	if opts.ViewerID != nil && *opts.ViewerID != uuid.Nil {
+		var err error
+		if mine, err = userReactions(db, *opts.ViewerID, commentIDs); err != nil {
+			return err
+		}
+	}
+
+	var build func(c *domain.Comment)
+	build = func(c *domain.Comment) {
+		c.User = usersByID[c.UserID]
+		c.Mentions = mentionsOf[c.ID]
+		c.MyReactions = mine[c.ID]
+		c.IsCollapsed = !c.IsGuest() && opts.CollapsedAuthors[c.UserID]
+		replies := children[c.ID]
+		for i := range replies {
+			build(&replies[i])
+		}
+		c.Replies = replies
+
+		if c.Depth < baseDepth+opts.Depth && opts.RepliesLimit > 0 {
+			// Replies at this level were queried, so the visible total is exact
+			c.HasMoreReplies = siblingTotal[c.ID] > len(replies)
+		} else {
+			c.HasMoreReplies = c.ReplyCount > 0
+		}
+		if c.HasMoreReplies && len(replies) > 0 {
+			c.RepliesCursor = commentCursor(key, replies[len(replies)-1]).Encode()
+		}
+	}
+	for i := range items {
+		build(&items[i])
+	}
+	return nil
+}
@@ -23,15 +23,19 @@ func (r *dashboardRepository) GetAnalytics() (*domain.DashboardAnalyticsData, er
 		return nil, err
 	}
 
-	// 2. Total Views (Sum of view_count from articles)
-	if err := r.db.Model(&domain.Article{}).Select("COALESCE(SUM(view_count), 0)").Scan(&data.TotalViews).Error; err != nil {
-		return nil, err
-	}
-
-	// 3. Total Comments
-	if err := r.db.Model(&domain.Comment{}).Count(&data.TotalComments).Error; err != nil {
+	// 2-3. Total human views and approved comments, from the daily site rollups
+	var totals struct {
+		Views    int64
+		Comments int64
+	}
+	err := r.db.Model(&domain.AnalyticsRollup{}).
+		Select("COALESCE(SUM(views), 0) AS views, COALESCE(SUM(comments), 0) AS comments").
+		Where("granularity = ? AND dimension = ?", domain.RollupDay, domain.RollupSite).
+		Scan(&totals).Error
+	if err != nil {
 		return nil, err
 	}
+	data.TotalViews, data.TotalComments = totals.Views, totals.Comments
 
 	// 4. Total Categories
 	if err := r.db.Model(&domain.Category{}).Count(&data.TotalCategories).Error; err != nil {
@@ -63,6 +67,17 @@ func (r *dashboardRepository) GetAnalytics() (*domain.DashboardAnalyticsData, er
 		data.ArticleTrend = append(data.ArticleTrend, trend)
 	}
 
+	// Human views per day over the last 30 days, from the daily site rollups
+	data.ViewTrend = make([]domain.ArticleTrend, 0)
+	err = r.db.Model(&domain.AnalyticsRollup{}).
+		Select("TO_CHAR(bucket, 'YYYY-MM-DD') AS date, views AS count").
+		Where("granularity = ? AND dimension = ? AND bucket >= CURRENT_DATE - INTERVAL '30 days'", domain.RollupDay, domain.RollupSite).
+		Order("bucket ASC").
+		Scan(&data.ViewTrend).Error
+	if err != nil {
+		return nil, err
+	}
+
 	// 7. Top Categories
 	data.TopCategories = make([]domain.CategoryStats, 0)
 	err = r.db.Table("categories").
@@ -429,8 +444,8 @@ func (r *dashboardRepository) GetSuperDashboard() (*domain.SuperDashboardData, e
 	}
 	data.CategoryTree = tree
 
-	// 5. Engagement
-	r.db.Table("comments").Count(&data.Engagement.TotalComments)
+	// 5. Engagement; spam and deleted comments are not in the rollups and stay live
+	data.Engagement.TotalComments = data.Stats.TotalComments
 	r.db.Table("comments").Where("is_spam = ?", true).Count(&data.Engagement.SpamComments)
 	r.db.Table("comments").Where("is_deleted = ?", true).Count(&data.Engagement.DeletedComments)
 	// Sum share_count from articles table (denormalized)
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type engagementRepository struct {
+	db *gorm.DB
+}
+
+func NewEngagementRepository(db *gorm.DB) domain.EngagementRepository {
+	return &engagementRepository{db: db}
+}
+
+func (r *engagementRepository) UpsertBatch(sessions []domain.ArticleEngagement) error {
+	if len(sessions) == 0 {
+		return nil
+	}
+	return r.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "article_id"}, {Name: "session_id"}},
+		DoUpdates: clause.Set{
+			{Column: clause.Column{Name: "max_scroll"}, Value: gorm.Expr("GREATEST(article_engagements.max_scroll, EXCLUDED.max_scroll)")},
+			{Column: clause.Column{Name: "active_seconds"}, Value: gorm.Expr("GREATEST(article_engagements.active_seconds, EXCLUDED.active_seconds)")},
+			{Column: clause.Column{Name: "reached_end"}, Value: gorm.Expr("article_engagements.reached_end OR EXCLUDED.reached_end")},
+			{Column: clause.Column{Name: "pings"}, Value: gorm.Expr("article_engagements.pings + EXCLUDED.pings")},
+			{Column: clause.Column{Name: "updated_at"}, Value: gorm.Expr("EXCLUDED.updated_at")},
+		},
+		Where: clause.Where{Exprs: []clause.Expression{
+			clause.Expr{SQL: "article_engagements.visitor_id = EXCLUDED.visitor_id"},
+		}},
+	}).CreateInBatches(sessions, 500).Error
+}
+
+func (r *engagementRepository) sessions(articleID uuid.UUID, from, to time.Time) *gorm.DB {
+	return r.db.Model(&domain.ArticleEngagement{}).
+		Where("article_id = ? AND started_at >= ? AND started_at < ?", articleID, from, to)
+}
+
+func (r *engagementRepository) Summary(articleID uuid.UUID, from, to time.Time) (*domain.EngagementSummary, error) {
+	var summary domain.EngagementSummary
+	err := r.sessions(articleID, from, to).
+		Select(`COUNT(*) AS sessions,
+			COUNT(*) FILTER (WHERE reached_end) AS completed,
+			COALESCE(percentile_cont(0.5) WITHIN GROUP (ORDER BY active_seconds), 0) AS median_active,
+			COALESCE(AVG(active_seconds), 0) AS avg_active,
+			COALESCE(AVG(max_scroll), 0) AS avg_scroll`).
+		Scan(&summary).Error
+	if err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
+
+func (r *engagementRepository) ScrollHistogram(articleID uuid.UUID, from, to time.Time) ([]domain.ScrollCount, error) {
+	var counts []domain.ScrollCount
+	err := r.sessions(articleID, from, to).
+		Select("max_scroll AS depth, COUNT(*) AS sessions").
+		Group("max_scroll").
+		Order("max_scroll").
+		Scan(&counts).Error
+	return counts, err
+}
+
+func (r *engagementRepository) DeleteBefore(before time.Time, chunk int) (int64, error) {
+	var total int64
+	for {
+		result := r.db.Exec(`
+			DELETE FROM article_engagements
+			WHERE id IN (SELECT id FROM article_engagements WHERE started_at < ? LIMIT ?)`, before, chunk)
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+		if result.RowsAffected < int64(chunk) {
+			return total, nil
+		}
+	}
+}
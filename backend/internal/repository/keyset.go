@@ -0,0 +1,99 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"fmt"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// keysetKind tells how a cursor value is serialised for a sort column
+type keysetKind int
+
+const (
+	keysetTime keysetKind = iota
+	keysetInt
+	keysetFloat
+	keysetString
+)
+
+// keysetKey is a stable sort order usable for cursor pagination: a sort
+// expression plus the row ID as tiebreaker, e.g. (published_at, id).
+type keysetKey struct {
+	Name   string
+	Expr   string
+	IDExpr string
+	Desc   bool
+	Kind   keysetKind
+}
+
+// apply filters rows strictly after the cursor and orders by (Expr, IDExpr)
+func (k keysetKey) apply(q *gorm.DB, cur *domain.Cursor) (*gorm.DB, error) {
+	op := ">"
+	if k.Desc {
+		op = "<"
+	}
+
+	if cur != nil {
+		if cur.Sort != k.Name {
+			return nil, domain.ErrInvalidCursor
+		}
+		val, err := k.parse(cur.Value)
+		if err != nil {
+			return nil, domain.ErrInvalidCursor
+		}
+		q = q.Where(fmt.Sprintf("(%s, %s) %s (?, ?)", k.Expr, k.IDExpr, op), val, cur.ID)
+	}
+
+	return q.Order(k.order()), nil
+}
+
+// order is the ORDER BY clause of the key, also usable inside window functions
+func (k keysetKey) order() string {
+	dir := "ASC"
+	if k.Desc {
+		dir = "DESC"
+	}
+	return fmt.Sprintf("%s %s, %s %s", k.Expr, dir, k.IDExpr, dir)
+}
+
+func (k keysetKey) parse(v string) (interface{}, error) {
+	switch k.Kind {
+	case keysetInt:
+		return strconv.ParseInt(v, 10, 64)
+	case keysetFloat:
+		return strconv.ParseFloat(v, 64)
+	case keysetString:
+		return v, nil
+	default:
+		return time.Parse(time.RFC3339Nano, v)
+	}
+}
+
+func keysetTimeValue(t time.Time) string {
+	return t.UTC().Format(time.RFC3339Nano)
+}
+
+func keysetIntValue(n int64) string {
+	return strconv.FormatInt(n, 10)
+}
+
+func keysetFloatValue(f float64) string {
+	return strconv.FormatFloat(f, 'g', -1, 64)
+}
+
+// keysetPage trims the extra look-ahead row fetched with limit+1 and builds the
+// cursor pagination block from the last row returned.
+func keysetPage[T any](rows []T, limit int, cursorOf func(T) domain.Cursor) ([]T, domain.CursorPagination) {
+	page := domain.CursorPagination{}
+	if len(rows) > limit {
+		rows = rows[:limit]
+		page.HasMore = true
+	}
+	if page.HasMore && len(rows) > 0 {
+		page.NextCursor = cursorOf(rows[len(rows)-1]).Encode()
+	}
+	return rows, page
+}
@@ -0,0 +1,184 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) domain.NotificationRepository {
+	return &notificationRepository{db: db}
+}
+
+func (r *notificationRepository) CreateIfAbsent(notifications []domain.Notification) ([]domain.Notification, error) {
+	var created []domain.Notification
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range notifications {
+			n := notifications[i]
+			res := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&n)
+			if res.Error != nil {
+				return res.Error
+			}
+			if res.RowsAffected > 0 {
+				created = append(created, n)
+			}
+		}
+		return nil
+	})
+	return created, err
+}
+
+func (r *notificationRepository) List(userID uuid.UUID, unreadOnly bool, offset, limit int) ([]domain.Notification, int64, error) {
+	var notifications []domain.Notification
+	var total int64
+
+	query := r.db.Model(&domain.Notification{}).Where("user_id = ?", userID)
+	if unreadOnly {
+		query = query.Where("read_at IS NULL")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := withNotificationContext(query).
+		Order("created_at DESC, id DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, total, err
+}
+
+// withNotificationContext preloads the actor and a slim article for display
+func withNotificationContext(q *gorm.DB) *gorm.DB {
+	return q.
+		Preload("Actor", func(db *gorm.DB) *gorm.DB { return db.Select("id", "full_name", "avatar_url") }).
+		Preload("Article", func(db *gorm.DB) *gorm.DB { return db.Select("id", "title", "slug") })
+}
+
+func (r *notificationRepository) CountUnread(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.Notification{}).Where("user_id = ? AND read_at IS NULL", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *notificationRepository) MarkRead(userID uuid.UUID, ids []uuid.UUID, at time.Time) error {
+	query := r.db.Model(&domain.Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if len(ids) > 0 {
+		query = query.Where("id IN ?", ids)
+	}
+	return query.Update("read_at", at).Error
+}
+
+func (r *notificationRepository) GetPreferences(userIDs []uuid.UUID) (map[uuid.UUID]domain.NotificationPreference, error) {
+	prefs := make(map[uuid.UUID]domain.NotificationPreference)
+	if len(userIDs) == 0 {
+		return prefs, nil
+	}
+	var rows []domain.NotificationPreference
+	if err := r.db.Where("user_id IN ?", userIDs).Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, p := range rows {
+		prefs[p.UserID] = p
+	}
+	return prefs, nil
+}
+
+func (r *notificationRepository) SavePreference(pref *domain.NotificationPreference) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"mute_mentions", "mute_replies", "email_digest", "muted_until", "updated_at"}),
+	}).Create(pref).Error
+}
+
+func (r *notificationRepository) ListMutes(userID uuid.UUID) ([]uuid.UUID, error) {
+	ids := []uuid.UUID{}
+	err := r.db.Model(&domain.NotificationMute{}).Where("user_id = ?", userID).Order("created_at DESC").Pluck("article_id", &ids).Error
+	return ids, err
+}
+
+func (r *notificationRepository) MutedUsers(userIDs []uuid.UUID, articleID uuid.UUID) (map[uuid.UUID]bool, error) {
+	muted := make(map[uuid.UUID]bool)
+	if len(userIDs) == 0 {
+		return muted, nil
+	}
+	var ids []uuid.UUID
+	if err := r.db.Model(&domain.NotificationMute{}).
+		Where("article_id = ? AND user_id IN ?", articleID, userIDs).
+		Pluck("user_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		muted[id] = true
+	}
+	return muted, nil
+}
+
+func (r *notificationRepository) AddMute(userID, articleID uuid.UUID) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.NotificationMute{UserID: userID, ArticleID: articleID}).Error
+}
+
+func (r *notificationRepository) RemoveMute(userID, articleID uuid.UUID) error {
+	return r.db.Where("user_id = ? AND article_id = ?", userID, articleID).Delete(&domain.NotificationMute{}).Error
+}
+
+func (r *notificationRepository) GetActiveUsers(ids []uuid.UUID) ([]domain.User, error) {
+	var users []domain.User
+	if len(ids) == 0 {
+		return users, nil
+	}
+	err := r.db.Select("id", "full_name", "avatar_url").Where("id IN ? AND is_active = true", ids).Find(&users).Error
+	return users, err
+}
+
+func (r *notificationRepository) SearchActiveUsers(query string, limit int) ([]domain.User, error) {
+	var users []domain.User
+	err := r.db.Select("id", "full_name", "avatar_url").
+		Where(`is_active = true AND full_name ILIKE ? ESCAPE '\'`, "%"+likeEscaper.Replace(query)+"%").
+		Order("full_name").
+		Limit(limit).
+		Find(&users).Error
+	return users, err
+}
+
+// likeEscaper makes user input match literally inside a LIKE pattern with ESCAPE '\'
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func (r *notificationRepository) ListDigestDue(lastBefore time.Time) ([]domain.NotificationPreference, error) {
+	var prefs []domain.NotificationPreference
+	err := r.db.
+		Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id", "email", "full_name", "is_active") }).
+		Where("email_digest = true AND (last_digest_at IS NULL OR last_digest_at <= ?)", lastBefore).
+		Find(&prefs).Error
+	return prefs, err
+}
+
+// GetUnemailed returns unread notifications not yet included in a digest, oldest first
+func (r *notificationRepository) GetUnemailed(userID uuid.UUID, limit int) ([]domain.Notification, error) {
+	var notifications []domain.Notification
+	err := withNotificationContext(r.db.Where("user_id = ? AND read_at IS NULL AND emailed_at IS NULL", userID)).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&notifications).Error
+	return notifications, err
+}
+
+func (r *notificationRepository) MarkEmailed(ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&domain.Notification{}).Where("id IN ?", ids).Update("emailed_at", at).Error
+}
+
+func (r *notificationRepository) MarkDigestSent(userID uuid.UUID, at time.Time) error {
+	return r.db.Model(&domain.NotificationPreference{}).Where("user_id = ?", userID).Update("last_digest_at", at).Error
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"fmt"
+	"os"
+	"testing"
+	"time"
+
+	"backend/internal/db"
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// The query budgets run against a database with database/schema.sql applied, given
+// as a DSN in TEST_DATABASE_DSN, e.g.
+//
+//	TEST_DATABASE_DSN="host=localhost user=test password=test dbname=test_db sslmode=disable" go test ./internal/repository
+//
+// Fixtures are written in a transaction that is rolled back afterwards.
+const (
+	smallPage = 1
+	largePage = 20
+
+	// Preloads that find no rows skip their follow-up query, so a one-row page
+	// may legitimately issue a few statements fewer than a full page.
+	growthSlack = 3
+
+	budgetSearchTerm = "querybudgetfixture"
+)
+
+type queryBudget struct {
+	name   string
+	budget int64
+	run    func(limit int) error
+}
+
+func TestQueryBudgets(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+	conn, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to test database: %v", err)
+	}
+
+	tx := conn.Begin()
+	if tx.Error != nil {
+		t.Fatalf("failed to begin transaction: %v", tx.Error)
+	}
+	defer tx.Rollback()
+
+	articleIDs, commentArticleID := seedQueryBudgetFixtures(t, tx)
+
+	countedDB, counter, err := db.Counted(tx)
+	if err != nil {
+		t.Fatalf("failed to install query counter: %v", err)
+	}
+	articleRepo := NewArticleRepository(countedDB)
+	categoryRepo := NewCategoryRepository(countedDB)
+	commentRepo := NewCommentRepository(countedDB)
+
+	budgets := []queryBudget{
+		{"ArticleRepository.GetAll", 14, func(limit int) error {
+			_, _, err := articleRepo.GetAll(0, limit, domain.ArticleQuery{})
+			return err
+		}},
+		{"ArticleRepository.GetAll(minimal)", 2, func(limit int) error {
+			_, _, err := articleRepo.GetAll(0, limit, domain.ArticleQuery{Minimal: true})
+			return err
+		}},
+		{"ArticleRepository.GetByIDs", 5, func(limit int) error {
+			_, err := articleRepo.GetByIDs(articleIDs[:limit])
+			return err
+		}},
+		{"ArticleRepository.SearchArticles", 6, func(limit int) error {
+			_, _, err := articleRepo.SearchArticles(budgetSearchTerm, 0, limit, map[string]interface{}{"status": string(domain.StatusPublished)})
+			return err
+		}},
+		{"ArticleRepository.GetTrending", 4, func(limit int) error {
+			_, err := articleRepo.GetTrending(limit)
+			return err
+		}},
+		{"CategoryRepository.GetTree", 2, func(limit int) error {
+			_, err := categoryRepo.GetTree()
+			return err
+		}},
+		{"CategoryRepository.GetList", 4, func(limit int) error {
+			_, err := categoryRepo.GetList(1, limit, "", "", "", false)
+			return err
+		}},
+		{"CommentRepository.GetByArticleID", 5, func(limit int) error {
+			opts := domain.CommentThreadOptions{Sort: domain.CommentSortNewest, ReplySort: domain.CommentSortOldest, Depth: 10, RepliesLimit: 5}
+			_, _, err := commentRepo.GetByArticleID(commentArticleID.String(), opts, 1, limit)
+			return err
+		}},
+	}
+
+	for _, b := range budgets {
+		t.Run(b.name, func(t *testing.T) {
+			small, err := counter.Measure(b.name, b.budget, func() error { return b.run(smallPage) })
+			if err != nil {
+				t.Fatal(err)
+			}
+			large, err := counter.Measure(b.name, b.budget, func() error { return b.run(largePage) })
+			if err != nil {
+				t.Fatal(err)
+			}
+			if large-small > growthSlack {
+				t.Errorf("%d queries at limit %d but %d at limit %d (N+1?)", small, smallPage, large, largePage)
+				for _, q := range counter.Queries() {
+					t.Log(q)
+				}
+			}
+		})
+	}
+}
+
+// seedQueryBudgetFixtures creates a full page of published articles with tags and
+// images, a category tree and a comment thread, so every preload has rows to load
+func seedQueryBudgetFixtures(t *testing.T, tx *gorm.DB) ([]uuid.UUID, uuid.UUID) {
+	t.Helper()
+	suffix := uuid.NewString()[:8]
+	must := func(err error) {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("failed to seed fixtures: %v", err)
+		}
+	}
+
+	author := domain.User{Email: "querybudget-" + suffix + "@example.com", PasswordHash: "-", FullName: "Query Budget"}
+	must(tx.Create(&author).Error)
+
+	parent := domain.Category{Name: "Query budget " + suffix, Slug: "query-budget-" + suffix}
+	must(tx.Create(&parent).Error)
+	var categories []domain.Category
+	for i := 0; i < largePage; i++ {
+		categories = append(categories, domain.Category{
+			Name:     fmt.Sprintf("Query budget %s %d", suffix, i),
+			Slug:     fmt.Sprintf("query-budget-%s-%d", suffix, i),
+			ParentID: &parent.ID,
+		})
+	}
+	must(tx.Create(&categories).Error)
+
+	tags := []domain.Tag{
+		{Name: "qb-a-" + suffix, Slug: "qb-a-" + suffix},
+		{Name: "qb-b-" + suffix, Slug: "qb-b-" + suffix},
+	}
+	must(tx.Create(&tags).Error)
+
+	now := time.Now().UTC()
+	ids := make([]uuid.UUID, 0, largePage)
+	for i := 0; i < largePage; i++ {
+		article := domain.Article{
+			Title:       fmt.Sprintf("%s %d", budgetSearchTerm, i),
+			Slug:        fmt.Sprintf("query-budget-%s-%d", suffix, i),
+			Content:     budgetSearchTerm,
+			AuthorID:    author.ID,
+			CategoryID:  categories[i].ID,
+			Status:      domain.StatusPublished,
+			PublishedAt: &now,
+			Tags:        tags,
+			Images:      []domain.ArticleImage{{ImageURL: "/uploads/query-budget.png", IsPrimary: true}},
+		}
+		must(tx.Create(&article).Error)
+		ids = append(ids, article.ID)
+	}
+
+	for i := 0; i < largePage; i++ {
+		top := domain.Comment{ArticleID: ids[0], UserID: author.ID, Content: "top", ReplyCount: 2}
+		top.PlaceInThread(nil)
+		must(tx.Create(&top).Error)
+		for j := 0; j < 2; j++ {
+			reply := domain.Comment{ArticleID: ids[0], UserID: author.ID, Content: "reply", ParentID: &top.ID}
+			reply.PlaceInThread(&top)
+			must(tx.Create(&reply).Error)
+		}
+	}
+	return ids, ids[0]
+}
@@ -2,8 +2,12 @@ package repository
 
 import (
 	"backend/internal/domain"
+	"strings"
+	"time"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ratingRepository struct {
@@ -14,36 +18,50 @@ func NewRatingRepository(db *gorm.DB) domain.RatingRepository {
 	return &ratingRepository{db: db}
 }
 
-func (r *ratingRepository) Upsert(rating *domain.ArticleRating) error {
+func (r *ratingRepository) Upsert(rating *domain.ArticleRating, priorWeight float64) error {
 	return r.db.Transaction(func(tx *gorm.DB) error {
-		// Upsert rating
-		if err := tx.Save(rating).Error; err != nil {
+		// Upsert rating; helpfulness counts are only maintained by Vote
+		if err := tx.Omit("HelpfulCount", "NotHelpfulCount", "HelpfulScore", "User").Save(rating).Error; err != nil {
 			return err
 		}
 
-		// Recalculate stats
-		var stats struct {
-			Avg   float64
-			Count int64
-		}
-		err := tx.Model(&domain.ArticleRating{}).
-			Where("article_id = ?", rating.ArticleID).
-			Select("COALESCE(AVG(score), 0) as avg, COUNT(*) as count").
-			Scan(&stats).Error
-		if err != nil {
-			return err
-		}
-
-		// Update Article denormalized fields
-		return tx.Model(&domain.Article{}).
-			Where("id = ?", rating.ArticleID).
-			Updates(map[string]interface{}{
-				"rating_avg":   stats.Avg,
-				"rating_count": stats.Count,
-			}).Error
+		return refreshArticleRating(tx, rating.ArticleID, priorWeight)
 	})
 }
 
+// refreshArticleRating recalculates the article's cached rating values from the exact
+// overall scores of its non-voided ratings, not the rounded ones
+func refreshArticleRating(tx *gorm.DB, articleID uuid.UUID, priorWeight float64) error {
+	var stats struct {
+		Avg   float64
+		Count int64
+	}
+	err := tx.Model(&domain.ArticleRating{}).
+		Where("article_id = ? AND voided_at IS NULL", articleID).
+		Select("COALESCE(AVG(" + domain.RatingOverallExpr + "), 0) as avg, COUNT(*) as count").
+		Scan(&stats).Error
+	if err != nil {
+		return err
+	}
+	priorMean, err := ratingPriorMean(tx)
+	if err != nil {
+		return err
+	}
+
+	// Update Article denormalized fields; unrated articles keep a zero score
+	score := 0.0
+	if stats.Count > 0 {
+		score = domain.BayesianRating(stats.Avg, stats.Count, priorMean, priorWeight)
+	}
+	return tx.Model(&domain.Article{}).
+		Where("id = ?", articleID).
+		Updates(map[string]interface{}{
+			"rating_avg":   stats.Avg,
+			"rating_count": stats.Count,
+			"rating_score": score,
+		}).Error
+}
+
 func (r *ratingRepository) GetByArticleAndUser(articleID, userID string) (*domain.ArticleRating, error) {
 	var rating domain.ArticleRating
 	err := r.db.Where("article_id = ? AND user_id = ?", articleID, userID).First(&rating).Error
@@ -59,8 +77,385 @@ func (r *ratingRepository) GetStats(articleID string) (float64, int64, error) {
 		Count int64
 	}
 	err := r.db.Model(&domain.ArticleRating{}).
-		Where("article_id = ?", articleID).
-		Select("COALESCE(AVG(score), 0) as avg, COUNT(*) as count").
+		Where("article_id = ? AND voided_at IS NULL", articleID).
+		Select("COALESCE(AVG(" + domain.RatingOverallExpr + "), 0) as avg, COUNT(*) as count").
 		Scan(&stats).Error
 	return stats.Avg, stats.Count, err
 }
+
+// GetBreakdown counts the ratings per star for the overall score and each criterion
+// in one grouped query. The prior fields are left for the caller.
+func (r *ratingRepository) GetBreakdown(articleID uuid.UUID) (*domain.RatingBreakdown, error) {
+	var rows []struct {
+		Criterion string
+		Stars     int
+		Count     int64
+	}
+	err := r.db.Raw(`
+		SELECT 'overall' AS criterion, score AS stars, COUNT(*) AS count FROM article_ratings WHERE article_id = @id AND voided_at IS NULL GROUP BY score
+		UNION ALL
+		SELECT 'content', content_score, COUNT(*) FROM article_ratings WHERE article_id = @id AND voided_at IS NULL GROUP BY content_score
+		UNION ALL
+		SELECT 'clarity', clarity_score, COUNT(*) FROM article_ratings WHERE article_id = @id AND voided_at IS NULL GROUP BY clarity_score
+		UNION ALL
+		SELECT 'relevance', relevance_score, COUNT(*) FROM article_ratings WHERE article_id = @id AND voided_at IS NULL GROUP BY relevance_score
+	`, map[string]interface{}{"id": articleID}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	breakdown := &domain.RatingBreakdown{ArticleID: articleID}
+	criteria := map[string]*domain.RatingCriterionStats{
+		"overall":   &breakdown.Overall,
+		"content":   &breakdown.Content,
+		"clarity":   &breakdown.Clarity,
+		"relevance": &breakdown.Relevance,
+	}
+	for _, row := range rows {
+		criteria[row.Criterion].Add(row.Stars, row.Count)
+	}
+	for _, c := range criteria {
+		c.Finish()
+	}
+
+	avg, count, err := r.GetStats(articleID.String())
+	if err != nil {
+		return nil, err
+	}
+	breakdown.Average = avg
+	breakdown.Count = count
+	return breakdown, nil
+}
+
+func (r *ratingRepository) PriorMean() (float64, error) {
+	return ratingPriorMean(r.db)
+}
+
+// ratingPriorMean weighs each article's exact average by its rating count, which
+// reads the small articles table instead of every rating
+func ratingPriorMean(db *gorm.DB) (float64, error) {
+	var prior struct {
+		Sum   float64
+		Count int64
+	}
+	err := db.Model(&domain.Article{}).
+		Select("COALESCE(SUM(rating_avg * rating_count), 0) AS sum, COALESCE(SUM(rating_count), 0) AS count").
+		Where("rating_count > 0").
+		Scan(&prior).Error
+	if err != nil || prior.Count == 0 {
+		return 3, err
+	}
+	return prior.Sum / float64(prior.Count), nil
+}
+
+// approvedReviews restricts q to visible reviews of the article
+func approvedReviews(q *gorm.DB, articleID uuid.UUID) *gorm.DB {
+	return q.Where("article_id = ? AND review <> '' AND review_status = ? AND voided_at IS NULL", articleID, domain.CommentApproved)
+}
+
+func (r *ratingRepository) ListReviews(articleID uuid.UUID, sort domain.ReviewSort, viewerID *uuid.UUID, offset, limit int) ([]domain.ArticleRating, int64, error) {
+	var reviews []domain.ArticleRating
+	var total int64
+
+	query := approvedReviews(r.db.Model(&domain.ArticleRating{}), articleID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "helpful_score DESC, review_updated_at DESC"
+	switch sort {
+	case domain.ReviewSortNewest:
+		order = "review_updated_at DESC"
+	case domain.ReviewSortHighest:
+		order = "score DESC, helpful_score DESC, review_updated_at DESC"
+	}
+	err := query.Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id", "full_name", "avatar_url") }).Order(order + ", id").Offset(offset).Limit(limit).Find(&reviews).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if viewerID != nil && *viewerID != uuid.Nil {
+		if err := r.attachVotes(reviews, *viewerID); err != nil {
+			return nil, 0, err
+		}
+	}
+	return reviews, total, nil
+}
+
+// attachVotes sets MyVote on the reviews the viewer voted on
+func (r *ratingRepository) attachVotes(reviews []domain.ArticleRating, viewerID uuid.UUID) error {
+	if len(reviews) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(reviews))
+	for i := range reviews {
+		ids[i] = reviews[i].ID
+	}
+	var votes []domain.ReviewVote
+	if err := r.db.Where("user_id = ? AND rating_id IN ?", viewerID, ids).Find(&votes).Error; err != nil {
+		return err
+	}
+	byRating := make(map[uuid.UUID]bool, len(votes))
+	for _, v := range votes {
+		byRating[v.RatingID] = v.Helpful
+	}
+	for i := range reviews {
+		if helpful, ok := byRating[reviews[i].ID]; ok {
+			reviews[i].MyVote = &helpful
+		}
+	}
+	return nil
+}
+
+func (r *ratingRepository) TopReviews(articleID uuid.UUID, limit int) ([]domain.ArticleRating, error) {
+	var reviews []domain.ArticleRating
+	err := approvedReviews(r.db, articleID).
+		Where("helpful_count > 0").
+		Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id", "full_name", "avatar_url") }).
+		Order("helpful_score DESC, review_updated_at DESC, id").
+		Limit(limit).
+		Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *ratingRepository) GetReviewByID(id uuid.UUID) (*domain.ArticleRating, error) {
+	var rating domain.ArticleRating
+	err := r.db.Preload("User", func(db *gorm.DB) *gorm.DB { return db.Select("id", "full_name", "avatar_url") }).Where("review <> ''").First(&rating, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// Vote locks the review so concurrent votes recount in turn
+func (r *ratingRepository) Vote(ratingID, userID uuid.UUID, helpful *bool) (*domain.ArticleRating, error) {
+	var rating domain.ArticleRating
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&rating, "id = ?", ratingID).Error; err != nil {
+			return err
+		}
+
+		if helpful == nil {
+			if err := tx.Where("rating_id = ? AND user_id = ?", ratingID, userID).Delete(&domain.ReviewVote{}).Error; err != nil {
+				return err
+			}
+		} else {
+			vote := domain.ReviewVote{RatingID: ratingID, UserID: userID, Helpful: *helpful, CreatedAt: time.Now().UTC()}
+			err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "rating_id"}, {Name: "user_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"helpful", "created_at"}),
+			}).Create(&vote).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		var counts struct {
+			Helpful    int
+			NotHelpful int
+		}
+		err := tx.Model(&domain.ReviewVote{}).
+			Select("COUNT(*) FILTER (WHERE helpful) AS helpful, COUNT(*) FILTER (WHERE NOT helpful) AS not_helpful").
+			Where("rating_id = ?", ratingID).
+			Scan(&counts).Error
+		if err != nil {
+			return err
+		}
+		rating.HelpfulCount = counts.Helpful
+		rating.NotHelpfulCount = counts.NotHelpful
+		rating.HelpfulScore = domain.WilsonScore(counts.Helpful, counts.Helpful+counts.NotHelpful)
+		return tx.Model(&domain.ArticleRating{}).Where("id = ?", ratingID).Updates(map[string]interface{}{
+			"helpful_count":     rating.HelpfulCount,
+			"not_helpful_count": rating.NotHelpfulCount,
+			"helpful_score":     rating.HelpfulScore,
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+// GetReviewQueue lists reviews for moderators, oldest first so the queue is worked in order
+func (r *ratingRepository) GetReviewQueue(filter domain.ReviewFilter, offset, limit int) ([]domain.ArticleRating, int64, error) {
+	var reviews []domain.ArticleRating
+	var total int64
+
+	query := r.db.Model(&domain.ArticleRating{}).Where("review <> ''")
+	if filter.Status != "" {
+		query = query.Where("review_status = ?", filter.Status)
+	}
+	if filter.ArticleID != nil {
+		query = query.Where("article_id = ?", *filter.ArticleID)
+	}
+	if filter.Search != "" {
+		query = query.Where("review ILIKE ?", "%"+filter.Search+"%")
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err := query.Preload("User").Order("review_updated_at ASC, id").Offset(offset).Limit(limit).Find(&reviews).Error
+	return reviews, total, err
+}
+
+func (r *ratingRepository) GetReviewsByIDs(ids []uuid.UUID) ([]domain.ArticleRating, error) {
+	var reviews []domain.ArticleRating
+	if len(ids) == 0 {
+		return reviews, nil
+	}
+	err := r.db.Where("id IN ? AND review <> ''", ids).Find(&reviews).Error
+	return reviews, err
+}
+
+func (r *ratingRepository) SetReviewStatus(ids []uuid.UUID, status domain.CommentStatus, reason string, moderatorID uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&domain.ArticleRating{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+		"review_status":     status,
+		"moderation_reason": reason,
+		"moderated_by":      moderatorID,
+		"moderated_at":      at,
+	}).Error
+}
+
+func (r *ratingRepository) GetByID(id uuid.UUID) (*domain.ArticleRating, error) {
+	var rating domain.ArticleRating
+	err := r.db.Preload("User").First(&rating, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+	return &rating, nil
+}
+
+func (r *ratingRepository) Void(id, voidedBy uuid.UUID, reason string, at time.Time, priorWeight float64) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var rating domain.ArticleRating
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&rating, "id = ?", id).Error; err != nil {
+			return err
+		}
+		err := tx.Model(&domain.ArticleRating{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"voided_at":   at,
+			"voided_by":   voidedBy,
+			"void_reason": reason,
+		}).Error
+		if err != nil {
+			return err
+		}
+		return refreshArticleRating(tx, rating.ArticleID, priorWeight)
+	})
+}
+
+// Reconcile compares every article's cached values with its ratings in one query,
+// rewrites the drifted rows and then refreshes every rating_score, since fixing the
+// averages also moves the site-wide prior mean
+func (r *ratingRepository) Reconcile(priorWeight float64) ([]domain.RatingDrift, int64, error) {
+	var drifts []domain.RatingDrift
+	var checked int64
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&domain.Article{}).Count(&checked).Error; err != nil {
+			return err
+		}
+		err := tx.Raw(`
+			SELECT a.id AS article_id, a.title,
+			       a.rating_avg AS stored_avg, COALESCE(r.avg, 0) AS actual_avg,
+			       a.rating_count AS stored_count, COALESCE(r.count, 0) AS actual_count
+			FROM articles a
+			LEFT JOIN (
+				SELECT article_id, AVG(` + domain.RatingOverallExpr + `) AS avg, COUNT(*) AS count
+				FROM article_ratings
+				WHERE voided_at IS NULL
+				GROUP BY article_id
+			) r ON r.article_id = a.id
+			WHERE a.rating_count <> COALESCE(r.count, 0)
+			   OR ABS(a.rating_avg - COALESCE(r.avg, 0)) > 0.000001
+			ORDER BY a.id
+		`).Scan(&drifts).Error
+		if err != nil {
+			return err
+		}
+
+		for _, d := range drifts {
+			err := tx.Model(&domain.Article{}).Where("id = ?", d.ArticleID).Updates(map[string]interface{}{
+				"rating_avg":   d.ActualAvg,
+				"rating_count": d.ActualCount,
+			}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		priorMean, err := ratingPriorMean(tx)
+		if err != nil {
+			return err
+		}
+		return tx.Exec(`
+			UPDATE articles
+			SET rating_score = CASE WHEN rating_count > 0
+				THEN (@w * @mean + rating_count * rating_avg) / (@w + rating_count) ELSE 0 END
+			WHERE rating_score IS DISTINCT FROM CASE WHEN rating_count > 0
+				THEN (@w * @mean + rating_count * rating_avg) / (@w + rating_count) ELSE 0 END
+		`, map[string]interface{}{"w": priorWeight, "mean": priorMean}).Error
+	})
+	return drifts, checked, err
+}
+
+// FindAnomalies groups recent 1 and 5 star ratings from accounts that were younger
+// than opts.MaxAccountAge when they rated, per article and score
+func (r *ratingRepository) FindAnomalies(opts domain.RatingAnomalyOptions) ([]domain.RatingAnomaly, error) {
+	var rows []struct {
+		ArticleID uuid.UUID
+		Title     string
+		Score     int
+		Count     int
+		Total     int
+		FirstAt   time.Time
+		LastAt    time.Time
+		RatingIDs string
+	}
+	err := r.db.Raw(`
+		SELECT r.article_id, a.title, r.score,
+		       COUNT(*) AS count,
+		       (SELECT COUNT(*) FROM article_ratings t
+		        WHERE t.article_id = r.article_id AND t.voided_at IS NULL AND t.updated_at >= @since) AS total,
+		       MIN(r.updated_at) AS first_at, MAX(r.updated_at) AS last_at,
+		       string_agg(r.id::text, ',' ORDER BY r.updated_at) AS rating_ids
+		FROM article_ratings r
+		JOIN users u ON u.id = r.user_id
+		JOIN articles a ON a.id = r.article_id
+		WHERE r.voided_at IS NULL
+		  AND r.updated_at >= @since
+		  AND r.score IN (1, 5)
+		  AND r.updated_at - u.created_at < make_interval(secs => @age)
+		GROUP BY r.article_id, a.title, r.score
+		HAVING COUNT(*) >= @min
+		ORDER BY count DESC, last_at DESC
+	`, map[string]interface{}{
+		"since": opts.Since,
+		"age":   opts.MaxAccountAge.Seconds(),
+		"min":   opts.MinCount,
+	}).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	anomalies := make([]domain.RatingAnomaly, 0, len(rows))
+	for _, row := range rows {
+		anomaly := domain.RatingAnomaly{
+			ArticleID: row.ArticleID,
+			Title:     row.Title,
+			Score:     row.Score,
+			Count:     row.Count,
+			Total:     row.Total,
+			FirstAt:   row.FirstAt,
+			LastAt:    row.LastAt,
+		}
+		for _, raw := range strings.Split(row.RatingIDs, ",") {
+			if id, err := uuid.Parse(raw); err == nil {
+				anomaly.RatingIDs = append(anomaly.RatingIDs, id)
+			}
+		}
+		anomalies = append(anomalies, anomaly)
+	}
+	return anomalies, nil
+}
@@ -0,0 +1,232 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+type reportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) domain.ReportRepository {
+	return &reportRepository{db: db}
+}
+
+func (r *reportRepository) Create(report *domain.ContentReport) error {
+	return r.db.Create(report).Error
+}
+
+func (r *reportRepository) GetByIDs(ids []uuid.UUID) ([]domain.ContentReport, error) {
+	var reports []domain.ContentReport
+	if len(ids) == 0 {
+		return reports, nil
+	}
+	err := r.db.Where("id IN ?", ids).Find(&reports).Error
+	return reports, err
+}
+
+// openReportsOf counts the open reports on the same item as the outer row
+const openReportsOf = "(SELECT COUNT(*) FROM content_reports o WHERE o.target_type = content_reports.target_type AND o.target_id = content_reports.target_id AND o.status = 'OPEN')"
+
+func (r *reportRepository) List(filter domain.ReportFilter, offset, limit int) ([]domain.ContentReport, int64, error) {
+	var reports []domain.ContentReport
+	var total int64
+
+	query := r.db.Model(&domain.ContentReport{})
+	if filter.Status != "" {
+		query = query.Where("content_reports.status = ?", filter.Status)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("content_reports.target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != nil {
+		query = query.Where("content_reports.target_id = ?", *filter.TargetID)
+	}
+	if filter.Reason != "" {
+		query = query.Where("content_reports.reason = ?", filter.Reason)
+	}
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	order := "content_reports.created_at ASC, content_reports.id ASC"
+	if filter.MostReported {
+		order = openReportsOf + " DESC, " + order
+	}
+	err := query.
+		Preload("Reporter", func(db *gorm.DB) *gorm.DB { return db.Select("id", "full_name", "avatar_url") }).
+		Order(order).
+		Offset(offset).
+		Limit(limit).
+		Find(&reports).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	if err := r.attachTargets(reports); err != nil {
+		return nil, 0, err
+	}
+	return reports, total, nil
+}
+
+// attachTargets loads a short view of every reported item and its open report count, in four queries at most
+func (r *reportRepository) attachTargets(reports []domain.ContentReport) error {
+	if len(reports) == 0 {
+		return nil
+	}
+	var commentIDs, articleIDs, targetIDs []uuid.UUID
+	for _, rep := range reports {
+		targetIDs = append(targetIDs, rep.TargetID)
+		if rep.TargetType == domain.ReportTargetComment {
+			commentIDs = append(commentIDs, rep.TargetID)
+		} else {
+			articleIDs = append(articleIDs, rep.TargetID)
+		}
+	}
+
+	targets := make(map[uuid.UUID]*domain.ReportTarget)
+	if len(commentIDs) > 0 {
+		var comments []domain.Comment
+		if err := r.db.Select("id", "article_id", "user_id", "content", "status", "is_deleted", "created_at").
+			Preload("Article", func(db *gorm.DB) *gorm.DB { return db.Select("id", "title", "slug") }).
+			Where("id IN ?", commentIDs).Find(&comments).Error; err != nil {
+			return err
+		}
+		for _, c := range comments {
+			t := &domain.ReportTarget{
+				Excerpt:   excerpt(c.Content, 300),
+				AuthorID:  c.UserID,
+				ArticleID: c.ArticleID,
+				Status:    string(c.Status),
+				Deleted:   c.IsDeleted,
+				CreatedAt: &c.CreatedAt,
+			}
+			if c.Article != nil {
+				t.Title, t.Slug = c.Article.Title, c.Article.Slug
+			}
+			targets[c.ID] = t
+		}
+	}
+	if len(articleIDs) > 0 {
+		var articles []domain.Article
+		if err := r.db.Select("id", "title", "slug", "summary", "author_id", "status", "published_at").
+			Where("id IN ?", articleIDs).Find(&articles).Error; err != nil {
+			return err
+		}
+		for _, a := range articles {
+			targets[a.ID] = &domain.ReportTarget{
+				Title:     a.Title,
+				Excerpt:   excerpt(a.Summary, 300),
+				AuthorID:  a.AuthorID,
+				ArticleID: a.ID,
+				Slug:      a.Slug,
+				Status:    string(a.Status),
+				CreatedAt: a.PublishedAt,
+			}
+		}
+	}
+
+	counts, err := r.CountOpenByTargets(targetIDs)
+	if err != nil {
+		return err
+	}
+	for i := range reports {
+		reports[i].Target = targets[reports[i].TargetID]
+		reports[i].OpenReports = counts[reports[i].TargetID]
+	}
+	return nil
+}
+
+func excerpt(s string, n int) string {
+	if r := []rune(s); len(r) > n {
+		return string(r[:n]) + "…"
+	}
+	return s
+}
+
+func (r *reportRepository) CountOpen(targetType domain.ReportTargetType, targetID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.ContentReport{}).
+		Where("target_type = ? AND target_id = ? AND status = ?", targetType, targetID, domain.ReportOpen).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *reportRepository) CountOpenByTargets(targetIDs []uuid.UUID) (map[uuid.UUID]int64, error) {
+	counts := make(map[uuid.UUID]int64)
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		TargetID uuid.UUID
+		Count    int64
+	}
+	if err := r.db.Model(&domain.ContentReport{}).
+		Select("target_id, COUNT(*) AS count").
+		Where("target_id IN ? AND status = ?", targetIDs, domain.ReportOpen).
+		Group("target_id").
+		Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.TargetID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *reportRepository) CountByReporterSince(reporterID uuid.UUID, since time.Time) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.ContentReport{}).
+		Where("reporter_id = ? AND created_at >= ?", reporterID, since).
+		Count(&count).Error
+	return count, err
+}
+
+func (r *reportRepository) Resolve(ids []uuid.UUID, status domain.ReportStatus, note string, moderatorID uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	updates := map[string]interface{}{
+		"status":          status,
+		"resolution_note": note,
+		"resolved_by":     moderatorID,
+		"resolved_at":     at,
+		"updated_at":      at,
+	}
+	// Re-opening clears the previous decision
+	if status == domain.ReportOpen {
+		updates["resolved_by"] = nil
+		updates["resolved_at"] = nil
+	}
+	return r.db.Model(&domain.ContentReport{}).Where("id IN ?", ids).Updates(updates).Error
+}
+
+func (r *reportRepository) ResolveOpenForTargets(targetType domain.ReportTargetType, targetIDs []uuid.UUID, status domain.ReportStatus, note string, moderatorID uuid.UUID, at time.Time) (int64, error) {
+	if len(targetIDs) == 0 {
+		return 0, nil
+	}
+	res := r.db.Model(&domain.ContentReport{}).
+		Where("target_type = ? AND target_id IN ? AND status = ?", targetType, targetIDs, domain.ReportOpen).
+		Updates(map[string]interface{}{
+			"status":          status,
+			"resolution_note": note,
+			"resolved_by":     moderatorID,
+			"resolved_at":     at,
+			"updated_at":      at,
+		})
+	return res.RowsAffected, res.Error
+}
+
+func (r *reportRepository) ModeratorIDs() ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	err := r.db.Table("users").
+		Joins("JOIN user_roles ON user_roles.user_id = users.id").
+		Joins("JOIN roles ON roles.id = user_roles.role_id").
+		Where("users.is_active = true AND roles.name IN ?", []string{"ADMIN", "EDITOR"}).
+		Distinct().
+		Pluck("users.id", &ids).Error
+	return ids, err
+}
@@ -0,0 +1,217 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type savedSearchRepository struct {
+	db *gorm.DB
+}
+
+func NewSavedSearchRepository(db *gorm.DB) domain.SavedSearchRepository {
+	return &savedSearchRepository{db: db}
+}
+
+func (r *savedSearchRepository) Create(search *domain.SavedSearch) error {
+	return r.db.Create(search).Error
+}
+
+func (r *savedSearchRepository) Update(search *domain.SavedSearch) error {
+	return r.db.Model(search).
+		Select("Name", "Query", "CategoryID", "Frequency", "UpdatedAt").
+		Updates(search).Error
+}
+
+func (r *savedSearchRepository) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("saved_search_id = ?", id).Delete(&domain.SavedSearchMatch{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.SavedSearch{}, "id = ?", id).Error
+	})
+}
+
+func (r *savedSearchRepository) GetByID(id uuid.UUID) (*domain.SavedSearch, error) {
+	var search domain.SavedSearch
+	if err := r.db.First(&search, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	return &search, nil
+}
+
+func (r *savedSearchRepository) ListByUser(userID uuid.UUID) ([]domain.SavedSearch, error) {
+	searches := []domain.SavedSearch{}
+	if err := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&searches).Error; err != nil {
+		return nil, err
+	}
+	if len(searches) == 0 {
+		return searches, nil
+	}
+
+	ids := make([]uuid.UUID, len(searches))
+	for i, s := range searches {
+		ids[i] = s.ID
+	}
+
+	var counts []struct {
+		SavedSearchID uuid.UUID
+		Unread        int64
+	}
+	if err := r.db.Model(&domain.SavedSearchMatch{}).
+		Select("saved_search_id, COUNT(*) AS unread").
+		Where("saved_search_id IN ? AND read_at IS NULL", ids).
+		Group("saved_search_id").
+		Scan(&counts).Error; err != nil {
+		return nil, err
+	}
+
+	unread := make(map[uuid.UUID]int64, len(counts))
+	for _, c := range counts {
+		unread[c.SavedSearchID] = c.Unread
+	}
+	for i := range searches {
+		searches[i].UnreadCount = unread[searches[i].ID]
+	}
+	return searches, nil
+}
+
+func (r *savedSearchRepository) CountByUser(userID uuid.UUID) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.SavedSearch{}).Where("user_id = ?", userID).Count(&count).Error
+	return count, err
+}
+
+func (r *savedSearchRepository) ListAlerting() ([]domain.SavedSearch, error) {
+	var searches []domain.SavedSearch
+	err := r.db.Where("frequency <> ?", domain.SavedSearchNone).Order("created_at ASC").Find(&searches).Error
+	return searches, err
+}
+
+func (r *savedSearchRepository) MarkRun(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&domain.SavedSearch{}).Where("id = ?", id).UpdateColumn("last_run_at", at).Error
+}
+
+func (r *savedSearchRepository) MarkDigested(ids []uuid.UUID, at time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return r.db.Model(&domain.SavedSearch{}).Where("id IN ?", ids).UpdateColumn("last_digest_at", at).Error
+}
+
+func (r *savedSearchRepository) AddMatches(searchID uuid.UUID, articleIDs []uuid.UUID) ([]domain.SavedSearchMatch, error) {
+	if len(articleIDs) == 0 {
+		return nil, nil
+	}
+
+	var existing []uuid.UUID
+	if err := r.db.Model(&domain.SavedSearchMatch{}).
+		Where("saved_search_id = ? AND article_id IN ?", searchID, articleIDs).
+		Pluck("article_id", &existing).Error; err != nil {
+		return nil, err
+	}
+	seen := make(map[uuid.UUID]bool, len(existing))
+	for _, id := range existing {
+		seen[id] = true
+	}
+
+	now := time.Now().UTC()
+	matches := make([]domain.SavedSearchMatch, 0, len(articleIDs))
+	for _, id := range articleIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		matches = append(matches, domain.SavedSearchMatch{
+			ID:            uuid.New(),
+			SavedSearchID: searchID,
+			ArticleID:     id,
+			MatchedAt:     now,
+		})
+	}
+	if len(matches) == 0 {
+		return nil, nil
+	}
+
+	if err := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&matches).Error; err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func (r *savedSearchRepository) GetMatches(searchID uuid.UUID, offset, limit int) ([]domain.SavedSearchMatch, int64, error) {
+	var matches []domain.SavedSearchMatch
+	var total int64
+
+	query := r.db.Model(&domain.SavedSearchMatch{}).Where("saved_search_id = ?", searchID)
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := query.Order("matched_at DESC, id DESC").Offset(offset).Limit(limit).Find(&matches).Error; err != nil {
+		return nil, 0, err
+	}
+	if err := r.attachArticles(matches); err != nil {
+		return nil, 0, err
+	}
+	return matches, total, nil
+}
+
+func (r *savedSearchRepository) GetUndelivered(searchIDs []uuid.UUID) ([]domain.SavedSearchMatch, error) {
+	if len(searchIDs) == 0 {
+		return nil, nil
+	}
+	var matches []domain.SavedSearchMatch
+	if err := r.db.Where("saved_search_id IN ? AND delivered_at IS NULL", searchIDs).
+		Order("matched_at ASC").Find(&matches).Error; err != nil {
+		return nil, err
+	}
+	if err := r.attachArticles(matches); err != nil {
+		return nil, err
+	}
+	return matches, nil
+}
+
+func (r *savedSearchRepository) MarkDelivered(matchIDs []uuid.UUID, at time.Time) error {
+	if len(matchIDs) == 0 {
+		return nil
+	}
+	return r.db.Model(&domain.SavedSearchMatch{}).Where("id IN ?", matchIDs).UpdateColumn("delivered_at", at).Error
+}
+
+func (r *savedSearchRepository) MarkRead(searchID uuid.UUID, at time.Time) error {
+	return r.db.Model(&domain.SavedSearchMatch{}).
+		Where("saved_search_id = ? AND read_at IS NULL", searchID).
+		UpdateColumn("read_at", at).Error
+}
+
+// attachArticles loads listing-weight articles for matches in a fixed number of queries
+func (r *savedSearchRepository) attachArticles(matches []domain.SavedSearchMatch) error {
+	if len(matches) == 0 {
+		return nil
+	}
+	ids := make([]uuid.UUID, len(matches))
+	for i, m := range matches {
+		ids[i] = m.ArticleID
+	}
+
+	var articles []domain.Article
+	if err := r.db.Where("id IN ?", ids).Find(&articles).Error; err != nil {
+		return err
+	}
+	if err := loadArticleListAssociations(r.db, articles); err != nil {
+		return err
+	}
+
+	byID := make(map[uuid.UUID]*domain.Article, len(articles))
+	for i := range articles {
+		byID[articles[i].ID] = &articles[i]
+	}
+	for i := range matches {
+		matches[i].Article = byID[matches[i].ArticleID]
+	}
+	return nil
+}
@@ -0,0 +1,61 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// postgresSearchIndex serves search straight from PostgreSQL FTS + pg_trgm.
+// The search_vector column is maintained by a database trigger, so the
+// write-side methods are no-ops.
+type postgresSearchIndex struct {
+	repo *articleRepository
+}
+
+func NewPostgresSearchIndex(db *gorm.DB) domain.SearchIndex {
+	return &postgresSearchIndex{repo: &articleRepository{db: db}}
+}
+
+func (p *postgresSearchIndex) Name() string {
+	return "postgres"
+}
+
+func (p *postgresSearchIndex) Search(query string, offset, limit int, filters map[string]interface{}) ([]domain.Article, int64, error) {
+	return p.repo.SearchArticles(query, offset, limit, filters)
+}
+
+func (p *postgresSearchIndex) IndexArticle(article *domain.Article) error {
+	return nil
+}
+
+func (p *postgresSearchIndex) RemoveArticle(id uuid.UUID) error {
+	return nil
+}
+
+// BeginRebuild returns a no-op build: the articles_search_vector_update trigger keeps
+// search_vector current, and rewriting every row would also bump updated_at
+func (p *postgresSearchIndex) BeginRebuild() (domain.SearchIndexBuild, error) {
+	return &postgresSearchBuild{}, nil
+}
+
+func (p *postgresSearchIndex) Flush() error {
+	return nil
+}
+
+func (p *postgresSearchIndex) Close() error {
+	return nil
+}
+
+type postgresSearchBuild struct{}
+
+func (b *postgresSearchBuild) IndexArticle(article *domain.Article) error {
+	return nil
+}
+
+func (b *postgresSearchBuild) Commit() error {
+	return nil
+}
+
+func (b *postgresSearchBuild) Discard() {}
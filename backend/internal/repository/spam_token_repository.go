@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type spamTokenRepository struct {
+	db *gorm.DB
+}
+
+func NewSpamTokenRepository(db *gorm.DB) domain.SpamTokenRepository {
+	return &spamTokenRepository{db: db}
+}
+
+func (r *spamTokenRepository) GetAll() ([]domain.SpamToken, error) {
+	var tokens []domain.SpamToken
+	err := r.db.Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *spamTokenRepository) Increment(tokens []string, spam bool) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	rows := make([]domain.SpamToken, len(tokens))
+	column := "ham_count"
+	for i, t := range tokens {
+		rows[i] = domain.SpamToken{Token: t}
+		if spam {
+			rows[i].SpamCount = 1
+		} else {
+			rows[i].HamCount = 1
+		}
+	}
+	if spam {
+		column = "spam_count"
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "token"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{column: gorm.Expr("spam_tokens." + column + " + 1")}),
+	}).CreateInBatches(&rows, 500).Error
+}
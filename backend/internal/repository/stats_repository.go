@@ -142,30 +142,34 @@ func (r *statsRepository) GetRecentActivities(limit int) ([]domain.AdminActivity
 func (r *statsRepository) GetAnalytics(days int) ([]domain.AnalyticsData, error) {
 	analytics := []domain.AnalyticsData{}
 
-	// Improved query to handle dates with 0 values and actual views from article_views
+	// Improved query to handle dates with 0 values; views, uniques, comments and
+	// ratings come from the daily site rollups, articles are counted in the window only
 	err := r.db.Raw(`
 		WITH date_series AS (
 			SELECT CAST(CURRENT_DATE - (i || ' day')::interval AS DATE) as d
-			FROM generate_series(0, ?) i
+			FROM generate_series(0, @last) i
 		)
 		SELECT 
 			TO_CHAR(ds.d, 'DD/MM') as date,
 			COALESCE(a.count, 0) as articles,
-			COALESCE(v.count, 0) as views
+			COALESCE(v.views, 0) as views,
+			COALESCE(v.uniques, 0) as uniques,
+			COALESCE(v.comments, 0) as comments,
+			COALESCE(v.ratings, 0) as ratings
 		FROM date_series ds
 		LEFT JOIN (
 			SELECT CAST(created_at AS DATE) as d, COUNT(*) as count
 			FROM articles
+			WHERE created_at >= CURRENT_DATE - @last
 			GROUP BY d
 		) a ON ds.d = a.d
 		LEFT JOIN (
-			SELECT CAST(viewed_at AS DATE) as d, COUNT(*) as count
-			FROM article_views
-			WHERE session_duration >= 30
-			GROUP BY d
+			SELECT CAST(bucket AS DATE) as d, views, uniques, comments, ratings
+			FROM analytics_rollups
+			WHERE granularity = 'DAY' AND dimension = 'SITE' AND bucket >= CURRENT_DATE - @last
 		) v ON ds.d = v.d
 		ORDER BY ds.d ASC
-	`, days-1).Scan(&analytics).Error
+	`, map[string]interface{}{"last": days - 1}).Scan(&analytics).Error
 
 	return analytics, err
 }
@@ -0,0 +1,140 @@
+package repository
+
+import (
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type userBlockRepository struct {
+	db *gorm.DB
+}
+
+func NewUserBlockRepository(db *gorm.DB) domain.UserBlockRepository {
+	return &userBlockRepository{db: db}
+}
+
+func (r *userBlockRepository) Add(block *domain.UserBlock) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(block).Error
+}
+
+func (r *userBlockRepository) Remove(blockerID, blockedID uuid.UUID, kind domain.BlockKind) (bool, error) {
+	res := r.db.Where("blocker_id = ? AND blocked_id = ? AND kind = ?", blockerID, blockedID, kind).Delete(&domain.UserBlock{})
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *userBlockRepository) List(blockerID uuid.UUID, kind domain.BlockKind, offset, limit int) ([]domain.UserBlock, int64, error) {
+	query := r.db.Model(&domain.UserBlock{}).Where("blocker_id = ?", blockerID)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	blocks := []domain.UserBlock{}
+	err := query.
+		Preload("Blocked", func(db *gorm.DB) *gorm.DB { return db.Select("id", "full_name", "avatar_url") }).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&blocks).Error
+	return blocks, total, err
+}
+
+func (r *userBlockRepository) Count(blockerID uuid.UUID, kind domain.BlockKind) (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.UserBlock{}).Where("blocker_id = ? AND kind = ?", blockerID, kind).Count(&count).Error
+	return count, err
+}
+
+func (r *userBlockRepository) IsBlocked(blockerID, blockedID uuid.UUID) (bool, error) {
+	var count int64
+	err := r.db.Model(&domain.UserBlock{}).
+		Where("blocker_id = ? AND blocked_id = ? AND kind = ?", blockerID, blockedID, domain.BlockKindBlock).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func (r *userBlockRepository) BlockedBy(blockedID uuid.UUID, blockerIDs []uuid.UUID) (map[uuid.UUID]bool, error) {
+	blockers := make(map[uuid.UUID]bool)
+	if len(blockerIDs) == 0 {
+		return blockers, nil
+	}
+	var ids []uuid.UUID
+	if err := r.db.Model(&domain.UserBlock{}).
+		Where("blocked_id = ? AND blocker_id IN ? AND kind = ?", blockedID, blockerIDs, domain.BlockKindBlock).
+		Pluck("blocker_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	for _, id := range ids {
+		blockers[id] = true
+	}
+	return blockers, nil
+}
+
+func (r *userBlockRepository) HiddenAuthors(viewerID uuid.UUID) (map[uuid.UUID]bool, error) {
+	var ids []uuid.UUID
+	if err := r.db.Model(&domain.UserBlock{}).
+		Where("blocker_id = ?", viewerID).
+		Distinct().
+		Pluck("blocked_id", &ids).Error; err != nil {
+		return nil, err
+	}
+	hidden := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		hidden[id] = true
+	}
+	return hidden, nil
+}
+
+// Stats lists users who were blocked or muted at least once, most blocked first
+func (r *userBlockRepository) Stats(offset, limit int) ([]domain.UserBlockStats, int64, error) {
+	var total int64
+	if err := r.db.Model(&domain.UserBlock{}).Distinct("blocked_id").Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	received := r.db.Model(&domain.UserBlock{}).
+		Select("blocked_id, "+
+			"COUNT(*) FILTER (WHERE kind = ?) AS blocked_by_count, "+
+			"COUNT(*) FILTER (WHERE kind = ?) AS muted_by_count, "+
+			"MAX(created_at) FILTER (WHERE kind = ?) AS last_blocked_at",
+			domain.BlockKindBlock, domain.BlockKindMute, domain.BlockKindBlock).
+		Group("blocked_id")
+	blocking := r.db.Model(&domain.UserBlock{}).
+		Select("blocker_id, COUNT(*) AS blocking_count").
+		Where("kind = ?", domain.BlockKindBlock).
+		Group("blocker_id")
+
+	stats := []domain.UserBlockStats{}
+	err := r.db.Table("(?) AS received", received).
+		Select("received.blocked_id AS user_id, users.full_name, users.email, "+
+			"received.blocked_by_count, received.muted_by_count, received.last_blocked_at, "+
+			"COALESCE(blocking.blocking_count, 0) AS blocking_count").
+		Joins("JOIN users ON users.id = received.blocked_id").
+		Joins("LEFT JOIN (?) AS blocking ON blocking.blocker_id = received.blocked_id", blocking).
+		Order("received.blocked_by_count DESC, received.muted_by_count DESC, received.blocked_id").
+		Offset(offset).
+		Limit(limit).
+		Scan(&stats).Error
+	return stats, total, err
+}
+
+func (r *userBlockRepository) ListReceived(blockedID uuid.UUID, offset, limit int) ([]domain.UserBlock, int64, error) {
+	query := r.db.Model(&domain.UserBlock{}).Where("blocked_id = ?", blockedID)
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	blocks := []domain.UserBlock{}
+	err := query.
+		Preload("Blocker", func(db *gorm.DB) *gorm.DB { return db.Select("id", "full_name", "email", "avatar_url") }).
+		Order("created_at DESC").
+		Offset(offset).
+		Limit(limit).
+		Find(&blocks).Error
+	return blocks, total, err
+}
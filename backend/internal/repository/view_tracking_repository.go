@@ -2,6 +2,7 @@ package repository
 
 import (
 	"backend/internal/domain"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
@@ -27,30 +28,18 @@ func (r *viewTrackingRepository) RecordView(view *domain.ArticleView) error {
 	return r.db.Create(view).Error
 }
 
-// GetValidViewCount returns the count of valid views for an article
+// GetValidViewCount returns the count of valid human views for an article. It reads
+// the view_count kept by the ingestion pipeline, since raw views are pruned over time.
 func (r *viewTrackingRepository) GetValidViewCount(articleID uuid.UUID) (int64, error) {
 	var count int64
-	err := r.db.Model(&domain.ArticleView{}).
-		Where("article_id = ? AND session_duration >= 30", articleID).
-		Count(&count).Error
+	err := r.db.Model(&domain.Article{}).
+		Select("view_count").
+		Where("id = ?", articleID).
+		Scan(&count).Error
 
 	return count, err
 }
 
-// HasRecentView checks if the IP address has viewed this article recently
-// This is used for deduplication - same IP won't count multiple times within the duration
-func (r *viewTrackingRepository) HasRecentView(articleID uuid.UUID, ipAddress string, within time.Duration) (bool, error) {
-	var count int64
-	cutoffTime := time.Now().Add(-within)
-
-	err := r.db.Model(&domain.ArticleView{}).
-		Where("article_id = ? AND ip_address = ? AND viewed_at > ? AND session_duration >= 30",
-			articleID, ipAddress, cutoffTime).
-		Count(&count).Error
-
-	return count > 0, err
-}
-
 // GetViewsByArticle returns all valid views for an article with pagination
 func (r *viewTrackingRepository) GetViewsByArticle(articleID uuid.UUID, page, limit int) ([]domain.ArticleView, int64, error) {
 	var views []domain.ArticleView
@@ -86,3 +75,169 @@ func (r *viewTrackingRepository) GetRecentViews(limit int) ([]domain.ArticleView
 
 	return views, err
 }
+
+// RecordViews inserts the views in multi-row INSERT statements
+func (r *viewTrackingRepository) RecordViews(views []domain.ArticleView) error {
+	if len(views) == 0 {
+		return nil
+	}
+	return r.db.CreateInBatches(views, 500).Error
+}
+
+// RecentViewKeys checks a whole batch against article_views in one query
+func (r *viewTrackingRepository) RecentViewKeys(keys []domain.ViewKey, since time.Time) (map[domain.ViewKey]bool, error) {
+	found := make(map[domain.ViewKey]bool)
+	if len(keys) == 0 {
+		return found, nil
+	}
+	pairs := make([][]interface{}, len(keys))
+	for i, k := range keys {
+		pairs[i] = []interface{}{k.ArticleID, k.VisitorID}
+	}
+
+	var rows []struct {
+		ArticleID uuid.UUID
+		VisitorID string
+	}
+	err := r.db.Model(&domain.ArticleView{}).
+		Select("DISTINCT article_id, visitor_id").
+		Where("viewed_at >= ? AND session_duration >= 30", since).
+		Where("(article_id, visitor_id) IN ?", pairs).
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		found[domain.ViewKey{ArticleID: row.ArticleID, VisitorID: row.VisitorID}] = true
+	}
+	return found, nil
+}
+
+func (r *viewTrackingRepository) TrafficStats(from, to time.Time, limit int) (*domain.ViewTrafficStats, error) {
+	stats := &domain.ViewTrafficStats{From: from, To: to}
+	base := func() *gorm.DB {
+		return r.db.Model(&domain.ArticleView{}).Where("viewed_at >= ? AND viewed_at < ?", from, to)
+	}
+
+	err := base().
+		Select("classification AS class, COUNT(*) AS count").
+		Group("classification").
+		Order("count DESC").
+		Scan(&stats.ByClass).Error
+	if err != nil {
+		return nil, err
+	}
+
+	// The top lists only cover filtered traffic
+	breakdowns := []struct {
+		column string
+		out    *[]domain.ViewTrafficCount
+	}{
+		{"class_reason", &stats.TopReasons},
+		{"user_agent", &stats.TopUserAgents},
+		{"host(ip_address)", &stats.TopIPs},
+	}
+	for _, b := range breakdowns {
+		err := base().
+			Select("classification AS class, "+b.column+" AS key, COUNT(*) AS count").
+			Where("classification <> ?", domain.ViewHuman).
+			Group("classification, " + b.column).
+			Order("count DESC").
+			Limit(limit).
+			Scan(b.out).Error
+		if err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func (r *viewTrackingRepository) AnonymizeBefore(before time.Time) (int64, error) {
+	result := r.db.Model(&domain.ArticleView{}).
+		Where("viewed_at < ? AND (ip_address IS NOT NULL OR user_agent <> '')", before).
+		Updates(map[string]interface{}{"ip_address": nil, "user_agent": ""})
+	return result.RowsAffected, result.Error
+}
+
+// dailyUniques restricts q to the daily rollups of the site or of one article in [from, to)
+func dailyUniques(q *gorm.DB, articleID *uuid.UUID, from, to time.Time) *gorm.DB {
+	q = q.Where("granularity = ? AND bucket >= ? AND bucket < ?", domain.RollupDay, from, to)
+	if articleID != nil {
+		return q.Where("dimension = ? AND dimension_id = ?", domain.RollupArticle, *articleID)
+	}
+	return q.Where("dimension = ?", domain.RollupSite)
+}
+
+// CountUniqueVisitors can sum the daily counts: the daily salt makes the same
+// reader's IDs on different days distinct anyway
+func (r *viewTrackingRepository) CountUniqueVisitors(articleID *uuid.UUID, from, to time.Time) (int64, error) {
+	var count int64
+	err := dailyUniques(r.db.Model(&domain.AnalyticsRollup{}), articleID, from, to).
+		Select("COALESCE(SUM(uniques), 0)").
+		Scan(&count).Error
+	return count, err
+}
+
+func (r *viewTrackingRepository) DailyUniqueVisitors(articleID *uuid.UUID, from, to time.Time) ([]domain.DailyUniques, error) {
+	var series []domain.DailyUniques
+	err := dailyUniques(r.db.Model(&domain.AnalyticsRollup{}), articleID, from, to).
+		Select("CAST(bucket AS DATE) AS date, uniques AS visitors").
+		Order("bucket").
+		Scan(&series).Error
+	return series, err
+}
+
+// DeleteBefore removes raw views older than before, in chunks to keep locks short
+func (r *viewTrackingRepository) DeleteBefore(before time.Time, chunk int) (int64, error) {
+	var total int64
+	for {
+		result := r.db.Exec(`
+			DELETE FROM article_views
+			WHERE id IN (SELECT id FROM article_views WHERE viewed_at < ? LIMIT ?)`, before, chunk)
+		if result.Error != nil {
+			return total, result.Error
+		}
+		total += result.RowsAffected
+		if result.RowsAffected < int64(chunk) {
+			return total, nil
+		}
+	}
+}
+
+// sourceColumns maps breakdown groups to article_views columns
+var sourceColumns = map[domain.ViewSourceGroup]string{
+	domain.SourceByChannel:  "channel",
+	domain.SourceByReferrer: "referrer_host",
+	domain.SourceByCampaign: "utm_campaign",
+	domain.SourceByDevice:   "device_class",
+	domain.SourceByBrowser:  "browser",
+	domain.SourceByOS:       "os",
+}
+
+func (r *viewTrackingRepository) SourceBreakdown(group domain.ViewSourceGroup, q domain.ViewSourceQuery) ([]domain.ViewSourceCount, error) {
+	column, ok := sourceColumns[group]
+	if !ok {
+		return nil, fmt.Errorf("unknown view source group %q", group)
+	}
+	keys := column + " AS key"
+	groupBy := column
+	if group == domain.SourceByCampaign {
+		keys += ", utm_source AS source, utm_medium AS medium"
+		groupBy += ", utm_source, utm_medium"
+	}
+
+	query := r.db.Model(&domain.ArticleView{}).
+		Select(keys+", COUNT(*) AS views, COUNT(DISTINCT NULLIF(visitor_id, '')) AS uniques, AVG(session_duration) AS avg_session").
+		Where("viewed_at >= ? AND viewed_at < ? AND session_duration >= 30 AND classification = ?", q.From, q.To, domain.ViewHuman)
+	if q.ArticleID != nil {
+		query = query.Where("article_id = ?", *q.ArticleID)
+	}
+	// Untagged and referrer-less views have nothing to show in these lists
+	if group == domain.SourceByCampaign || group == domain.SourceByReferrer {
+		query = query.Where(column + " <> ''")
+	}
+
+	rows := []domain.ViewSourceCount{}
+	err := query.Group(groupBy).Order("views DESC, key").Limit(q.Limit).Scan(&rows).Error
+	return rows, err
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"backend/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type visitorSaltRepository struct {
+	db *gorm.DB
+}
+
+func NewVisitorSaltRepository(db *gorm.DB) domain.VisitorSaltRepository {
+	return &visitorSaltRepository{db: db}
+}
+
+// GetOrCreate lets every process agree on one salt per day: the first insert wins
+// and the others read it back
+func (r *visitorSaltRepository) GetOrCreate(day time.Time, candidate []byte) ([]byte, error) {
+	err := r.db.Clauses(clause.OnConflict{DoNothing: true}).
+		Create(&domain.VisitorSalt{Day: day, Salt: candidate, CreatedAt: time.Now().UTC()}).Error
+	if err != nil {
+		return nil, err
+	}
+	var salt domain.VisitorSalt
+	if err := r.db.First(&salt, "day = ?", day).Error; err != nil {
+		return nil, err
+	}
+	return salt.Salt, nil
+}
+
+func (r *visitorSaltRepository) DeleteBefore(day time.Time) error {
+	return r.db.Where("day < ?", day).Delete(&domain.VisitorSalt{}).Error
+}
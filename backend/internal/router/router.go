@@ -27,6 +27,13 @@ type Router struct {
 	viewTrackingHandler *handler.ViewTrackingHandler
 	settingHandler      *handler.SettingHandler
 	auditHandler        *handler.AuditHandler
+	searchHandler       *handler.SearchHandler
+	savedSearchHandler  *handler.SavedSearchHandler
+	notificationHandler *handler.NotificationHandler
+	reportHandler       *handler.ReportHandler
+	userBlockHandler    *handler.UserBlockHandler
+	ratingIntegrity     *handler.RatingIntegrityHandler
+	analyticsHandler    *handler.AnalyticsHandler
 	userRepo            domain.UserRepository
 	wsHub               *ws.Hub
 	cache               *middleware.ResponseCache
@@ -47,6 +54,13 @@ func NewRouter(
 	viewTrackingHandler *handler.ViewTrackingHandler, // Added
 	settingHandler *handler.SettingHandler, // Added
 	auditHandler *handler.AuditHandler, // Added
+	searchHandler *handler.SearchHandler,
+	savedSearchHandler *handler.SavedSearchHandler,
+	notificationHandler *handler.NotificationHandler,
+	reportHandler *handler.ReportHandler,
+	userBlockHandler *handler.UserBlockHandler,
+	ratingIntegrity *handler.RatingIntegrityHandler,
+	analyticsHandler *handler.AnalyticsHandler,
 	wsHub *ws.Hub,
 	cache *middleware.ResponseCache,
 ) *Router {
@@ -65,6 +79,13 @@ func NewRouter(
 		viewTrackingHandler: viewTrackingHandler,
 		settingHandler:      settingHandler,
 		auditHandler:        auditHandler,
+		searchHandler:       searchHandler,
+		savedSearchHandler:  savedSearchHandler,
+		notificationHandler: notificationHandler,
+		reportHandler:       reportHandler,
+		userBlockHandler:    userBlockHandler,
+		ratingIntegrity:     ratingIntegrity,
+		analyticsHandler:    analyticsHandler,
 		userRepo:            userHandler.GetService().GetRepo(),
 		wsHub:               wsHub,
 		cache:               cache,
@@ -143,6 +164,8 @@ func (r *Router) Setup(engine *gin.Engine) {
 			// View tracking - public endpoint with rate limiting (10 req/min)
 			articles.POST("/:id/track-view", middleware.RateLimitMiddleware(rate.Limit(0.16), 3), r.viewTrackingHandler.TrackView)
 			articles.GET("/:id/views", r.viewTrackingHandler.GetViewCount)
+			// Read-depth pings, sent every few seconds while the article is open
+			articles.POST("/:id/engagement", middleware.RateLimitMiddleware(rate.Limit(0.5), 5), r.viewTrackingHandler.TrackEngagement)
 			// Random articles - No cache to ensure randomness, but rate limited to prevent abuse
 			// 1 request/sec, burst 5
 			articles.GET("/random", middleware.RateLimitMiddleware(rate.Limit(1.0), 5), r.articleHandler.GetRandom)
@@ -150,9 +173,19 @@ func (r *Router) Setup(engine *gin.Engine) {
 			articles.GET("/:id/relations", r.articleHandler.GetArticleRelations)
 
 			// Public Comment Routes (Read-only)
-			articles.GET("/:id/comments", r.commentHandler.GetComments)
-			articles.GET("/:id/comments/:commentId/replies", r.commentHandler.GetReplies)
+			// Optional auth so authors also see their own comments awaiting moderation
+			articles.GET("/:id/comments", middleware.OptionalAuthMiddleware(r.userRepo), r.commentHandler.GetComments)
+			articles.GET("/:id/comments/:commentId/replies", middleware.OptionalAuthMiddleware(r.userRepo), r.commentHandler.GetReplies)
 			articles.GET("/:id/rating", middleware.OptionalAuthMiddleware(r.userRepo), r.ratingHandler.GetRating)
+			articles.GET("/:id/rating/breakdown", middleware.CacheMiddleware(r.cache, time.Minute), r.ratingHandler.GetRatingBreakdown)
+			articles.GET("/:id/reviews", middleware.OptionalAuthMiddleware(r.userRepo), r.ratingHandler.GetReviews)
+		}
+
+		// Guest comments (off unless guest_comments_enabled), gated by a proof-of-work challenge
+		guestComments := v1.Group("/comments/guest")
+		{
+			guestComments.GET("/challenge", middleware.RateLimitMiddleware(rate.Limit(0.5), 5), r.commentHandler.GetGuestChallenge)
+			guestComments.POST("", middleware.RateLimitMiddleware(rate.Limit(0.1), 3), r.commentHandler.CreateGuestComment)
 		}
 
 		// Stats routes
@@ -205,6 +238,27 @@ func (r *Router) Setup(engine *gin.Engine) {
 			// Stricter rate limiting for delete/restore operations (10/min)
 			protected.DELETE("/comments/:id", middleware.RateLimitMiddleware(rate.Limit(0.16), 3), r.commentHandler.DeleteComment)
 			protected.PUT("/comments/:id/restore", middleware.RateLimitMiddleware(rate.Limit(0.16), 3), r.commentHandler.RestoreComment)
+			protected.POST("/comments/:id/reactions", middleware.RateLimitMiddleware(rate.Limit(2), 10), r.commentHandler.ToggleReaction)
+			// Reader reports (one per user and item; stricter limit to curb abuse)
+			protected.POST("/comments/:id/report", middleware.RateLimitMiddleware(rate.Limit(0.1), 3), r.reportHandler.ReportComment)
+			protected.GET("/comments/mention-suggestions", middleware.RateLimitMiddleware(rate.Limit(2), 10), r.notificationHandler.SuggestMentions)
+
+			// Comment notifications (mentions & replies) and opt-outs
+			notifications := protected.Group("/notifications")
+			{
+				notifications.GET("", r.notificationHandler.ListNotifications)
+				notifications.POST("/read", r.notificationHandler.MarkNotificationsRead)
+				notifications.GET("/preferences", r.notificationHandler.GetPreferences)
+				notifications.PUT("/preferences", r.notificationHandler.UpdatePreferences)
+				notifications.POST("/mutes/:articleId", r.notificationHandler.MuteArticle)
+				notifications.DELETE("/mutes/:articleId", r.notificationHandler.UnmuteArticle)
+			}
+
+			// Author dashboard, always scoped to the signed-in user
+			protected.GET("/me/analytics", middleware.RequireRoles("ADMIN", "EDITOR", "AUTHOR"), r.analyticsHandler.GetMyAnalytics)
+
+			// Read-depth report of an article (its author, editors and admins)
+			protected.GET("/articles/:id/engagement", middleware.RequireRoles("ADMIN", "EDITOR", "AUTHOR"), r.analyticsHandler.GetArticleEngagement)
 
 			// Admin Stats
 			protected.GET("/admin/super-dashboard", middleware.CacheMiddleware(r.cache, time.Minute), r.dashboardHandler.GetSuperDashboard)
@@ -238,10 +292,45 @@ func (r *Router) Setup(engine *gin.Engine) {
 				articles.POST("/:id/redirects", r.articleHandler.AddRedirect)
 				articles.DELETE("/:id/redirects/:redirectId", r.articleHandler.DeleteRedirect)
 				articles.POST("/:id/rate", r.ratingHandler.RateArticle)
+				articles.POST("/:id/report", middleware.RateLimitMiddleware(rate.Limit(0.1), 3), r.reportHandler.ReportArticle)
+			}
+
+			// Helpfulness votes on written reviews
+			reviews := protected.Group("/reviews")
+			{
+				reviews.POST("/:id/vote", middleware.RateLimitMiddleware(rate.Limit(1.0), 10), r.ratingHandler.VoteReview)
+				reviews.DELETE("/:id/vote", r.ratingHandler.RemoveReviewVote)
 			}
 
 			protected.GET("/roles", r.userHandler.GetRoles)
 
+			// Muted and blocked users (per user)
+			blocks := protected.Group("/blocks")
+			{
+				blocks.GET("", r.userBlockHandler.ListBlocks)
+				blocks.POST("", middleware.RateLimitMiddleware(rate.Limit(0.5), 10), r.userBlockHandler.AddBlock)
+				blocks.DELETE("/:userId", r.userBlockHandler.RemoveBlock)
+			}
+
+			// Block counts for abuse investigations (Admin only)
+			blockAdmin := protected.Group("/admin/user-blocks")
+			blockAdmin.Use(middleware.AdminMiddleware())
+			{
+				blockAdmin.GET("", r.userBlockHandler.GetBlockStats)
+				blockAdmin.GET("/:userId", r.userBlockHandler.GetReceivedBlocks)
+			}
+
+			// Saved searches with new-match alerts (per user)
+			savedSearches := protected.Group("/saved-searches")
+			{
+				savedSearches.GET("", r.savedSearchHandler.ListSavedSearches)
+				savedSearches.POST("", middleware.RateLimitMiddleware(rate.Limit(0.33), 5), r.savedSearchHandler.CreateSavedSearch)
+				savedSearches.GET("/:id", r.savedSearchHandler.GetSavedSearch)
+				savedSearches.PUT("/:id", r.savedSearchHandler.UpdateSavedSearch)
+				savedSearches.DELETE("/:id", r.savedSearchHandler.DeleteSavedSearch)
+				savedSearches.GET("/:id/matches", r.savedSearchHandler.GetSavedSearchMatches)
+			}
+
 			// SEO Management
 			seo := protected.Group("/seo")
 			{
@@ -268,6 +357,73 @@ func (r *Router) Setup(engine *gin.Engine) {
 				logs.GET("/system", r.auditHandler.GetSystemLogs)
 				logs.GET("/system/:id", r.auditHandler.GetSystemLog)
 			}
+
+			// Comment Moderation (Admin & Editor)
+			commentAdmin := protected.Group("/admin/comments")
+			commentAdmin.Use(middleware.RequireRoles("ADMIN", "EDITOR"))
+			{
+				commentAdmin.GET("", r.commentHandler.GetModerationQueue)
+				commentAdmin.POST("/moderate", r.commentHandler.ModerateComments)
+				commentAdmin.GET("/:id/revisions", r.commentHandler.GetCommentRevisions)
+			}
+
+			// Review Moderation (Admin & Editor)
+			reviewAdmin := protected.Group("/admin/reviews")
+			reviewAdmin.Use(middleware.RequireRoles("ADMIN", "EDITOR"))
+			{
+				reviewAdmin.GET("", r.ratingHandler.GetReviewQueue)
+				reviewAdmin.POST("/moderate", r.ratingHandler.ModerateReviews)
+			}
+
+			// Rating integrity: reconciliation, anomalies and voiding (Admin & Editor)
+			ratingAdmin := protected.Group("/admin/ratings")
+			ratingAdmin.Use(middleware.RequireRoles("ADMIN", "EDITOR"))
+			{
+				ratingAdmin.GET("/integrity", r.ratingIntegrity.GetIntegrityReport)
+				ratingAdmin.POST("/integrity", r.ratingIntegrity.RunIntegrityCheck)
+				ratingAdmin.GET("/anomalies", r.ratingIntegrity.GetAnomalies)
+				ratingAdmin.POST("/:id/void", r.ratingIntegrity.VoidRating)
+			}
+
+			// Report triage (Admin & Editor)
+			reportAdmin := protected.Group("/admin/reports")
+			reportAdmin.Use(middleware.RequireRoles("ADMIN", "EDITOR"))
+			{
+				reportAdmin.GET("", r.reportHandler.GetReports)
+				reportAdmin.POST("/resolve", r.reportHandler.ResolveReports)
+			}
+
+			// Search Index Management (Admin only)
+			searchAdmin := protected.Group("/admin/search")
+			searchAdmin.Use(middleware.AdminMiddleware())
+			{
+				searchAdmin.GET("/reindex", r.searchHandler.GetReindexStatus)
+				searchAdmin.POST("/reindex", r.searchHandler.StartReindex)
+			}
+
+			// View ingestion, bot filtering and traffic sources (Admin only)
+			viewAdmin := protected.Group("/admin/views")
+			viewAdmin.Use(middleware.AdminMiddleware())
+			{
+				viewAdmin.GET("/ingest", r.viewTrackingHandler.GetIngestStats)
+				viewAdmin.GET("/traffic", r.viewTrackingHandler.GetTrafficStats)
+				viewAdmin.GET("/uniques", r.viewTrackingHandler.GetUniqueVisitors)
+				viewAdmin.GET("/channels", r.viewTrackingHandler.GetChannels)
+				viewAdmin.GET("/campaigns", r.viewTrackingHandler.GetCampaigns)
+				viewAdmin.GET("/devices", r.viewTrackingHandler.GetDevices)
+				viewAdmin.GET("/bot-rules", r.viewTrackingHandler.GetBotRules)
+				viewAdmin.POST("/bot-rules/reload", r.viewTrackingHandler.ReloadBotRules)
+			}
+
+			// Hourly / daily analytics rollups (Admin only)
+			analyticsAdmin := protected.Group("/admin/analytics")
+			analyticsAdmin.Use(middleware.AdminMiddleware())
+			{
+				analyticsAdmin.GET("/rollups", r.analyticsHandler.GetSeries)
+				analyticsAdmin.POST("/rollups/run", r.analyticsHandler.RunRollup)
+				analyticsAdmin.GET("/top", r.analyticsHandler.GetTop)
+				analyticsAdmin.GET("/authors/:id", r.analyticsHandler.GetAuthorAnalytics)
+			}
 		}
 	}
 }
@@ -0,0 +1,72 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Analyze lowercases, strips Vietnamese diacritics and splits text into terms,
+// so "Học máy" and "hoc may" produce the same tokens.
+func Analyze(text string) []string {
+	if text == "" {
+		return nil
+	}
+	folded := foldDiacritics(strings.ToLower(text))
+
+	terms := strings.FieldsFunc(folded, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+
+	result := terms[:0]
+	for _, t := range terms {
+		// Single characters add noise without helping relevance
+		if len([]rune(t)) < 2 {
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+func foldDiacritics(s string) string {
+	// đ is a distinct letter, not a combining mark, so NFD won't split it
+	s = strings.NewReplacer("đ", "d", "Đ", "d").Replace(s)
+
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	out, _, err := transform.String(t, s)
+	if err != nil {
+		return s
+	}
+	return out
+}
+
+// ParseSynonyms converts the "search_synonyms" system setting (a JSON array of
+// string arrays) into synonym groups, ignoring malformed entries.
+func ParseSynonyms(value interface{}) [][]string {
+	rawGroups, ok := value.([]interface{})
+	if !ok {
+		return nil
+	}
+
+	var groups [][]string
+	for _, rg := range rawGroups {
+		items, ok := rg.([]interface{})
+		if !ok {
+			continue
+		}
+		var group []string
+		for _, item := range items {
+			if s, ok := item.(string); ok && strings.TrimSpace(s) != "" {
+				group = append(group, s)
+			}
+		}
+		if len(group) > 1 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
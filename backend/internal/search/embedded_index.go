@@ -0,0 +1,534 @@
+package search
+
+import (
+	"backend/internal/domain"
+	"encoding/gob"
+	"errors"
+	"log"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Indexed fields. The order is part of the on-disk format.
+const (
+	fieldTitle = iota
+	fieldSummary
+	fieldContent
+	fieldTags
+	fieldCategory
+	numFields
+)
+
+const (
+	indexFileName = "articles.idx"
+	flushInterval = 30 * time.Second
+
+	// Expanded terms (synonyms / prefixes) score lower than exact matches
+	synonymWeight = 0.8
+	prefixWeight  = 0.5
+	maxExpansions = 20
+)
+
+// Options tunes relevance for the embedded index
+type Options struct {
+	// BM25 parameters
+	K1 float64
+	B  float64
+	// Per-field score multipliers (title, summary, content, tags, category)
+	FieldBoosts [numFields]float64
+	// Synonym groups, e.g. [["ai", "tri tue nhan tao"], ["ml", "hoc may"]]
+	Synonyms [][]string
+}
+
+// DefaultOptions mirrors the weighting of the PostgreSQL search_vector (title A, summary B, content C)
+func DefaultOptions() Options {
+	return Options{
+		K1:          1.2,
+		B:           0.75,
+		FieldBoosts: [numFields]float64{3.0, 1.5, 1.0, 2.0, 1.5},
+	}
+}
+
+type document struct {
+	Status     string
+	CategoryID string
+	IsFeatured bool
+	CreatedAt  time.Time
+	// PublishedAt was added after the first on-disk format; older snapshots decode it as nil
+	PublishedAt *time.Time
+	Lengths     [numFields]int
+}
+
+// snapshot is the gob-encoded on-disk representation
+type snapshot struct {
+	Docs     map[string]*document
+	Postings map[string]map[string][numFields]int // term -> doc -> per-field term frequency
+	TotalLen [numFields]int64
+}
+
+type embeddedIndex struct {
+	mu       sync.RWMutex
+	path     string
+	opts     Options
+	loader   domain.ArticleRepository
+	data     snapshot
+	synonyms map[string][]string
+	dirty    bool
+	// build is the rebuild in progress; live writes are mirrored into it
+	build *embeddedBuild
+
+	stop      chan struct{}
+	closeOnce sync.Once
+}
+
+// NewEmbeddedIndex opens (or creates) an on-disk BM25 inverted index in dir.
+// loader hydrates search hits into full articles.
+func NewEmbeddedIndex(dir string, loader domain.ArticleRepository, opts Options) (domain.SearchIndex, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+
+	idx := &embeddedIndex{
+		path:   filepath.Join(dir, indexFileName),
+		opts:   opts,
+		loader: loader,
+		data:   newSnapshot(),
+		stop:   make(chan struct{}),
+	}
+	idx.buildSynonyms()
+
+	if err := idx.load(); err != nil {
+		// A corrupt index is not fatal; an admin can rebuild it with "reindex all"
+		log.Printf("Search index: failed to load %s, starting empty: %v", idx.path, err)
+		idx.data = newSnapshot()
+	}
+
+	go idx.flushLoop()
+	return idx, nil
+}
+
+func (idx *embeddedIndex) Name() string {
+	return "embedded"
+}
+
+func newSnapshot() snapshot {
+	return snapshot{
+		Docs:     make(map[string]*document),
+		Postings: make(map[string]map[string][numFields]int),
+	}
+}
+
+func (idx *embeddedIndex) buildSynonyms() {
+	idx.synonyms = make(map[string][]string)
+	for _, group := range idx.opts.Synonyms {
+		var terms []string
+		for _, phrase := range group {
+			terms = append(terms, Analyze(phrase)...)
+		}
+		for _, t := range terms {
+			for _, other := range terms {
+				if other != t {
+					idx.synonyms[t] = append(idx.synonyms[t], other)
+				}
+			}
+		}
+	}
+}
+
+func (idx *embeddedIndex) IndexArticle(article *domain.Article) error {
+	if article == nil || article.ID == uuid.Nil {
+		return errors.New("article is required")
+	}
+	key := article.ID.String()
+	doc, freqs := analyzeArticle(article)
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.data.put(key, doc, freqs)
+	if idx.build != nil {
+		idx.build.data.put(key, doc, freqs)
+		idx.build.touched[key] = true
+	}
+	idx.dirty = true
+	return nil
+}
+
+func (idx *embeddedIndex) RemoveArticle(id uuid.UUID) error {
+	key := id.String()
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.data.remove(key)
+	if idx.build != nil {
+		idx.build.data.remove(key)
+		idx.build.touched[key] = true
+	}
+	idx.dirty = true
+	return nil
+}
+
+// analyzeArticle tokenizes the indexed fields of an article
+func analyzeArticle(article *domain.Article) (*document, map[string][numFields]int) {
+	var fields [numFields][]string
+	fields[fieldTitle] = Analyze(article.Title)
+	fields[fieldSummary] = Analyze(article.Summary)
+	fields[fieldContent] = Analyze(article.Content)
+	for _, tag := range article.Tags {
+		fields[fieldTags] = append(fields[fieldTags], Analyze(tag.Name)...)
+	}
+	fields[fieldCategory] = Analyze(article.Category.Name)
+
+	doc := &document{
+		Status:      string(article.Status),
+		CategoryID:  article.CategoryID.String(),
+		IsFeatured:  article.IsFeatured,
+		CreatedAt:   article.CreatedAt,
+		PublishedAt: article.PublishedAt,
+	}
+
+	freqs := make(map[string][numFields]int)
+	for f, terms := range fields {
+		doc.Lengths[f] = len(terms)
+		for _, t := range terms {
+			tf := freqs[t]
+			tf[f]++
+			freqs[t] = tf
+		}
+	}
+	return doc, freqs
+}
+
+// put adds or replaces a document
+func (s *snapshot) put(key string, doc *document, freqs map[string][numFields]int) {
+	s.remove(key)
+	s.Docs[key] = doc
+	for f := 0; f < numFields; f++ {
+		s.TotalLen[f] += int64(doc.Lengths[f])
+	}
+	for term, tf := range freqs {
+		postings, ok := s.Postings[term]
+		if !ok {
+			postings = make(map[string][numFields]int)
+			s.Postings[term] = postings
+		}
+		postings[key] = tf
+	}
+}
+
+func (s *snapshot) remove(key string) {
+	doc, ok := s.Docs[key]
+	if !ok {
+		return
+	}
+	for f := 0; f < numFields; f++ {
+		s.TotalLen[f] -= int64(doc.Lengths[f])
+	}
+	// Postings are not keyed by doc, so scan; removals are rare compared to searches
+	for term, postings := range s.Postings {
+		if _, ok := postings[key]; ok {
+			delete(postings, key)
+			if len(postings) == 0 {
+				delete(s.Postings, term)
+			}
+		}
+	}
+	delete(s.Docs, key)
+}
+
+// embeddedBuild fills a fresh snapshot next to the live one
+type embeddedBuild struct {
+	idx  *embeddedIndex
+	data snapshot
+	// touched holds articles written to the live index during the build; those
+	// writes are newer than the copies the rebuild reads from the database
+	touched map[string]bool
+}
+
+var errRebuildSuperseded = errors.New("search index rebuild was discarded or superseded")
+
+func (idx *embeddedIndex) BeginRebuild() (domain.SearchIndexBuild, error) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	idx.build = &embeddedBuild{idx: idx, data: newSnapshot(), touched: make(map[string]bool)}
+	return idx.build, nil
+}
+
+func (b *embeddedBuild) IndexArticle(article *domain.Article) error {
+	if article == nil || article.ID == uuid.Nil {
+		return errors.New("article is required")
+	}
+	key := article.ID.String()
+	doc, freqs := analyzeArticle(article)
+
+	b.idx.mu.Lock()
+	defer b.idx.mu.Unlock()
+
+	if b.idx.build != b {
+		return errRebuildSuperseded
+	}
+	if !b.touched[key] {
+		b.data.put(key, doc, freqs)
+	}
+	return nil
+}
+
+func (b *embeddedBuild) Commit() error {
+	b.idx.mu.Lock()
+	defer b.idx.mu.Unlock()
+
+	if b.idx.build != b {
+		return errRebuildSuperseded
+	}
+	b.idx.data = b.data
+	b.idx.build = nil
+	b.idx.dirty = true
+	return nil
+}
+
+func (b *embeddedBuild) Discard() {
+	b.idx.mu.Lock()
+	defer b.idx.mu.Unlock()
+
+	if b.idx.build == b {
+		b.idx.build = nil
+	}
+}
+
+type scoredDoc struct {
+	key   string
+	score float64
+	doc   *document
+}
+
+func (idx *embeddedIndex) Search(query string, offset, limit int, filters map[string]interface{}) ([]domain.Article, int64, error) {
+	// Queries of only punctuation or single characters match nothing
+	terms := Analyze(query)
+	if len(terms) == 0 {
+		return []domain.Article{}, 0, nil
+	}
+
+	status := string(domain.StatusPublished)
+	if s, ok := filters["status"].(string); ok && s != "" {
+		status = s
+	}
+	categoryFilter, _ := filters["category_id"].(string)
+	publishedAfter, _ := filters["published_after"].(time.Time)
+
+	idx.mu.RLock()
+	scores := make(map[string]float64)
+	matched := make(map[string]int)
+	for _, term := range terms {
+		seen := make(map[string]bool)
+		for _, exp := range idx.expandLocked(term) {
+			for key, tf := range idx.data.Postings[exp.term] {
+				scores[key] += exp.weight * idx.bm25Locked(exp.term, tf, idx.data.Docs[key])
+				if !seen[key] {
+					seen[key] = true
+					matched[key]++
+				}
+			}
+		}
+	}
+
+	hits := make([]scoredDoc, 0, len(scores))
+	for key, score := range scores {
+		doc := idx.data.Docs[key]
+		if doc == nil || doc.Status != status {
+			continue
+		}
+		if categoryFilter != "" && doc.CategoryID != categoryFilter {
+			continue
+		}
+		if !publishedAfter.IsZero() && (doc.PublishedAt == nil || !doc.PublishedAt.After(publishedAfter)) {
+			continue
+		}
+		// Coordination factor: favour documents that match every query term
+		score *= float64(matched[key]) / float64(len(terms))
+		if doc.IsFeatured {
+			score += 0.5
+		}
+		hits = append(hits, scoredDoc{key: key, score: score, doc: doc})
+	}
+	idx.mu.RUnlock()
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].score != hits[j].score {
+			return hits[i].score > hits[j].score
+		}
+		if !hits[i].doc.CreatedAt.Equal(hits[j].doc.CreatedAt) {
+			return hits[i].doc.CreatedAt.After(hits[j].doc.CreatedAt)
+		}
+		return hits[i].key < hits[j].key
+	})
+
+	total := int64(len(hits))
+	if offset >= len(hits) {
+		return []domain.Article{}, total, nil
+	}
+	end := offset + limit
+	if end > len(hits) {
+		end = len(hits)
+	}
+
+	ids := make([]uuid.UUID, 0, end-offset)
+	for _, h := range hits[offset:end] {
+		if id, err := uuid.Parse(h.key); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	articles, err := idx.loader.GetByIDs(ids)
+	if err != nil {
+		return nil, 0, err
+	}
+	return articles, total, nil
+}
+
+type expansion struct {
+	term   string
+	weight float64
+}
+
+// expandLocked returns the term itself plus synonyms and vocabulary prefix matches with
+// their weights, in a fixed order so repeated queries score identically
+func (idx *embeddedIndex) expandLocked(term string) []expansion {
+	expanded := []expansion{{term, 1.0}}
+	seen := map[string]bool{term: true}
+	for _, syn := range idx.synonyms[term] {
+		if !seen[syn] {
+			seen[syn] = true
+			expanded = append(expanded, expansion{syn, synonymWeight})
+		}
+	}
+
+	if len([]rune(term)) >= 3 {
+		var candidates []string
+		for vocab := range idx.data.Postings {
+			if !seen[vocab] && strings.HasPrefix(vocab, term) {
+				candidates = append(candidates, vocab)
+			}
+		}
+		// Keep the most common completions, ties broken lexically
+		sort.Slice(candidates, func(i, j int) bool {
+			di, dj := len(idx.data.Postings[candidates[i]]), len(idx.data.Postings[candidates[j]])
+			if di != dj {
+				return di > dj
+			}
+			return candidates[i] < candidates[j]
+		})
+		if len(candidates) > maxExpansions {
+			candidates = candidates[:maxExpansions]
+		}
+		for _, c := range candidates {
+			expanded = append(expanded, expansion{c, prefixWeight})
+		}
+	}
+	return expanded
+}
+
+// bm25Locked computes a BM25F-style score: each field is length-normalised separately and boosted
+func (idx *embeddedIndex) bm25Locked(term string, tf [numFields]int, doc *document) float64 {
+	if doc == nil {
+		return 0
+	}
+	n := float64(len(idx.data.Docs))
+	df := float64(len(idx.data.Postings[term]))
+	idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+	var score float64
+	for f := 0; f < numFields; f++ {
+		if tf[f] == 0 {
+			continue
+		}
+		avgLen := 1.0
+		if n > 0 && idx.data.TotalLen[f] > 0 {
+			avgLen = float64(idx.data.TotalLen[f]) / n
+		}
+		freq := float64(tf[f])
+		norm := freq * (idx.opts.K1 + 1) / (freq + idx.opts.K1*(1-idx.opts.B+idx.opts.B*float64(doc.Lengths[f])/avgLen))
+		score += idx.opts.FieldBoosts[f] * idf * norm
+	}
+	return score
+}
+
+func (idx *embeddedIndex) load() error {
+	f, err := os.Open(idx.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	defer f.Close()
+
+	var data snapshot
+	if err := gob.NewDecoder(f).Decode(&data); err != nil {
+		return err
+	}
+	if data.Docs == nil || data.Postings == nil {
+		return errors.New("index file is incomplete")
+	}
+	idx.data = data
+	return nil
+}
+
+func (idx *embeddedIndex) Flush() error {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if !idx.dirty {
+		return nil
+	}
+
+	// Write to a temp file and rename so a crash never leaves a half-written index
+	tmp := idx.path + ".tmp"
+	f, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	if err := gob.NewEncoder(f).Encode(&idx.data); err != nil {
+		f.Close()
+		os.Remove(tmp)
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, idx.path); err != nil {
+		return err
+	}
+	idx.dirty = false
+	return nil
+}
+
+// Close stops the periodic flush and writes any pending changes
+func (idx *embeddedIndex) Close() error {
+	idx.closeOnce.Do(func() { close(idx.stop) })
+	return idx.Flush()
+}
+
+func (idx *embeddedIndex) flushLoop() {
+	ticker := time.NewTicker(flushInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-idx.stop:
+			return
+		case <-ticker.C:
+			if err := idx.Flush(); err != nil {
+				log.Printf("Search index: flush failed: %v", err)
+			}
+		}
+	}
+}
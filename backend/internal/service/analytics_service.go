@@ -0,0 +1,281 @@
+package service
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Range limits keep a single rollup query to a few thousand buckets
+const (
+	maxHourlyRange = 31 * 24 * time.Hour
+	maxDailyRange  = 366 * 24 * time.Hour
+)
+
+type analyticsService struct {
+	repo           domain.AnalyticsRollupRepository
+	articleRepo    domain.ArticleRepository
+	engagementRepo domain.EngagementRepository
+	userRepo       domain.UserRepository
+}
+
+func NewAnalyticsService(repo domain.AnalyticsRollupRepository, articleRepo domain.ArticleRepository, engagementRepo domain.EngagementRepository, userRepo domain.UserRepository) domain.AnalyticsService {
+	return &analyticsService{repo: repo, articleRepo: articleRepo, engagementRepo: engagementRepo, userRepo: userRepo}
+}
+
+func (s *analyticsService) GetSeries(q domain.RollupQuery) ([]domain.AnalyticsRollup, error) {
+	if err := validateRollupQuery(q); err != nil {
+		return nil, err
+	}
+	rows, err := s.repo.Series(q)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	for i := range rows {
+		rows[i].Finish()
+	}
+	return rows, nil
+}
+
+func (s *analyticsService) GetTop(q domain.RollupQuery, limit int) ([]domain.RollupTotal, error) {
+	if err := validateRollupQuery(q); err != nil {
+		return nil, err
+	}
+	if q.Dimension == domain.RollupSite {
+		return nil, apperrors.NewBadRequest("Xếp hạng chỉ áp dụng cho bài viết, chuyên mục hoặc tác giả")
+	}
+	if limit < 1 || limit > 100 {
+		limit = 10
+	}
+	rows, err := s.repo.Top(q, limit)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	for i := range rows {
+		rows[i].Finish()
+	}
+	return rows, nil
+}
+
+// GetArticleEngagement reports completion, active time and where readers stop
+// scrolling. Authors may only see their own articles; editors and admins see all.
+func (s *analyticsService) GetArticleEngagement(articleID, viewerID uuid.UUID, roles []string, from, to time.Time) (*domain.ArticleEngagementStats, error) {
+	if !from.Before(to) {
+		return nil, apperrors.NewBadRequest("Khoảng thời gian không hợp lệ")
+	}
+	if to.Sub(from) > maxDailyRange {
+		return nil, apperrors.NewBadRequest(fmt.Sprintf("Khoảng thời gian tối đa là %d ngày", int(maxDailyRange.Hours()/24)))
+	}
+	article, err := s.articleRepo.GetByID(articleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound("Bài viết không tồn tại")
+		}
+		return nil, apperrors.NewInternalError(err)
+	}
+	if !domain.HasAnyRole(roles, "ADMIN", "EDITOR") && article.AuthorID != viewerID {
+		return nil, apperrors.NewForbidden("Bạn chỉ có thể xem số liệu bài viết của mình")
+	}
+
+	summary, err := s.engagementRepo.Summary(articleID, from, to)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	histogram, err := s.engagementRepo.ScrollHistogram(articleID, from, to)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	if summary.Sessions > 0 {
+		summary.CompletionRate = float64(summary.Completed) / float64(summary.Sessions)
+	}
+
+	stats := &domain.ArticleEngagementStats{
+		ArticleID:         articleID,
+		From:              from,
+		To:                to,
+		EngagementSummary: *summary,
+	}
+	// Share of sessions scrolling past each tenth of the article
+	for depth := 0; depth <= 100; depth += 10 {
+		reach := domain.ScrollReach{Depth: depth, Sessions: sessionsBetween(histogram, float64(depth), 101)}
+		if summary.Sessions > 0 {
+			reach.Rate = float64(reach.Sessions) / float64(summary.Sessions)
+		}
+		stats.ScrollReach = append(stats.ScrollReach, reach)
+	}
+	// A session drops off in the section holding its deepest scroll point; sessions
+	// scrolled to 100% finished the article
+	for _, section := range domain.ParseOutline(article.Content) {
+		d := domain.SectionDropOff{
+			OutlineSection: section,
+			Reached:        sessionsBetween(histogram, section.StartPct, 101),
+			Dropped:        sessionsBetween(histogram, section.StartPct, section.EndPct),
+		}
+		if d.Reached > 0 {
+			d.DropOffRate = float64(d.Dropped) / float64(d.Reached)
+		}
+		stats.Sections = append(stats.Sections, d)
+	}
+	return stats, nil
+}
+
+// sessionsBetween counts sessions whose deepest scroll lies in [from, to). The
+// first section starts at 0, so readers who never scrolled drop off there.
+func sessionsBetween(histogram []domain.ScrollCount, from, to float64) int64 {
+	var n int64
+	for _, h := range histogram {
+		if depth := float64(h.Depth); depth >= from && depth < to {
+			n += h.Sessions
+		}
+	}
+	return n
+}
+
+// GetAuthorAnalytics reads the author's rollups, ranks their articles and compares
+// the totals with the median author over the same range. Totals, rankings and medians
+// always sum daily rollups, since hourly uniques can't be added up, so they cover the
+// whole UTC days the range touches.
+func (s *analyticsService) GetAuthorAnalytics(authorID uuid.UUID, granularity domain.RollupGranularity, from, to time.Time, topLimit int) (*domain.AuthorAnalytics, error) {
+	q := domain.RollupQuery{
+		Granularity: granularity,
+		Dimension:   domain.RollupAuthor,
+		DimensionID: &authorID,
+		From:        from,
+		To:          to,
+	}
+	if err := validateRollupQuery(q); err != nil {
+		return nil, err
+	}
+	author, err := s.userRepo.GetUserByID(authorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound("Tác giả không tồn tại")
+		}
+		return nil, apperrors.NewInternalError(err)
+	}
+
+	rows, err := s.repo.Series(q)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	result := &domain.AuthorAnalytics{
+		AuthorID:    authorID,
+		AuthorName:  author.FullName,
+		Granularity: granularity,
+		From:        from,
+		To:          to,
+		Totals:      domain.AnalyticsRollup{Granularity: domain.RollupDay, Dimension: domain.RollupAuthor, DimensionID: authorID},
+		Series:      fillBuckets(rows, q),
+	}
+
+	daily := q
+	daily.Granularity, daily.From = domain.RollupDay, domain.VisitorDay(from)
+	dailyRows := rows
+	if granularity != domain.RollupDay || !daily.From.Equal(from) {
+		if dailyRows, err = s.repo.Series(daily); err != nil {
+			return nil, apperrors.NewInternalError(err)
+		}
+	}
+	for _, row := range dailyRows {
+		result.Totals.Add(row)
+	}
+	result.Totals.Finish()
+
+	if topLimit < 1 || topLimit > 50 {
+		topLimit = 10
+	}
+	top := daily
+	top.Dimension, top.DimensionID, top.AuthorID = domain.RollupArticle, nil, &authorID
+	if result.TopArticles, err = s.repo.Top(top, topLimit); err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	for i := range result.TopArticles {
+		result.TopArticles[i].Finish()
+	}
+
+	median, err := s.repo.AuthorMedians(domain.RollupDay, daily.From, to)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	t := result.Totals
+	result.Author = domain.AuthorBenchmark{
+		Views:          float64(t.Views),
+		Uniques:        float64(t.Uniques),
+		Comments:       float64(t.Comments),
+		Ratings:        float64(t.Ratings),
+		AvgRating:      t.AvgRating,
+		CompletionRate: t.CompletionRate,
+		AvgActive:      t.AvgActive,
+	}
+	result.SiteMedian = *median
+	result.VsMedian = domain.AuthorBenchmark{
+		Views:          ratio(result.Author.Views, median.Views),
+		Uniques:        ratio(result.Author.Uniques, median.Uniques),
+		Comments:       ratio(result.Author.Comments, median.Comments),
+		Ratings:        ratio(result.Author.Ratings, median.Ratings),
+		AvgRating:      ratio(result.Author.AvgRating, median.AvgRating),
+		CompletionRate: ratio(result.Author.CompletionRate, median.CompletionRate),
+		AvgActive:      ratio(result.Author.AvgActive, median.AvgActive),
+	}
+	return result, nil
+}
+
+// fillBuckets returns one row per bucket of q's range, zero where nothing happened
+func fillBuckets(rows []domain.AnalyticsRollup, q domain.RollupQuery) []domain.AnalyticsRollup {
+	step := time.Hour
+	start := q.From.UTC().Truncate(time.Hour)
+	if q.Granularity == domain.RollupDay {
+		step = 24 * time.Hour
+		start = domain.VisitorDay(q.From)
+	}
+	byBucket := make(map[int64]domain.AnalyticsRollup, len(rows))
+	for _, row := range rows {
+		byBucket[row.Bucket.Unix()] = row
+	}
+
+	var series []domain.AnalyticsRollup
+	for b := start; b.Before(q.To); b = b.Add(step) {
+		row, ok := byBucket[b.Unix()]
+		if !ok {
+			row = domain.AnalyticsRollup{Granularity: q.Granularity, Bucket: b, Dimension: q.Dimension}
+			if q.DimensionID != nil {
+				row.DimensionID = *q.DimensionID
+			}
+		}
+		row.Finish()
+		series = append(series, row)
+	}
+	return series
+}
+
+func ratio(value, median float64) float64 {
+	if median == 0 {
+		return 0
+	}
+	return value / median
+}
+
+func validateRollupQuery(q domain.RollupQuery) error {
+	if !q.Granularity.IsValid() {
+		return apperrors.NewBadRequest("granularity phải là HOUR hoặc DAY")
+	}
+	if !q.Dimension.IsValid() {
+		return apperrors.NewBadRequest("dimension phải là SITE, ARTICLE, CATEGORY hoặc AUTHOR")
+	}
+	if !q.From.Before(q.To) {
+		return apperrors.NewBadRequest("Khoảng thời gian không hợp lệ")
+	}
+	max := maxDailyRange
+	if q.Granularity == domain.RollupHour {
+		max = maxHourlyRange
+	}
+	if q.To.Sub(q.From) > max {
+		return apperrors.NewBadRequest(fmt.Sprintf("Khoảng thời gian tối đa là %d ngày", int(max.Hours()/24)))
+	}
+	return nil
+}
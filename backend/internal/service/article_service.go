@@ -2,6 +2,7 @@ package service
 
 import (
 	"backend/internal/domain"
+	"backend/internal/logger"
 	"backend/internal/utils"
 	"backend/internal/ws"
 	"errors"
@@ -12,6 +13,7 @@ import (
 
 	"github.com/google/uuid"
 	"golang.org/x/sync/singleflight"
+	"gorm.io/gorm"
 )
 
 type articleService struct {
@@ -19,20 +21,42 @@ type articleService struct {
 	mediaRepo      domain.MediaRepository
 	auditServ      domain.AuditService
 	seoService     domain.SeoService
+	searchIndex    domain.SearchIndex
 	imageProcessor *utils.ImageProcessor
 	hub            *ws.Hub // Directly using Hub for simplicity
+	commentPolicy  domain.CommentPolicyResolver
+	topReviews     domain.TopReviewsProvider
+	reports        domain.ReportRepository
 	sfGroup        singleflight.Group
+	// searchSync carries index updates to a single goroutine, so writes to the same
+	// article reach the index in order
+	searchSync chan searchSyncOp
 }
 
-func NewArticleService(repo domain.ArticleRepository, mediaRepo domain.MediaRepository, auditServ domain.AuditService, seoService domain.SeoService, hub *ws.Hub) domain.ArticleService {
-	return &articleService{
+// searchSyncQueueSize bounds pending index updates; writers wait when it is full
+const searchSyncQueueSize = 1024
+
+type searchSyncOp struct {
+	id     uuid.UUID
+	remove bool
+}
+
+func NewArticleService(repo domain.ArticleRepository, mediaRepo domain.MediaRepository, auditServ domain.AuditService, seoService domain.SeoService, searchIndex domain.SearchIndex, hub *ws.Hub, commentPolicy domain.CommentPolicyResolver, topReviews domain.TopReviewsProvider, reports domain.ReportRepository) domain.ArticleService {
+	s := &articleService{
 		repo:           repo,
 		mediaRepo:      mediaRepo,
 		auditServ:      auditServ,
 		seoService:     seoService,
+		searchIndex:    searchIndex,
 		imageProcessor: utils.NewImageProcessor(),
 		hub:            hub,
+		commentPolicy:  commentPolicy,
+		topReviews:     topReviews,
+		reports:        reports,
+		searchSync:     make(chan searchSyncOp, searchSyncQueueSize),
 	}
+	go s.runSearchSync()
+	return s
 }
 
 func (s *articleService) CreateArticle(article *domain.Article) error {
@@ -72,7 +96,7 @@ func (s *articleService) CreateArticle(article *domain.Article) error {
 		{
 			OldStatus: "",
 			NewStatus: article.Status,
-			ChangedBy: article.AuthorID,
+			ChangedBy: &article.AuthorID,
 			Note:      "Article created",
 		},
 	}
@@ -90,6 +114,7 @@ func (s *articleService) CreateArticle(article *domain.Article) error {
 
 	// 7. Broadcast Event
 	s.broadcastEvent("article_created", article)
+	s.syncSearchIndex(article.ID)
 
 	// 8. Log Action
 	s.auditServ.LogAction(article.AuthorID, "CREATE", "articles", article.ID, nil, article)
@@ -97,7 +122,7 @@ func (s *articleService) CreateArticle(article *domain.Article) error {
 	return nil
 }
 
-func (s *articleService) GetArticles(page, limit int, filter map[string]interface{}) ([]domain.Article, int64, error) {
+func (s *articleService) GetArticles(page, limit int, query domain.ArticleQuery) ([]domain.Article, int64, error) {
 	if page < 1 {
 		page = 1
 	}
@@ -106,7 +131,14 @@ func (s *articleService) GetArticles(page, limit int, filter map[string]interfac
 	}
 	offset := (page - 1) * limit
 
-	return s.repo.GetAll(offset, limit, filter)
+	return s.repo.GetAll(offset, limit, query)
+}
+
+func (s *articleService) GetArticlesByCursor(req domain.CursorRequest, query domain.ArticleQuery) (*domain.PaginatedResult[domain.Article], error) {
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	return s.repo.GetAllByCursor(req, query)
 }
 
 func (s *articleService) GetArticleByID(id uuid.UUID) (*domain.Article, error) {
@@ -118,6 +150,7 @@ func (s *articleService) GetArticleByID(id uuid.UUID) (*domain.Article, error) {
 		}
 		if article != nil {
 			s.calculateArticleMetrics(article)
+			s.attachDetail(article)
 		}
 		return article, nil
 	})
@@ -128,6 +161,15 @@ func (s *articleService) GetArticleByID(id uuid.UUID) (*domain.Article, error) {
 	return v.(*domain.Article), nil
 }
 
+// attachDetail adds the fields only shown on the article detail page
+func (s *articleService) attachDetail(article *domain.Article) {
+	policy := s.commentPolicy.Resolve(article)
+	article.EffectiveCommentPolicy = &policy
+	if reviews, err := s.topReviews.TopReviews(article.ID); err == nil {
+		article.TopReviews = reviews
+	}
+}
+
 func (s *articleService) GetArticleBySlug(slug string) (*domain.Article, error) {
 	key := fmt.Sprintf("get_article_by_slug_%s", slug)
 	v, err, _ := s.sfGroup.Do(key, func() (interface{}, error) {
@@ -137,6 +179,7 @@ func (s *articleService) GetArticleBySlug(slug string) (*domain.Article, error)
 		}
 		if article != nil {
 			s.calculateArticleMetrics(article)
+			s.attachDetail(article)
 		}
 		return article, nil
 	})
@@ -242,6 +285,7 @@ func (s *articleService) UpdateArticle(article *domain.Article) error {
 	}
 
 	s.broadcastEvent("article_updated", article)
+	s.syncSearchIndex(article.ID)
 
 	// Log Action
 	s.auditServ.LogAction(article.AuthorID, "UPDATE", "articles", article.ID, existing, article)
@@ -300,6 +344,7 @@ func (s *articleService) DeleteArticle(id uuid.UUID) error {
 		return err
 	}
 	s.broadcastEvent("article_deleted", map[string]interface{}{"id": id})
+	s.searchSync <- searchSyncOp{id: id, remove: true}
 
 	// Log Action
 	s.auditServ.LogAction(uuid.Nil, "DELETE", "articles", id, nil, nil)
@@ -312,7 +357,26 @@ func (s *articleService) ChangeStatus(id uuid.UUID, newStatus domain.ArticleStat
 	if err != nil {
 		return err
 	}
+	return s.changeStatus(article, newStatus, &changedBy, note)
+}
+
+func (s *articleService) HideForReview(id uuid.UUID, note string) (bool, error) {
+	article, err := s.repo.GetByIDFull(id)
+	if err != nil {
+		return false, err
+	}
+	if article.Status != domain.StatusPublished {
+		return false, nil
+	}
+	if err := s.changeStatus(article, domain.StatusReview, nil, note); err != nil {
+		return false, err
+	}
+	return true, nil
+}
 
+// changeStatus records the transition in the status log; changedBy is nil for system changes
+func (s *articleService) changeStatus(article *domain.Article, newStatus domain.ArticleStatus, changedBy *uuid.UUID, note string) error {
+	id := article.ID
 	if article.Status == newStatus {
 		return nil
 	}
@@ -321,6 +385,7 @@ func (s *articleService) ChangeStatus(id uuid.UUID, newStatus domain.ArticleStat
 	article.Status = newStatus
 
 	now := time.Now()
+	republished := newStatus == domain.StatusPublished && article.PublishedAt != nil
 	if newStatus == domain.StatusPublished && article.PublishedAt == nil {
 		article.PublishedAt = &now
 	}
@@ -338,12 +403,21 @@ func (s *articleService) ChangeStatus(id uuid.UUID, newStatus domain.ArticleStat
 		return err
 	}
 
+	// Republishing is a moderator's verdict on the open reports, e.g. after an
+	// auto-hide; leaving them open would hide the article again on the next report
+	if changedBy != nil && republished {
+		if _, err := s.reports.ResolveOpenForTargets(domain.ReportTargetArticle, []uuid.UUID{id}, domain.ReportDismissed, "Bài viết đã được xuất bản lại", *changedBy, now.UTC()); err != nil {
+			logger.Get().Error("Failed to resolve reports of republished article", "article_id", id, "error", err)
+		}
+	}
+
 	// Broadcast
 	s.broadcastEvent("article_status_changed", map[string]interface{}{
 		"id":         id,
 		"old_status": oldStatus,
 		"new_status": newStatus,
 	})
+	s.syncSearchIndex(id)
 
 	return nil
 }
@@ -360,6 +434,34 @@ func (s *articleService) broadcastEvent(eventType string, payload interface{}) {
 	s.hub.Broadcast <- []byte(fmt.Sprintf(`{"type":"%s","payload":%v}`, eventType, utils.ToJSON(payload)))
 }
 
+// syncSearchIndex queues the article to be reloaded and re-indexed in the background
+func (s *articleService) syncSearchIndex(id uuid.UUID) {
+	s.searchSync <- searchSyncOp{id: id}
+}
+
+// runSearchSync applies queued index updates one at a time. Each update re-reads the
+// article, so the index ends up with its latest state whatever order the writes raced in.
+func (s *articleService) runSearchSync() {
+	for op := range s.searchSync {
+		if !op.remove {
+			article, err := s.repo.GetByID(op.id)
+			if err == nil {
+				if err := s.searchIndex.IndexArticle(article); err != nil {
+					logger.Get().Error("Failed to index article", "article_id", op.id, "error", err)
+				}
+				continue
+			}
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				logger.Get().Error("Failed to load article for search index", "article_id", op.id, "error", err)
+				continue
+			}
+		}
+		if err := s.searchIndex.RemoveArticle(op.id); err != nil {
+			logger.Get().Error("Failed to remove article from search index", "article_id", op.id, "error", err)
+		}
+	}
+}
+
 func (s *articleService) CreateArticleRedirect(articleID uuid.UUID, fromSlug string) error {
 	article, err := s.repo.GetByIDFull(articleID)
 	if err != nil {
@@ -5,7 +5,7 @@ import (
 	"fmt"
 )
 
-// SearchArticles implements article search with singleflight for deduplication
+// SearchArticles runs the query against the configured SearchIndex, with singleflight for deduplication
 func (s *articleService) SearchArticles(query string, page, limit int, filters map[string]interface{}) ([]domain.Article, int64, error) {
 	// Validation
 	if page < 1 {
@@ -24,7 +24,7 @@ func (s *articleService) SearchArticles(query string, page, limit int, filters m
 	// Use singleflight to prevent duplicate concurrent searches
 	key := fmt.Sprintf("search_%s_%d_%d_%v", query, page, limit, filters)
 	v, err, _ := s.sfGroup.Do(key, func() (interface{}, error) {
-		articles, total, err := s.repo.SearchArticles(query, offset, limit, filters)
+		articles, total, err := s.searchIndex.Search(query, offset, limit, filters)
 		if err != nil {
 			return nil, err
 		}
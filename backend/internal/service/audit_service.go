@@ -60,6 +60,13 @@ func (s *auditService) GetAuditLogs(page, limit int, filter map[string]interface
 	return s.repo.GetAuditLogs(page, limit, filter)
 }
 
+func (s *auditService) GetAuditLogsByCursor(req domain.CursorRequest, filter map[string]interface{}) (*domain.PaginatedResult[domain.AuditLog], error) {
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	return s.repo.GetAuditLogsByCursor(req, filter)
+}
+
 func (s *auditService) GetSystemLogs(page, limit int, filter map[string]interface{}) ([]domain.SystemLog, int64, error) {
 	return s.repo.GetSystemLogs(page, limit, filter)
 }
@@ -0,0 +1,178 @@
+package service
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const maxModerationBatch = 100
+
+func (s *commentService) GetModerationQueue(filter domain.CommentModerationFilter, page, limit int) ([]domain.Comment, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.repo.GetModerationQueue(filter, (page-1)*limit, limit)
+}
+
+// Moderate applies a status to a batch of comments, keeps articles.comment_count and the
+// parents' reply_count in sync with the visible (approved) comments and writes one audit
+// entry per comment. The comments' open reports are closed with the decision, so they
+// no longer count towards auto-hiding.
+func (s *commentService) Moderate(action domain.CommentModerationAction, moderatorID uuid.UUID) (*domain.CommentModerationResult, error) {
+	if !action.Status.IsValid() {
+		return nil, apperrors.NewBadRequest("Trạng thái kiểm duyệt không hợp lệ")
+	}
+	if len(action.IDs) == 0 {
+		return nil, apperrors.NewBadRequest("Chưa chọn bình luận nào")
+	}
+	if len(action.IDs) > maxModerationBatch {
+		return nil, apperrors.NewBadRequest("Tối đa 100 bình luận mỗi lần kiểm duyệt")
+	}
+	action.Reason = strings.TrimSpace(action.Reason)
+	if (action.Status == domain.CommentRejected || action.Status == domain.CommentSpam) && action.Reason == "" {
+		return nil, apperrors.NewBadRequest("Vui lòng nhập lý do khi từ chối hoặc đánh dấu spam")
+	}
+
+	comments, err := s.repo.GetByIDs(action.IDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+
+	result := &domain.CommentModerationResult{Updated: []uuid.UUID{}, NotFound: []uuid.UUID{}}
+	found := make(map[uuid.UUID]bool, len(comments))
+	var changed []domain.Comment
+	for _, c := range comments {
+		found[c.ID] = true
+		if c.Status != action.Status {
+			changed = append(changed, c)
+		}
+	}
+	for _, id := range action.IDs {
+		if !found[id] {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+	if len(changed) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uuid.UUID, len(changed))
+	for i, c := range changed {
+		ids[i] = c.ID
+	}
+	now := time.Now().UTC()
+	if err := s.repo.SetStatus(ids, action.Status, action.Reason, &moderatorID, now); err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+
+	for _, c := range changed {
+		from := c.Status
+		wasVisible := from == domain.CommentApproved && !c.IsDeleted
+		isVisible := action.Status == domain.CommentApproved && !c.IsDeleted
+		switch {
+		case isVisible && !wasVisible:
+			s.syncVisibleCounts(&c, 1)
+			s.notifyVisible(&c, nil)
+		case wasVisible && !isVisible:
+			s.syncVisibleCounts(&c, -1)
+		}
+
+		s.auditServ.LogAction(moderatorID, "MODERATE_"+string(action.Status), "comments", c.ID,
+			map[string]interface{}{"status": from},
+			map[string]interface{}{"status": action.Status, "reason": action.Reason})
+
+		c.Status = action.Status
+		c.IsSpam = action.Status == domain.CommentSpam
+		c.ModerationReason = action.Reason
+		c.ModeratedBy = &moderatorID
+		c.ModeratedAt = &now
+		result.Updated = append(result.Updated, c.ID)
+		result.Changes = append(result.Changes, domain.CommentStatusChange{Comment: c, From: from})
+	}
+
+	s.resolveReports(ids, action, moderatorID, now)
+	s.trainSpamFilter(result.Changes)
+	return result, nil
+}
+
+// resolveReports dismisses the open reports of approved comments and marks those of
+// rejected or spam comments as actioned
+func (s *commentService) resolveReports(ids []uuid.UUID, action domain.CommentModerationAction, moderatorID uuid.UUID, at time.Time) {
+	status, note := domain.ReportActioned, action.Reason
+	switch action.Status {
+	case domain.CommentApproved:
+		status, note = domain.ReportDismissed, "Bình luận đã được duyệt"
+	case domain.CommentPending:
+		return
+	}
+	if _, err := s.reports.ResolveOpenForTargets(domain.ReportTargetComment, ids, status, note, moderatorID, at); err != nil {
+		log.Printf("CommentService: failed to resolve reports of moderated comments: %v", err)
+	}
+}
+
+// HideForReview sends a visible comment back to the moderation queue as a system
+// action (e.g. after too many reports). It returns the comment when it was hidden.
+func (s *commentService) HideForReview(id uuid.UUID, reason string) (*domain.Comment, error) {
+	comment, err := s.repo.GetByID(id.String())
+	if err != nil {
+		return nil, err
+	}
+	if comment.IsDeleted || comment.Status != domain.CommentApproved {
+		return nil, nil
+	}
+
+	if err := s.repo.SetStatus([]uuid.UUID{id}, domain.CommentPending, reason, nil, time.Now().UTC()); err != nil {
+		return nil, err
+	}
+	s.syncVisibleCounts(comment, -1)
+	_ = s.auditServ.LogSystemEvent(nil, "AUTO_HIDE", "comments", id,
+		map[string]interface{}{"status": comment.Status},
+		map[string]interface{}{"status": domain.CommentPending, "reason": reason})
+
+	comment.Status = domain.CommentPending
+	comment.ModerationReason = reason
+	return comment, nil
+}
+
+// GetHistory returns a comment with all its earlier texts for moderators reviewing
+// reports about edited comments
+func (s *commentService) GetHistory(id uuid.UUID) (*domain.CommentHistory, error) {
+	comment, err := s.repo.GetByID(id.String())
+	if err != nil {
+		return nil, apperrors.NewNotFound("Bình luận không tồn tại")
+	}
+	revisions, err := s.repo.GetRevisions(id)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	return &domain.CommentHistory{Comment: comment, GuestEmail: comment.GuestEmail, Revisions: revisions}, nil
+}
+
+// trainSpamFilter feeds moderator decisions back to a trainable spam checker:
+// marking spam is a spam example, approving a held comment is a ham example.
+func (s *commentService) trainSpamFilter(changes []domain.CommentStatusChange) {
+	trainer, ok := s.spamChecker.(domain.SpamTrainer)
+	if !ok {
+		return
+	}
+	for _, ch := range changes {
+		var err error
+		switch {
+		case ch.Comment.Status == domain.CommentSpam:
+			err = trainer.Train(ch.Comment.Content, true)
+		case ch.Comment.Status == domain.CommentApproved && (ch.From == domain.CommentPending || ch.From == domain.CommentSpam):
+			err = trainer.Train(ch.Comment.Content, false)
+		}
+		if err != nil {
+			log.Printf("CommentService: spam training failed for %s: %v", ch.Comment.ID, err)
+		}
+	}
+}
@@ -0,0 +1,32 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"log"
+	"time"
+)
+
+type commentPolicyResolver struct {
+	categoryRepo domain.CategoryRepository
+	settingServ  domain.SettingService
+}
+
+func NewCommentPolicyResolver(categoryRepo domain.CategoryRepository, settingServ domain.SettingService) domain.CommentPolicyResolver {
+	return &commentPolicyResolver{categoryRepo: categoryRepo, settingServ: settingServ}
+}
+
+// Resolve reads the category chain and comment_default_policy (OPEN when unset).
+// A failed category lookup falls back to the default rather than blocking comments.
+func (r *commentPolicyResolver) Resolve(article *domain.Article) domain.EffectiveCommentPolicy {
+	settings, _ := r.settingServ.GetSettings()
+	fallback := domain.SettingCommentPolicy(settings, "comment_default_policy")
+
+	var ancestors []domain.Category
+	if article.CommentPolicy == nil && article.AllowComment {
+		var err error
+		if ancestors, err = r.categoryRepo.GetAncestors(article.CategoryID); err != nil {
+			log.Printf("CommentPolicyResolver: failed to load categories of %s: %v", article.ID, err)
+		}
+	}
+	return domain.ResolveCommentPolicy(article, ancestors, fallback, time.Now().UTC())
+}
@@ -0,0 +1,30 @@
+package service
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ToggleReaction adds or removes one of the user's reactions on a visible comment
+func (s *commentService) ToggleReaction(commentID, userID uuid.UUID, reaction domain.ReactionType) (*domain.CommentReactionSummary, error) {
+	if !reaction.IsValid() {
+		return nil, apperrors.NewBadRequest("Loại cảm xúc không hợp lệ")
+	}
+
+	comment, err := s.repo.GetByID(commentID.String())
+	if err != nil {
+		return nil, apperrors.NewNotFound("Bình luận không tồn tại")
+	}
+	// Held, rejected and withdrawn comments can't be reacted to
+	if comment.IsDeleted || comment.Status != domain.CommentApproved {
+		return nil, apperrors.NewNotFound("Bình luận không tồn tại")
+	}
+
+	summary, err := s.repo.ToggleReaction(commentID, userID, reaction)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	return summary, nil
+}
@@ -1,127 +1,351 @@
 package service
 
 import (
+	apperrors "backend/internal/core/error"
 	"backend/internal/domain"
 	"backend/internal/repository"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+const (
+	// defaultCommentMaxDepth is the deepest reply level when comment_max_depth is unset
+	defaultCommentMaxDepth = 10
+	maxRepliesPerLevel     = 50
+	// Defaults for comment_max_length and comment_edit_window_minutes
+	defaultCommentMaxLength  = 5000
+	defaultCommentEditWindow = 15
+)
+
 type CommentService interface {
-	Create(comment *domain.Comment) error
+	// Create checks the article's effective comment policy against the author's roles;
+	// rejections are *apperrors.AppError values
+	Create(comment *domain.Comment, roles []string) error
 	Update(id string, userID string, content string) error // userID to check ownership
 	GetByID(id string) (*domain.Comment, error)
-	GetByArticleID(articleID string, page, limit int) ([]domain.Comment, int64, error)
-	GetRepliesByParentID(parentID string, page, limit int) ([]domain.Comment, int64, error)
+	// Listings return one level of a thread with a bounded reply subtree below each comment
+	GetByArticleID(articleID string, opts domain.CommentThreadOptions, page, limit int) ([]domain.Comment, int64, error)
+	GetByArticleIDByCursor(articleID string, opts domain.CommentThreadOptions, req domain.CursorRequest) (*domain.PaginatedResult[domain.Comment], error)
+	GetRepliesByParentID(parentID string, opts domain.CommentThreadOptions, page, limit int) ([]domain.Comment, int64, error)
+	GetRepliesByParentIDByCursor(parentID string, opts domain.CommentThreadOptions, req domain.CursorRequest) (*domain.PaginatedResult[domain.Comment], error)
 	Delete(id string, userID string) error  // userID to check ownership
 	Restore(id string, userID string) error // userID to check ownership
+
+	// Reactions
+	ToggleReaction(commentID, userID uuid.UUID, reaction domain.ReactionType) (*domain.CommentReactionSummary, error)
+
+	// Moderation
+	GetModerationQueue(filter domain.CommentModerationFilter, page, limit int) ([]domain.Comment, int64, error)
+	Moderate(action domain.CommentModerationAction, moderatorID uuid.UUID) (*domain.CommentModerationResult, error)
+	HideForReview(id uuid.UUID, reason string) (*domain.Comment, error)
+	// GetHistory returns the comment with its earlier texts, for moderators only
+	GetHistory(id uuid.UUID) (*domain.CommentHistory, error)
 }
 
 type commentService struct {
 	repo        repository.CommentRepository
 	articleRepo domain.ArticleRepository
+	settingServ domain.SettingService
+	auditServ   domain.AuditService
+	spamChecker domain.SpamChecker
+	notifier    domain.NotificationService
+	policies    domain.CommentPolicyResolver
+	blocks      domain.UserBlockRepository
+	reports     domain.ReportRepository
 }
 
-func NewCommentService(repo repository.CommentRepository, articleRepo domain.ArticleRepository) CommentService {
+func NewCommentService(repo repository.CommentRepository, articleRepo domain.ArticleRepository, settingServ domain.SettingService, auditServ domain.AuditService, spamChecker domain.SpamChecker, notifier domain.NotificationService, policies domain.CommentPolicyResolver, blocks domain.UserBlockRepository, reports domain.ReportRepository) CommentService {
 	return &commentService{
 		repo:        repo,
 		articleRepo: articleRepo,
+		settingServ: settingServ,
+		auditServ:   auditServ,
+		spamChecker: spamChecker,
+		notifier:    notifier,
+		policies:    policies,
+		blocks:      blocks,
+		reports:     reports,
 	}
 }
 
-func (s *commentService) Create(comment *domain.Comment) error {
+func (s *commentService) Create(comment *domain.Comment, roles []string) error {
 	// Force UTC timestamp
 	comment.CreatedAt = time.Now().UTC()
 
 	if comment.ArticleID == uuid.Nil {
-		return errors.New("thiếu thông tin bài viết")
+		return apperrors.NewBadRequest("Thiếu thông tin bài viết")
 	}
-	if comment.UserID == uuid.Nil {
-		return errors.New("thiếu thông tin người dùng")
+	settings, _ := s.settingServ.GetSettings()
+	if comment.IsGuest() {
+		if !domain.SettingBool(settings, "guest_comments_enabled", false) {
+			return apperrors.NewForbidden("Vui lòng đăng nhập để bình luận")
+		}
+		if comment.GuestName == "" {
+			return apperrors.NewBadRequest("Vui lòng nhập tên hiển thị")
+		}
 	}
-	if comment.Content == "" {
-		return errors.New("nội dung không được để trống")
+	if err := validateCommentContent(comment.Content, settings); err != nil {
+		return apperrors.NewBadRequest(err.Error())
 	}
 
-	// Content length validation
-	const MaxCommentLength = 5000
-	const MinCommentLength = 1
-
-	contentLen := len([]rune(comment.Content)) // Count Unicode characters, not bytes
-	if contentLen > MaxCommentLength {
-		return errors.New("bình luận quá dài (tối đa 5000 ký tự)")
+	articles, err := s.articleRepo.GetByIDs([]uuid.UUID{comment.ArticleID})
+	if err != nil || len(articles) == 0 || articles[0].Status != domain.StatusPublished {
+		return apperrors.NewNotFound("Bài viết không tồn tại")
+	}
+	article := &articles[0]
+	policy := s.policies.Resolve(article)
+	if !policy.IsOpen {
+		return apperrors.NewForbidden("Bài viết này đã đóng bình luận")
 	}
-	if contentLen < MinCommentLength {
-		return errors.New("bình luận quá ngắn")
+	if !policy.AllowsRoles(roles, !comment.IsGuest()) {
+		return apperrors.NewForbidden("Bạn không có quyền bình luận bài viết này")
+	}
+	if !comment.IsGuest() && article.AuthorID != comment.UserID {
+		blocked, err := s.blocks.IsBlocked(article.AuthorID, comment.UserID)
+		if err != nil {
+			return apperrors.NewInternalError(err)
+		}
+		if blocked {
+			return apperrors.NewForbidden("Tác giả đã chặn bạn bình luận bài viết này")
+		}
 	}
 
-	// If replying, check if parent exists (optional but good practice)
+	// If replying, check the parent is a visible comment of the same article and the
+	// thread isn't too deep
+	var parent *domain.Comment
 	if comment.ParentID != nil && *comment.ParentID != uuid.Nil {
-		parent, err := s.repo.GetByID(comment.ParentID.String())
-		if err != nil {
-			return errors.New("bình luận phản hồi không tồn tại")
+		p, err := s.repo.GetByID(comment.ParentID.String())
+		if err != nil || p.ArticleID != comment.ArticleID || p.IsDeleted || p.Status != domain.CommentApproved {
+			return apperrors.NewNotFound("Bình luận phản hồi không tồn tại")
 		}
+		parent = p
+
+		maxDepth := domain.SettingInt(settings, "comment_max_depth", defaultCommentMaxDepth)
+		if parent.Depth+1 > maxDepth {
+			return apperrors.NewBadRequest(fmt.Sprintf("Đã đạt giới hạn độ sâu bình luận (tối đa %d cấp)", maxDepth))
+		}
+	}
+	comment.PlaceInThread(parent)
 
-		// Check depth limit (max 10 levels)
-		depth := s.calculateDepth(parent)
-		if depth >= 10 {
-			return errors.New("đã đạt giới hạn độ sâu bình luận (tối đa 10 cấp)")
+	// A failed lookup only loses the mention links, not the comment.
+	// Guests can't mention: mentions notify, and notifications need an actor.
+	if !comment.IsGuest() {
+		if mentions, err := s.notifier.ResolveMentions(comment.Content); err == nil {
+			comment.Mentions = mentions
 		}
 	}
 
-	// SPAM CHECK
-	lastComment, _ := s.repo.GetLastCommentByUserID(comment.UserID.String())
+	// SPAM CHECK (guests are throttled by the challenge and the route's rate limit)
+	var lastComment *domain.Comment
+	if !comment.IsGuest() {
+		lastComment, _ = s.repo.GetLastCommentByUserID(comment.UserID.String())
+	}
 	if lastComment != nil {
 		duration := time.Since(lastComment.CreatedAt)
 		if duration > 0 && duration < 3*time.Second {
-			return errors.New("bình luận quá nhanh, vui lòng đợi vài giây")
+			return apperrors.NewTooManyRequests("Bình luận quá nhanh, vui lòng đợi vài giây")
 		}
 		// Duplicate content check (within 30 seconds)
 		if lastComment.Content == comment.Content && time.Since(lastComment.CreatedAt) < 30*time.Second {
-			return errors.New("bạn vừa đăng nội dung này rồi, vui lòng đợi chút")
+			return apperrors.NewConflict("Bạn vừa đăng nội dung này rồi, vui lòng đợi chút", nil)
 		}
 	}
 
-	err := s.repo.Create(comment)
-	if err == nil {
-		// Sync comment_count in articles table
+	comment.Status = domain.CommentApproved
+	if policy.Mode == domain.CommentPolicyPreModerated || requiresPreModeration(settings, article) {
+		comment.Status = domain.CommentPending
+	}
+	s.applySpamVerdict(comment, settings)
+	if comment.IsGuest() && comment.Status == domain.CommentApproved {
+		// Guest comments always wait for a moderator
+		comment.Status = domain.CommentPending
+	}
+
+	if err := s.repo.Create(comment); err != nil {
+		return apperrors.NewInternalError(err)
+	}
+	if comment.Status == domain.CommentApproved {
+		// Pending comments are counted and announced once approved
+		s.syncVisibleCounts(comment, 1)
+		s.notifyVisible(comment, parent)
+	}
+	return nil
+}
+
+// validateCommentContent checks the length of new or edited content against
+// comment_max_length (default 5000 characters)
+func validateCommentContent(content string, settings map[string]interface{}) error {
+	if content == "" {
+		return errors.New("nội dung không được để trống")
+	}
+	maxLength := domain.SettingInt(settings, "comment_max_length", defaultCommentMaxLength)
+	// Count Unicode characters, not bytes
+	if len([]rune(content)) > maxLength {
+		return fmt.Errorf("bình luận quá dài (tối đa %d ký tự)", maxLength)
+	}
+	return nil
+}
+
+// notifyVisible notifies mentioned users and the parent's author once a comment is
+// visible. Notifications are unique per comment, so calling it again is harmless.
+func (s *commentService) notifyVisible(comment *domain.Comment, parent *domain.Comment) {
+	if comment.IsGuest() {
+		return
+	}
+	if parent == nil && comment.ParentID != nil && *comment.ParentID != uuid.Nil {
+		if p, err := s.repo.GetByID(comment.ParentID.String()); err == nil {
+			parent = p
+		}
+	}
+	var parentAuthorID *uuid.UUID
+	if parent != nil && !parent.IsGuest() {
+		parentAuthorID = &parent.UserID
+	}
+	s.notifier.NotifyComment(comment, parentAuthorID)
+}
+
+// syncVisibleCounts keeps articles.comment_count and the parent's reply_count in step
+// with a comment becoming visible (delta 1) or hidden (delta -1)
+func (s *commentService) syncVisibleCounts(comment *domain.Comment, delta int) {
+	if delta > 0 {
 		_ = s.articleRepo.IncrementCommentCount(comment.ArticleID)
+	} else {
+		_ = s.articleRepo.DecrementCommentCount(comment.ArticleID)
+	}
+	if comment.ParentID != nil && *comment.ParentID != uuid.Nil {
+		_ = s.repo.AdjustReplyCount(*comment.ParentID, delta)
 	}
-	return err
 }
 
-// calculateDepth recursively calculates the depth of a comment in the tree
-func (s *commentService) calculateDepth(comment *domain.Comment) int {
-	if comment.ParentID == nil || *comment.ParentID == uuid.Nil {
-		return 0
+// requiresPreModeration reports whether new comments on the article must wait for approval.
+// comment_premoderation enables it site-wide; comment_premoderation_category_ids lists
+// categories that are pre-moderated on their own.
+func requiresPreModeration(settings map[string]interface{}, article *domain.Article) bool {
+	if domain.SettingBool(settings, "comment_premoderation", false) {
+		return true
+	}
+	categoryID := article.CategoryID.String()
+	for _, id := range domain.SettingStrings(settings, "comment_premoderation_category_ids") {
+		if id == categoryID {
+			return true
+		}
 	}
+	return false
+}
 
-	parent, err := s.repo.GetByID(comment.ParentID.String())
+// applySpamVerdict scores the comment and routes it to the moderation queue:
+// at or above spam_review_threshold (default 0.5) an approved comment becomes
+// PENDING, at or above spam_auto_spam_threshold (default 0.9) it is marked SPAM.
+func (s *commentService) applySpamVerdict(comment *domain.Comment, settings map[string]interface{}) {
+	if s.spamChecker == nil {
+		return
+	}
+	verdict, err := s.spamChecker.Check(domain.SpamCheckInput{
+		Content:   comment.Content,
+		UserID:    comment.UserID,
+		ArticleID: comment.ArticleID,
+	})
 	if err != nil {
-		return 0 // If parent not found, treat as root level
+		return
+	}
+
+	comment.SpamScore = verdict.Score
+	comment.SpamReasons = nil
+	if len(verdict.Reasons) > 0 {
+		if b, err := json.Marshal(verdict.Reasons); err == nil {
+			comment.SpamReasons = domain.JSONB(b)
+		}
 	}
 
-	return 1 + s.calculateDepth(parent)
+	comment.Status = spamVerdictStatus(verdict.Score, comment.Status, settings)
+	comment.IsSpam = comment.Status == domain.CommentSpam
+}
+
+// spamVerdictStatus maps a spam score to a moderation status: SPAM at or above
+// spam_auto_spam_threshold (default 0.9), an approved item becomes PENDING at or
+// above spam_review_threshold (default 0.5), otherwise the status is kept
+func spamVerdictStatus(score float64, status domain.CommentStatus, settings map[string]interface{}) domain.CommentStatus {
+	switch {
+	case score >= domain.SettingFloat(settings, "spam_auto_spam_threshold", 0.9):
+		return domain.CommentSpam
+	case score >= domain.SettingFloat(settings, "spam_review_threshold", 0.5) && status == domain.CommentApproved:
+		return domain.CommentPending
+	}
+	return status
 }
 
 func (s *commentService) GetByID(id string) (*domain.Comment, error) {
 	return s.repo.GetByID(id)
 }
 
-func (s *commentService) GetByArticleID(articleID string, page, limit int) ([]domain.Comment, int64, error) {
-	return s.repo.GetByArticleID(articleID, page, limit)
+func (s *commentService) GetByArticleID(articleID string, opts domain.CommentThreadOptions, page, limit int) ([]domain.Comment, int64, error) {
+	return s.repo.GetByArticleID(articleID, s.threadOptions(opts), page, limit)
+}
+
+func (s *commentService) GetByArticleIDByCursor(articleID string, opts domain.CommentThreadOptions, req domain.CursorRequest) (*domain.PaginatedResult[domain.Comment], error) {
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	return s.repo.GetByArticleIDByCursor(articleID, s.threadOptions(opts), req)
 }
 
-func (s *commentService) GetRepliesByParentID(parentID string, page, limit int) ([]domain.Comment, int64, error) {
+func (s *commentService) GetRepliesByParentID(parentID string, opts domain.CommentThreadOptions, page, limit int) ([]domain.Comment, int64, error) {
 	// Validate parent exists
 	_, err := s.repo.GetByID(parentID)
 	if err != nil {
 		return nil, 0, errors.New("bình luận gốc không tồn tại")
 	}
 
-	return s.repo.GetRepliesByParentID(parentID, page, limit)
+	return s.repo.GetRepliesByParentID(parentID, s.threadOptions(opts), page, limit)
+}
+
+func (s *commentService) GetRepliesByParentIDByCursor(parentID string, opts domain.CommentThreadOptions, req domain.CursorRequest) (*domain.PaginatedResult[domain.Comment], error) {
+	if _, err := s.repo.GetByID(parentID); err != nil {
+		return nil, errors.New("bình luận gốc không tồn tại")
+	}
+	if req.Limit < 1 {
+		req.Limit = 10
+	}
+	return s.repo.GetRepliesByParentIDByCursor(parentID, s.threadOptions(opts), req)
+}
+
+// threadOptions fills unset thread limits from settings: comment_thread_depth reply
+// levels (default 3, never more than comment_max_depth) and comment_replies_per_level
+// replies per comment (default 5, at most 50). It also loads the viewer's muted and
+// blocked users so their comments are collapsed.
+func (s *commentService) threadOptions(opts domain.CommentThreadOptions) domain.CommentThreadOptions {
+	settings, _ := s.settingServ.GetSettings()
+	maxDepth := domain.SettingInt(settings, "comment_max_depth", defaultCommentMaxDepth)
+
+	if opts.Depth < 0 {
+		opts.Depth = domain.SettingInt(settings, "comment_thread_depth", 3)
+	}
+	if opts.Depth > maxDepth {
+		opts.Depth = maxDepth
+	}
+	if opts.RepliesLimit < 0 {
+		opts.RepliesLimit = domain.SettingInt(settings, "comment_replies_per_level", 5)
+	}
+	if opts.RepliesLimit > maxRepliesPerLevel {
+		opts.RepliesLimit = maxRepliesPerLevel
+	}
+	if !opts.Sort.IsValid() {
+		opts.Sort = domain.CommentSortNewest
+	}
+	if !opts.ReplySort.IsValid() {
+		opts.ReplySort = domain.CommentSortOldest
+	}
+	if opts.ViewerID != nil && *opts.ViewerID != uuid.Nil {
+		// Without the list, muted authors are simply shown expanded
+		if hidden, err := s.blocks.HiddenAuthors(*opts.ViewerID); err == nil {
+			opts.CollapsedAuthors = hidden
+		}
+	}
+	return opts
 }
 
 func (s *commentService) Update(id string, userID string, content string) error {
@@ -140,32 +364,50 @@ func (s *commentService) Update(id string, userID string, content string) error
 		return errors.New("không thể chỉnh sửa bình luận đã bị thu hồi")
 	}
 
-	// Check edit time limit (15 minutes)
-	const editTimeLimit = 15 * time.Minute
-	if time.Since(comment.CreatedAt) > editTimeLimit {
-		return errors.New("đã hết thời gian chỉnh sửa (15 phút)")
+	// Check the edit window; comment_edit_window_minutes of 0 allows edits at any time
+	settings, _ := s.settingServ.GetSettings()
+	window := domain.SettingInt(settings, "comment_edit_window_minutes", defaultCommentEditWindow)
+	if window > 0 && time.Since(comment.CreatedAt) > time.Duration(window)*time.Minute {
+		return fmt.Errorf("đã hết thời gian chỉnh sửa (%d phút)", window)
 	}
 
-	// Validate content
-	if content == "" {
-		return errors.New("nội dung không được để trống")
-	}
-
-	const MaxCommentLength = 5000
-	const MinCommentLength = 1
-	contentLen := len([]rune(content))
-	if contentLen > MaxCommentLength {
-		return errors.New("bình luận quá dài (tối đa 5000 ký tự)")
+	if err := validateCommentContent(content, settings); err != nil {
+		return err
 	}
-	if contentLen < MinCommentLength {
-		return errors.New("bình luận quá ngắn")
+	if content == comment.Content {
+		return nil
 	}
 
-	// Update content and timestamp
+	// Keep the replaced text as a revision, then update content and timestamps
+	now := time.Now().UTC()
+	previous := &domain.CommentRevision{
+		Content:  comment.Content,
+		EditedBy: comment.UserID,
+		EditedAt: now,
+	}
+	wasVisible := comment.Status == domain.CommentApproved
 	comment.Content = content
-	comment.UpdatedAt = time.Now().UTC()
+	comment.UpdatedAt = now
 
-	return s.repo.Update(comment)
+	// Edits go through the spam checker too, so links can't be slipped in after approval
+	s.applySpamVerdict(comment, settings)
+
+	if err := s.repo.Edit(comment, previous); err != nil {
+		return err
+	}
+	if mentions, err := s.notifier.ResolveMentions(content); err == nil {
+		if err := s.repo.ReplaceMentions(comment.ID, mentions); err == nil {
+			comment.Mentions = mentions
+		}
+	}
+	if wasVisible && comment.Status != domain.CommentApproved {
+		s.syncVisibleCounts(comment, -1)
+	}
+	if comment.Status == domain.CommentApproved {
+		// Users newly mentioned by the edit are notified
+		s.notifyVisible(comment, nil)
+	}
+	return nil
 }
 
 func (s *commentService) Delete(id string, userID string) error {
@@ -180,9 +422,8 @@ func (s *commentService) Delete(id string, userID string) error {
 	}
 
 	err = s.repo.Delete(id)
-	if err == nil {
-		// Sync comment_count in articles table
-		_ = s.articleRepo.DecrementCommentCount(comment.ArticleID)
+	if err == nil && comment.Status == domain.CommentApproved && !comment.IsDeleted {
+		s.syncVisibleCounts(comment, -1)
 	}
 	return err
 }
@@ -203,5 +444,9 @@ func (s *commentService) Restore(id string, userID string) error {
 		return errors.New("bình luận này chưa bị thu hồi")
 	}
 
-	return s.repo.Restore(id)
+	err = s.repo.Restore(id)
+	if err == nil && comment.Status == domain.CommentApproved {
+		s.syncVisibleCounts(comment, 1)
+	}
+	return err
 }
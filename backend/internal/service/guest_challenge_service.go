@@ -0,0 +1,142 @@
+package service
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"log"
+	"math/bits"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	guestChallengeTTL = 10 * time.Minute
+	// Bounds for guest_challenge_difficulty; 18 bits takes a browser well under a second
+	defaultGuestChallengeDifficulty = 18
+	minGuestChallengeDifficulty     = 8
+	maxGuestChallengeDifficulty     = 26
+)
+
+// guestChallengePayload is the signed part of a challenge token
+type guestChallengePayload struct {
+	Nonce      string `json:"n"`
+	ExpiresAt  int64  `json:"e"`
+	Difficulty int    `json:"d"`
+}
+
+type guestChallengeService struct {
+	settingServ domain.SettingService
+	secret      []byte
+
+	// used remembers solved tokens until they expire, so each one posts one comment.
+	// It is per process; several instances behind a balancer would need a shared store.
+	mu   sync.Mutex
+	used map[string]time.Time
+}
+
+// NewGuestChallengeService signs tokens with secret. Without one a random key is
+// generated, which invalidates outstanding challenges on restart.
+func NewGuestChallengeService(settingServ domain.SettingService, secret string) domain.GuestChallengeService {
+	key := []byte(secret)
+	if len(key) == 0 {
+		key = make([]byte, 32)
+		if _, err := rand.Read(key); err != nil {
+			log.Fatalf("GuestChallengeService: failed to generate signing key: %v", err)
+		}
+		log.Println("GuestChallengeService: CHALLENGE_SECRET not set, using a random signing key")
+	}
+	return &guestChallengeService{settingServ: settingServ, secret: key, used: make(map[string]time.Time)}
+}
+
+func (s *guestChallengeService) Issue() (*domain.GuestChallenge, error) {
+	settings, _ := s.settingServ.GetSettings()
+	if !domain.SettingBool(settings, "guest_comments_enabled", false) {
+		return nil, apperrors.NewForbidden("Bình luận không cần đăng nhập đang tắt")
+	}
+	difficulty := domain.SettingInt(settings, "guest_challenge_difficulty", defaultGuestChallengeDifficulty)
+	difficulty = max(minGuestChallengeDifficulty, min(difficulty, maxGuestChallengeDifficulty))
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	expiresAt := time.Now().UTC().Add(guestChallengeTTL).Truncate(time.Second)
+	payload, err := json.Marshal(guestChallengePayload{
+		Nonce:      base64.RawURLEncoding.EncodeToString(nonce),
+		ExpiresAt:  expiresAt.Unix(),
+		Difficulty: difficulty,
+	})
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+
+	return &domain.GuestChallenge{
+		Token:      encoded + "." + s.sign(encoded),
+		Algorithm:  "SHA-256",
+		Difficulty: difficulty,
+		ExpiresAt:  expiresAt,
+	}, nil
+}
+
+func (s *guestChallengeService) sign(encoded string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *guestChallengeService) Verify(token, solution string) error {
+	invalid := apperrors.NewBadRequest("Mã xác minh không hợp lệ, vui lòng thử lại")
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || solution == "" || len(solution) > 64 || !hmac.Equal([]byte(signature), []byte(s.sign(encoded))) {
+		return invalid
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return invalid
+	}
+	var payload guestChallengePayload
+	if err := json.Unmarshal(raw, &payload); err != nil {
+		return invalid
+	}
+	now := time.Now().UTC()
+	expiresAt := time.Unix(payload.ExpiresAt, 0)
+	if !now.Before(expiresAt) {
+		return apperrors.NewBadRequest("Mã xác minh đã hết hạn, vui lòng thử lại")
+	}
+
+	sum := sha256.Sum256([]byte(token + ":" + solution))
+	if leadingZeroBits(sum[:]) < payload.Difficulty {
+		return invalid
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for t, exp := range s.used {
+		if !now.Before(exp) {
+			delete(s.used, t)
+		}
+	}
+	if _, seen := s.used[token]; seen {
+		return apperrors.NewBadRequest("Mã xác minh đã được sử dụng")
+	}
+	s.used[token] = expiresAt
+	return nil
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, x := range b {
+		if x != 0 {
+			return n + bits.LeadingZeros8(x)
+		}
+		n += 8
+	}
+	return n
+}
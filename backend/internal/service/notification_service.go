@@ -0,0 +1,229 @@
+package service
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"backend/internal/ws"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	notificationExcerptLength = 150
+	maxMentionSuggestions     = 8
+)
+
+type notificationService struct {
+	repo   domain.NotificationRepository
+	blocks domain.UserBlockRepository
+	hub    *ws.Hub
+}
+
+func NewNotificationService(repo domain.NotificationRepository, blocks domain.UserBlockRepository, hub *ws.Hub) domain.NotificationService {
+	return &notificationService{repo: repo, blocks: blocks, hub: hub}
+}
+
+func (s *notificationService) ResolveMentions(content string) ([]domain.CommentMention, error) {
+	ids := domain.ParseMentionIDs(content)
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	users, err := s.repo.GetActiveUsers(ids)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[uuid.UUID]string, len(users))
+	for _, u := range users {
+		names[u.ID] = u.FullName
+	}
+
+	// Keep the order of appearance; unknown or inactive users are plain text
+	var mentions []domain.CommentMention
+	for _, id := range ids {
+		if name, ok := names[id]; ok {
+			mentions = append(mentions, domain.CommentMention{UserID: id, Name: name})
+		}
+	}
+	return mentions, nil
+}
+
+// NotifyComment stores and pushes "notification" events. A user who is both
+// mentioned and the parent's author gets only the mention. Nobody is notified of
+// their own comment or of comments by someone they blocked, and users' opt-outs
+// and muted threads are respected.
+func (s *notificationService) NotifyComment(comment *domain.Comment, parentAuthorID *uuid.UUID) {
+	types := make(map[uuid.UUID]domain.NotificationType)
+	var recipients []uuid.UUID
+	for _, m := range comment.Mentions {
+		if m.UserID != comment.UserID {
+			if _, ok := types[m.UserID]; !ok {
+				recipients = append(recipients, m.UserID)
+			}
+			types[m.UserID] = domain.NotificationMention
+		}
+	}
+	if parentAuthorID != nil && *parentAuthorID != comment.UserID {
+		if _, ok := types[*parentAuthorID]; !ok {
+			types[*parentAuthorID] = domain.NotificationReply
+			recipients = append(recipients, *parentAuthorID)
+		}
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	prefs, err := s.repo.GetPreferences(recipients)
+	if err != nil {
+		log.Printf("NotificationService: failed to load preferences: %v", err)
+		return
+	}
+	muted, err := s.repo.MutedUsers(recipients, comment.ArticleID)
+	if err != nil {
+		log.Printf("NotificationService: failed to load muted threads: %v", err)
+		return
+	}
+	blockers, err := s.blocks.BlockedBy(comment.UserID, recipients)
+	if err != nil {
+		log.Printf("NotificationService: failed to load blocks: %v", err)
+		return
+	}
+
+	now := time.Now().UTC()
+	excerpt := notificationExcerpt(comment.Content)
+	var notifications []domain.Notification
+	for _, userID := range recipients {
+		if muted[userID] || blockers[userID] {
+			continue
+		}
+		if pref, ok := prefs[userID]; ok && pref.Mutes(types[userID], now) {
+			continue
+		}
+		notifications = append(notifications, domain.Notification{
+			UserID:    userID,
+			Type:      types[userID],
+			ActorID:   comment.UserID,
+			ArticleID: comment.ArticleID,
+			CommentID: comment.ID,
+			Excerpt:   excerpt,
+			CreatedAt: now,
+		})
+	}
+	if len(notifications) == 0 {
+		return
+	}
+
+	created, err := s.repo.CreateIfAbsent(notifications)
+	if err != nil {
+		log.Printf("NotificationService: failed to store notifications for comment %s: %v", comment.ID, err)
+		return
+	}
+	for i := range created {
+		created[i].Actor = comment.User
+		s.hub.SendToUser(created[i].UserID, "notification", created[i])
+	}
+}
+
+// notificationExcerpt is the start of the comment with mention markup reduced to "@Name"
+func notificationExcerpt(content string) string {
+	text := strings.Join(strings.Fields(domain.StripMentionMarkup(content)), " ")
+	if r := []rune(text); len(r) > notificationExcerptLength {
+		text = string(r[:notificationExcerptLength]) + "…"
+	}
+	return text
+}
+
+func (s *notificationService) List(userID uuid.UUID, unreadOnly bool, page, limit int) ([]domain.Notification, int64, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+	notifications, total, err := s.repo.List(userID, unreadOnly, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, 0, apperrors.NewInternalError(err)
+	}
+	unread, err := s.repo.CountUnread(userID)
+	if err != nil {
+		return nil, 0, 0, apperrors.NewInternalError(err)
+	}
+	return notifications, total, unread, nil
+}
+
+func (s *notificationService) MarkRead(userID uuid.UUID, ids []uuid.UUID) error {
+	if len(ids) > 100 {
+		return apperrors.NewBadRequest("Tối đa 100 thông báo mỗi lần")
+	}
+	if err := s.repo.MarkRead(userID, ids, time.Now().UTC()); err != nil {
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+func (s *notificationService) GetPreference(userID uuid.UUID) (*domain.NotificationPreference, error) {
+	prefs, err := s.repo.GetPreferences([]uuid.UUID{userID})
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	pref, ok := prefs[userID]
+	if !ok {
+		pref = domain.NotificationPreference{UserID: userID}
+	}
+	if pref.MutedArticles, err = s.repo.ListMutes(userID); err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	return &pref, nil
+}
+
+func (s *notificationService) UpdatePreference(userID uuid.UUID, pref *domain.NotificationPreference) error {
+	pref.UserID = userID
+	pref.UpdatedAt = time.Now().UTC()
+	if pref.MutedUntil != nil && !pref.MutedUntil.After(pref.UpdatedAt) {
+		pref.MutedUntil = nil
+	}
+	if err := s.repo.SavePreference(pref); err != nil {
+		return apperrors.NewInternalError(err)
+	}
+	saved, err := s.GetPreference(userID)
+	if err != nil {
+		return err
+	}
+	*pref = *saved
+	return nil
+}
+
+func (s *notificationService) MuteArticle(userID, articleID uuid.UUID) error {
+	if err := s.repo.AddMute(userID, articleID); err != nil {
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+func (s *notificationService) UnmuteArticle(userID, articleID uuid.UUID) error {
+	if err := s.repo.RemoveMute(userID, articleID); err != nil {
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+func (s *notificationService) SuggestMentions(query string) ([]domain.MentionSuggestion, error) {
+	query = strings.TrimSpace(query)
+	suggestions := []domain.MentionSuggestion{}
+	if len([]rune(query)) < 2 {
+		return suggestions, nil
+	}
+	if len(query) > 100 {
+		return nil, apperrors.NewBadRequest("Từ khóa quá dài")
+	}
+	users, err := s.repo.SearchActiveUsers(query, maxMentionSuggestions)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	for _, u := range users {
+		suggestions = append(suggestions, domain.MentionSuggestion{ID: u.ID, FullName: u.FullName, AvatarURL: u.AvatarURL})
+	}
+	return suggestions, nil
+}
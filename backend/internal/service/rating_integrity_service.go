@@ -0,0 +1,83 @@
+package service
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// VoidRating removes a rating from the article's aggregates without deleting it, so
+// the voter cannot simply rate again, and records who voided it and why
+func (s *ratingService) VoidRating(id, moderatorID uuid.UUID, reason string) (*domain.ArticleRating, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, apperrors.NewBadRequest("Vui lòng nhập lý do vô hiệu hoá đánh giá")
+	}
+	rating, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound("Đánh giá không tồn tại")
+		}
+		return nil, apperrors.NewInternalError(err)
+	}
+	if rating.VoidedAt != nil {
+		return nil, apperrors.NewConflict("Đánh giá đã bị vô hiệu hoá", nil)
+	}
+
+	now := time.Now().UTC()
+	if err := s.repo.Void(id, moderatorID, reason, now, s.priorWeight()); err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	s.auditServ.LogAction(moderatorID, "VOID", "article_ratings", id,
+		map[string]interface{}{"article_id": rating.ArticleID, "user_id": rating.UserID, "score": rating.Score},
+		map[string]interface{}{"voided": true, "reason": reason})
+
+	rating.VoidedAt = &now
+	rating.VoidedBy = &moderatorID
+	rating.VoidReason = reason
+	return rating, nil
+}
+
+func (s *ratingService) CheckIntegrity() (*domain.RatingIntegrityReport, error) {
+	report := &domain.RatingIntegrityReport{StartedAt: time.Now().UTC()}
+	drifts, checked, err := s.repo.Reconcile(s.priorWeight())
+	if err != nil {
+		return nil, err
+	}
+	report.Articles = checked
+	report.Drifts = drifts
+	if report.Anomalies, err = s.FindAnomalies(); err != nil {
+		return nil, err
+	}
+	report.FinishedAt = time.Now().UTC()
+	return report, nil
+}
+
+// FindAnomalies looks for bursts of same-score extreme ratings from new accounts
+// within the last rating_anomaly_window_hours
+func (s *ratingService) FindAnomalies() ([]domain.RatingAnomaly, error) {
+	settings, _ := s.settingServ.GetSettings()
+	window := domain.SettingInt(settings, "rating_anomaly_window_hours", domain.DefaultRatingAnomalyWindowHours)
+	ageDays := domain.SettingInt(settings, "rating_anomaly_account_age_days", domain.DefaultRatingAnomalyAccountAgeDays)
+	minCount := domain.SettingInt(settings, "rating_anomaly_min_count", domain.DefaultRatingAnomalyMinCount)
+	if window < 1 {
+		window = domain.DefaultRatingAnomalyWindowHours
+	}
+	if ageDays < 1 {
+		ageDays = domain.DefaultRatingAnomalyAccountAgeDays
+	}
+	if minCount < 2 {
+		minCount = domain.DefaultRatingAnomalyMinCount
+	}
+
+	return s.repo.FindAnomalies(domain.RatingAnomalyOptions{
+		Since:         time.Now().UTC().Add(-time.Duration(window) * time.Hour),
+		MaxAccountAge: time.Duration(ageDays) * 24 * time.Hour,
+		MinCount:      minCount,
+	})
+}
@@ -11,12 +11,18 @@ import (
 type ratingService struct {
 	repo        domain.RatingRepository
 	articleRepo domain.ArticleRepository
+	settingServ domain.SettingService
+	spamChecker domain.SpamChecker
+	auditServ   domain.AuditService
 }
 
-func NewRatingService(repo domain.RatingRepository, articleRepo domain.ArticleRepository) domain.RatingService {
+func NewRatingService(repo domain.RatingRepository, articleRepo domain.ArticleRepository, settingServ domain.SettingService, spamChecker domain.SpamChecker, auditServ domain.AuditService) domain.RatingService {
 	return &ratingService{
 		repo:        repo,
 		articleRepo: articleRepo,
+		settingServ: settingServ,
+		spamChecker: spamChecker,
+		auditServ:   auditServ,
 	}
 }
 
@@ -28,11 +34,8 @@ func (s *ratingService) RateArticle(articleID, userID string, details domain.Rat
 		return errors.New("điểm đánh giá cho từng tiêu chí phải từ 1 đến 5 sao")
 	}
 
-	// Calculate overall score (rounded to nearest integer)
-	overallScore := int((float64(details.Content) + float64(details.Clarity) + float64(details.Relevance)) / 3.0)
-	if overallScore < 1 {
-		overallScore = 1 // Safety check, though logic above prevents < 1
-	}
+	// Overall star score, rounded to the nearest integer; averages use the exact mean
+	overallScore := domain.OverallRatingScore(details)
 
 	artUUID, err := uuid.Parse(articleID)
 	if err != nil {
@@ -68,6 +71,8 @@ func (s *ratingService) RateArticle(articleID, userID string, details domain.Rat
 			CreatedAt:      time.Now().UTC(),
 			UpdatedAt:      time.Now().UTC(),
 		}
+	} else if rating.VoidedAt != nil {
+		return errors.New("đánh giá của bạn cho bài viết này đã bị vô hiệu hoá")
 	} else {
 		// Update existing
 		rating.Score = overallScore
@@ -77,7 +82,23 @@ func (s *ratingService) RateArticle(articleID, userID string, details domain.Rat
 		rating.UpdatedAt = time.Now().UTC()
 	}
 
-	return s.repo.Upsert(rating)
+	if details.Review != nil {
+		if err := s.applyReview(rating, *details.Review); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Upsert(rating, s.priorWeight())
+}
+
+// priorWeight reads rating_prior_weight, the number of site-average ratings the
+// Bayesian score adds to every article
+func (s *ratingService) priorWeight() float64 {
+	settings, _ := s.settingServ.GetSettings()
+	if w := domain.SettingFloat(settings, "rating_prior_weight", domain.DefaultRatingPriorWeight); w >= 0 {
+		return w
+	}
+	return domain.DefaultRatingPriorWeight
 }
 
 func (s *ratingService) GetRatingStats(articleID string) (float64, int64, error) {
@@ -90,3 +111,16 @@ func (s *ratingService) GetUserRating(articleID, userID string) (*domain.Article
 	}
 	return s.repo.GetByArticleAndUser(articleID, userID)
 }
+
+func (s *ratingService) GetBreakdown(articleID uuid.UUID) (*domain.RatingBreakdown, error) {
+	breakdown, err := s.repo.GetBreakdown(articleID)
+	if err != nil {
+		return nil, err
+	}
+	if breakdown.PriorMean, err = s.repo.PriorMean(); err != nil {
+		return nil, err
+	}
+	breakdown.PriorWeight = s.priorWeight()
+	breakdown.BayesianScore = domain.BayesianRating(breakdown.Average, breakdown.Count, breakdown.PriorMean, breakdown.PriorWeight)
+	return breakdown, nil
+}
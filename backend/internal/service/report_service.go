@@ -0,0 +1,243 @@
+package service
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"backend/internal/ws"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	maxReportDetailsLength = 1000
+	maxReportResolveBatch  = 100
+)
+
+type ReportService interface {
+	// Report files a report; reporters can't report their own content or an item twice
+	Report(reporterID uuid.UUID, targetType domain.ReportTargetType, targetID uuid.UUID, reason domain.ReportReason, details string) (*domain.ReportResult, error)
+	List(filter domain.ReportFilter, page, limit int) ([]domain.ContentReport, int64, error)
+	Resolve(resolution domain.ReportResolution, moderatorID uuid.UUID) ([]uuid.UUID, error)
+}
+
+type reportService struct {
+	repo        domain.ReportRepository
+	commentServ CommentService
+	articleServ domain.ArticleService
+	articleRepo domain.ArticleRepository
+	settingServ domain.SettingService
+	auditServ   domain.AuditService
+	hub         *ws.Hub
+}
+
+func NewReportService(repo domain.ReportRepository, commentServ CommentService, articleServ domain.ArticleService, articleRepo domain.ArticleRepository, settingServ domain.SettingService, auditServ domain.AuditService, hub *ws.Hub) ReportService {
+	return &reportService{
+		repo:        repo,
+		commentServ: commentServ,
+		articleServ: articleServ,
+		articleRepo: articleRepo,
+		settingServ: settingServ,
+		auditServ:   auditServ,
+		hub:         hub,
+	}
+}
+
+func (s *reportService) Report(reporterID uuid.UUID, targetType domain.ReportTargetType, targetID uuid.UUID, reason domain.ReportReason, details string) (*domain.ReportResult, error) {
+	if !reason.IsValid() {
+		return nil, apperrors.NewBadRequest("Lý do báo cáo không hợp lệ")
+	}
+	details = strings.TrimSpace(details)
+	if len([]rune(details)) > maxReportDetailsLength {
+		return nil, apperrors.NewBadRequest("Mô tả quá dài (tối đa 1000 ký tự)")
+	}
+	if reason == domain.ReportOther && details == "" {
+		return nil, apperrors.NewBadRequest("Vui lòng mô tả vấn đề khi chọn lý do khác")
+	}
+
+	if err := s.checkTarget(reporterID, targetType, targetID); err != nil {
+		return nil, err
+	}
+
+	settings, _ := s.settingServ.GetSettings()
+	perHour := domain.SettingInt(settings, "report_max_per_hour", 10)
+	recent, err := s.repo.CountByReporterSince(reporterID, time.Now().UTC().Add(-time.Hour))
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	if recent >= int64(perHour) {
+		return nil, apperrors.NewTooManyRequests("Bạn đã gửi quá nhiều báo cáo, vui lòng thử lại sau")
+	}
+
+	report := &domain.ContentReport{
+		TargetType: targetType,
+		TargetID:   targetID,
+		ReporterID: reporterID,
+		Reason:     reason,
+		Details:    details,
+		Status:     domain.ReportOpen,
+	}
+	if err := s.repo.Create(report); err != nil {
+		if apperrors.IsUniqueConstraintViolation(err) {
+			return nil, apperrors.NewConflict("Bạn đã báo cáo nội dung này rồi", err)
+		}
+		return nil, apperrors.NewInternalError(err)
+	}
+
+	result := &domain.ReportResult{Report: report}
+	s.applyAutoHide(result, settings)
+	s.notifyModerators(result)
+	return result, nil
+}
+
+// checkTarget makes sure the reported item exists, is public and isn't the reporter's own
+func (s *reportService) checkTarget(reporterID uuid.UUID, targetType domain.ReportTargetType, targetID uuid.UUID) error {
+	switch targetType {
+	case domain.ReportTargetComment:
+		comment, err := s.commentServ.GetByID(targetID.String())
+		if err != nil || comment.IsDeleted || comment.Status != domain.CommentApproved {
+			return apperrors.NewNotFound("Bình luận không tồn tại")
+		}
+		if comment.UserID == reporterID {
+			return apperrors.NewBadRequest("Bạn không thể báo cáo bình luận của chính mình")
+		}
+	case domain.ReportTargetArticle:
+		articles, err := s.articleRepo.GetByIDs([]uuid.UUID{targetID})
+		if err != nil || len(articles) == 0 || articles[0].Status != domain.StatusPublished {
+			return apperrors.NewNotFound("Bài viết không tồn tại")
+		}
+		if articles[0].AuthorID == reporterID {
+			return apperrors.NewBadRequest("Bạn không thể báo cáo bài viết của chính mình")
+		}
+	default:
+		return apperrors.NewBadRequest("Loại nội dung báo cáo không hợp lệ")
+	}
+	return nil
+}
+
+// applyAutoHide hides the item once its open reports reach the threshold in settings:
+// report_auto_hide_comment_threshold (default 3) sends a comment back to the moderation
+// queue, report_auto_hide_article_threshold (default 0) moves an article to REVIEW.
+// A threshold of 0 disables auto-hiding for that kind of content. Approving a comment or
+// republishing an article closes its open reports, so only later reports count again.
+func (s *reportService) applyAutoHide(result *domain.ReportResult, settings map[string]interface{}) {
+	report := result.Report
+	key, fallback := "report_auto_hide_comment_threshold", 3
+	if report.TargetType == domain.ReportTargetArticle {
+		key, fallback = "report_auto_hide_article_threshold", 0
+	}
+	threshold := domain.SettingInt(settings, key, fallback)
+	if threshold <= 0 {
+		return
+	}
+	open, err := s.repo.CountOpen(report.TargetType, report.TargetID)
+	if err != nil || open < int64(threshold) {
+		return
+	}
+
+	reason := "Tự động ẩn do bị báo cáo nhiều lần"
+	switch report.TargetType {
+	case domain.ReportTargetComment:
+		comment, err := s.commentServ.HideForReview(report.TargetID, reason)
+		if err != nil {
+			log.Printf("ReportService: failed to auto-hide comment %s: %v", report.TargetID, err)
+			return
+		}
+		result.AutoHidden = comment != nil
+		result.HiddenComment = comment
+	case domain.ReportTargetArticle:
+		hidden, err := s.articleServ.HideForReview(report.TargetID, reason)
+		if err != nil {
+			log.Printf("ReportService: failed to auto-hide article %s: %v", report.TargetID, err)
+			return
+		}
+		if hidden {
+			_ = s.auditServ.LogSystemEvent(nil, "AUTO_HIDE", "articles", report.TargetID,
+				map[string]interface{}{"status": domain.StatusPublished},
+				map[string]interface{}{"status": domain.StatusReview, "reason": reason})
+		}
+		result.AutoHidden = hidden
+	}
+}
+
+// notifyModerators pushes a "report_created" event to every active admin and editor
+func (s *reportService) notifyModerators(result *domain.ReportResult) {
+	ids, err := s.repo.ModeratorIDs()
+	if err != nil {
+		log.Printf("ReportService: failed to list moderators: %v", err)
+		return
+	}
+	open, _ := s.repo.CountOpen(result.Report.TargetType, result.Report.TargetID)
+	payload := map[string]interface{}{
+		"id":           result.Report.ID,
+		"target_type":  result.Report.TargetType,
+		"target_id":    result.Report.TargetID,
+		"reason":       result.Report.Reason,
+		"open_reports": open,
+		"auto_hidden":  result.AutoHidden,
+	}
+	for _, id := range ids {
+		s.hub.SendToUser(id, "report_created", payload)
+	}
+	s.hub.BroadcastEvent("admin_data_updated", map[string]interface{}{"module": "reports", "action": "create"})
+}
+
+func (s *reportService) List(filter domain.ReportFilter, page, limit int) ([]domain.ContentReport, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	reports, total, err := s.repo.List(filter, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError(err)
+	}
+	return reports, total, nil
+}
+
+// Resolve moves reports to a triage state and writes one audit entry per report.
+// It returns the IDs that changed. Acting on the content itself is done with the
+// comment moderation or article status tools.
+func (s *reportService) Resolve(resolution domain.ReportResolution, moderatorID uuid.UUID) ([]uuid.UUID, error) {
+	if !resolution.Status.IsValid() {
+		return nil, apperrors.NewBadRequest("Trạng thái báo cáo không hợp lệ")
+	}
+	if len(resolution.IDs) == 0 {
+		return nil, apperrors.NewBadRequest("Chưa chọn báo cáo nào")
+	}
+	if len(resolution.IDs) > maxReportResolveBatch {
+		return nil, apperrors.NewBadRequest("Tối đa 100 báo cáo mỗi lần")
+	}
+	resolution.Note = strings.TrimSpace(resolution.Note)
+
+	reports, err := s.repo.GetByIDs(resolution.IDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	var changed []domain.ContentReport
+	for _, r := range reports {
+		if r.Status != resolution.Status {
+			changed = append(changed, r)
+		}
+	}
+	ids := make([]uuid.UUID, 0, len(changed))
+	for _, r := range changed {
+		ids = append(ids, r.ID)
+	}
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	if err := s.repo.Resolve(ids, resolution.Status, resolution.Note, moderatorID, time.Now().UTC()); err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	for _, r := range changed {
+		s.auditServ.LogAction(moderatorID, "REPORT_"+string(resolution.Status), "content_reports", r.ID,
+			map[string]interface{}{"status": r.Status},
+			map[string]interface{}{"status": resolution.Status, "note": resolution.Note})
+	}
+	return ids, nil
+}
@@ -0,0 +1,194 @@
+package service
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// applyReview sets, edits or removes the rating's written review. A new or changed
+// text is length-checked against review_max_length, scored by the spam checker and
+// held for moderation when review_premoderation is on.
+func (s *ratingService) applyReview(rating *domain.ArticleRating, text string) error {
+	text = strings.TrimSpace(text)
+	if text == rating.Review {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	rating.ModerationReason = ""
+	rating.ModeratedBy = nil
+	rating.ModeratedAt = nil
+	rating.SpamScore = 0
+	if text == "" {
+		rating.Review = ""
+		rating.ReviewStatus = ""
+		rating.ReviewUpdatedAt = nil
+		return nil
+	}
+
+	settings, _ := s.settingServ.GetSettings()
+	maxLength := domain.SettingInt(settings, "review_max_length", domain.DefaultReviewMaxLength)
+	// Count Unicode characters, not bytes
+	length := len([]rune(text))
+	if length < domain.MinReviewLength {
+		return fmt.Errorf("nhận xét quá ngắn (tối thiểu %d ký tự)", domain.MinReviewLength)
+	}
+	if length > maxLength {
+		return fmt.Errorf("nhận xét quá dài (tối đa %d ký tự)", maxLength)
+	}
+
+	rating.Review = text
+	rating.ReviewUpdatedAt = &now
+	rating.ReviewStatus = domain.CommentApproved
+	if domain.SettingBool(settings, "review_premoderation", false) {
+		rating.ReviewStatus = domain.CommentPending
+	}
+	if s.spamChecker != nil {
+		verdict, err := s.spamChecker.Check(domain.SpamCheckInput{
+			Content:   text,
+			UserID:    rating.UserID,
+			ArticleID: rating.ArticleID,
+		})
+		if err == nil {
+			rating.SpamScore = verdict.Score
+			rating.ReviewStatus = spamVerdictStatus(verdict.Score, rating.ReviewStatus, settings)
+		}
+	}
+	return nil
+}
+
+func (s *ratingService) ListReviews(articleID uuid.UUID, sort domain.ReviewSort, viewerID *uuid.UUID, page, limit int) ([]domain.ArticleRating, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	if !sort.IsValid() {
+		sort = domain.ReviewSortHelpful
+	}
+	return s.repo.ListReviews(articleID, sort, viewerID, (page-1)*limit, limit)
+}
+
+func (s *ratingService) VoteReview(reviewID, userID uuid.UUID, helpful *bool) (*domain.ArticleRating, error) {
+	review, err := s.repo.GetReviewByID(reviewID)
+	if err != nil || review.ReviewStatus != domain.CommentApproved || review.VoidedAt != nil {
+		return nil, apperrors.NewNotFound("Nhận xét không tồn tại")
+	}
+	if review.UserID == userID {
+		return nil, apperrors.NewForbidden("Không thể bình chọn cho nhận xét của chính mình")
+	}
+
+	updated, err := s.repo.Vote(reviewID, userID, helpful)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound("Nhận xét không tồn tại")
+		}
+		return nil, apperrors.NewInternalError(err)
+	}
+	updated.MyVote = helpful
+	return updated, nil
+}
+
+func (s *ratingService) GetReviewQueue(filter domain.ReviewFilter, page, limit int) ([]domain.ArticleRating, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	return s.repo.GetReviewQueue(filter, (page-1)*limit, limit)
+}
+
+// ModerateReviews applies a status to a batch of reviews and writes one audit entry per review
+func (s *ratingService) ModerateReviews(action domain.ReviewModerationAction, moderatorID uuid.UUID) (*domain.ReviewModerationResult, error) {
+	if !action.Status.IsValid() {
+		return nil, apperrors.NewBadRequest("Trạng thái kiểm duyệt không hợp lệ")
+	}
+	if len(action.IDs) == 0 {
+		return nil, apperrors.NewBadRequest("Chưa chọn nhận xét nào")
+	}
+	if len(action.IDs) > maxModerationBatch {
+		return nil, apperrors.NewBadRequest("Tối đa 100 nhận xét mỗi lần kiểm duyệt")
+	}
+	action.Reason = strings.TrimSpace(action.Reason)
+	if (action.Status == domain.CommentRejected || action.Status == domain.CommentSpam) && action.Reason == "" {
+		return nil, apperrors.NewBadRequest("Vui lòng nhập lý do khi từ chối hoặc đánh dấu spam")
+	}
+
+	reviews, err := s.repo.GetReviewsByIDs(action.IDs)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+
+	result := &domain.ReviewModerationResult{Updated: []uuid.UUID{}, NotFound: []uuid.UUID{}}
+	found := make(map[uuid.UUID]bool, len(reviews))
+	var changed []domain.ArticleRating
+	for _, r := range reviews {
+		found[r.ID] = true
+		if r.ReviewStatus != action.Status {
+			changed = append(changed, r)
+		}
+	}
+	for _, id := range action.IDs {
+		if !found[id] {
+			result.NotFound = append(result.NotFound, id)
+		}
+	}
+	if len(changed) == 0 {
+		return result, nil
+	}
+
+	ids := make([]uuid.UUID, len(changed))
+	for i, r := range changed {
+		ids[i] = r.ID
+	}
+	now := time.Now().UTC()
+	if err := s.repo.SetReviewStatus(ids, action.Status, action.Reason, moderatorID, now); err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+
+	for _, r := range changed {
+		s.auditServ.LogAction(moderatorID, "MODERATE_REVIEW_"+string(action.Status), "article_ratings", r.ID,
+			map[string]interface{}{"status": r.ReviewStatus},
+			map[string]interface{}{"status": action.Status, "reason": action.Reason})
+
+		r.ReviewStatus = action.Status
+		r.ModerationReason = action.Reason
+		r.ModeratedBy = &moderatorID
+		r.ModeratedAt = &now
+		result.Updated = append(result.Updated, r.ID)
+		result.Changes = append(result.Changes, r)
+	}
+	return result, nil
+}
+
+type topReviewsProvider struct {
+	repo        domain.RatingRepository
+	settingServ domain.SettingService
+}
+
+// NewTopReviewsProvider returns the top_reviews_count (default 3) most helpful reviews
+// of an article; a count of 0 disables the block
+func NewTopReviewsProvider(repo domain.RatingRepository, settingServ domain.SettingService) domain.TopReviewsProvider {
+	return &topReviewsProvider{repo: repo, settingServ: settingServ}
+}
+
+func (p *topReviewsProvider) TopReviews(articleID uuid.UUID) ([]domain.ArticleRating, error) {
+	settings, _ := p.settingServ.GetSettings()
+	limit := domain.SettingInt(settings, "top_reviews_count", domain.DefaultTopReviews)
+	if limit <= 0 {
+		return nil, nil
+	}
+	if limit > 20 {
+		limit = 20
+	}
+	return p.repo.TopReviews(articleID, limit)
+}
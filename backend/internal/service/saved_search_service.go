@@ -0,0 +1,149 @@
+package service
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+const maxSavedSearchesPerUser = 20
+
+type savedSearchService struct {
+	repo domain.SavedSearchRepository
+}
+
+func NewSavedSearchService(repo domain.SavedSearchRepository) domain.SavedSearchService {
+	return &savedSearchService{repo: repo}
+}
+
+func (s *savedSearchService) Create(userID uuid.UUID, search *domain.SavedSearch) error {
+	if err := validateSavedSearch(search); err != nil {
+		return err
+	}
+
+	count, err := s.repo.CountByUser(userID)
+	if err != nil {
+		return apperrors.NewInternalError(err)
+	}
+	if count >= maxSavedSearchesPerUser {
+		return apperrors.NewBadRequest("Bạn chỉ có thể lưu tối đa 20 tìm kiếm")
+	}
+
+	now := time.Now().UTC()
+	search.ID = uuid.Nil
+	search.UserID = userID
+	// Alerts only cover articles published after the search was saved
+	search.LastRunAt = &now
+	search.LastDigestAt = &now
+
+	if err := s.repo.Create(search); err != nil {
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+func (s *savedSearchService) List(userID uuid.UUID) ([]domain.SavedSearch, error) {
+	return s.repo.ListByUser(userID)
+}
+
+func (s *savedSearchService) Get(userID, id uuid.UUID) (*domain.SavedSearch, error) {
+	search, err := s.repo.GetByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, apperrors.NewNotFound("Không tìm thấy tìm kiếm đã lưu")
+		}
+		return nil, apperrors.NewInternalError(err)
+	}
+	// Saved searches are private; don't reveal that another user's ID exists
+	if search.UserID != userID {
+		return nil, apperrors.NewNotFound("Không tìm thấy tìm kiếm đã lưu")
+	}
+	return search, nil
+}
+
+func (s *savedSearchService) Update(userID uuid.UUID, search *domain.SavedSearch) error {
+	existing, err := s.Get(userID, search.ID)
+	if err != nil {
+		return err
+	}
+	if err := validateSavedSearch(search); err != nil {
+		return err
+	}
+
+	existing.Name = search.Name
+	existing.Query = search.Query
+	existing.CategoryID = search.CategoryID
+	existing.Frequency = search.Frequency
+	existing.UpdatedAt = time.Now().UTC()
+
+	if err := s.repo.Update(existing); err != nil {
+		return apperrors.NewInternalError(err)
+	}
+	*search = *existing
+	return nil
+}
+
+func (s *savedSearchService) Delete(userID, id uuid.UUID) error {
+	if _, err := s.Get(userID, id); err != nil {
+		return err
+	}
+	if err := s.repo.Delete(id); err != nil {
+		return apperrors.NewInternalError(err)
+	}
+	return nil
+}
+
+func (s *savedSearchService) GetMatches(userID, id uuid.UUID, page, limit int) ([]domain.SavedSearchMatch, int64, error) {
+	if _, err := s.Get(userID, id); err != nil {
+		return nil, 0, err
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 50 {
+		limit = 20
+	}
+
+	matches, total, err := s.repo.GetMatches(id, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError(err)
+	}
+	_ = s.repo.MarkRead(id, time.Now().UTC())
+	return matches, total, nil
+}
+
+func validateSavedSearch(search *domain.SavedSearch) error {
+	search.Name = strings.TrimSpace(search.Name)
+	search.Query = strings.TrimSpace(search.Query)
+
+	if search.Query == "" {
+		return apperrors.NewBadRequest("Từ khóa tìm kiếm không được để trống")
+	}
+	// Same limit as GET /articles/search
+	if len(search.Query) > 200 {
+		return apperrors.NewBadRequest("Từ khóa tìm kiếm quá dài (tối đa 200 ký tự)")
+	}
+	if search.Name == "" {
+		search.Name = search.Query
+		if len([]rune(search.Name)) > 100 {
+			search.Name = string([]rune(search.Name)[:100])
+		}
+	}
+	if len([]rune(search.Name)) > 100 {
+		return apperrors.NewBadRequest("Tên tìm kiếm quá dài (tối đa 100 ký tự)")
+	}
+
+	switch search.Frequency {
+	case "":
+		search.Frequency = domain.SavedSearchInstant
+	case domain.SavedSearchInstant, domain.SavedSearchDaily, domain.SavedSearchNone:
+	default:
+		return apperrors.NewBadRequest("Tần suất thông báo phải là INSTANT, DAILY hoặc NONE")
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type userBlockService struct {
+	repo     domain.UserBlockRepository
+	userRepo domain.UserRepository
+}
+
+func NewUserBlockService(repo domain.UserBlockRepository, userRepo domain.UserRepository) domain.UserBlockService {
+	return &userBlockService{repo: repo, userRepo: userRepo}
+}
+
+func (s *userBlockService) Add(blockerID, blockedID uuid.UUID, kind domain.BlockKind) (*domain.UserBlock, error) {
+	if !kind.IsValid() {
+		return nil, apperrors.NewBadRequest("Loại chặn không hợp lệ")
+	}
+	if blockerID == blockedID {
+		return nil, apperrors.NewBadRequest("Bạn không thể chặn chính mình")
+	}
+	blocked, err := s.userRepo.GetUserByID(blockedID)
+	if err != nil {
+		return nil, apperrors.NewNotFound("Người dùng không tồn tại")
+	}
+	count, err := s.repo.Count(blockerID, kind)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	if count >= domain.MaxUserBlocks {
+		return nil, apperrors.NewBadRequest(fmt.Sprintf("Danh sách đã đạt giới hạn %d người", domain.MaxUserBlocks))
+	}
+
+	block := &domain.UserBlock{BlockerID: blockerID, BlockedID: blockedID, Kind: kind, CreatedAt: time.Now().UTC()}
+	if err := s.repo.Add(block); err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	block.Blocked = &domain.User{ID: blocked.ID, FullName: blocked.FullName, AvatarURL: blocked.AvatarURL}
+	return block, nil
+}
+
+func (s *userBlockService) Remove(blockerID, blockedID uuid.UUID, kind domain.BlockKind) error {
+	if !kind.IsValid() {
+		return apperrors.NewBadRequest("Loại chặn không hợp lệ")
+	}
+	removed, err := s.repo.Remove(blockerID, blockedID, kind)
+	if err != nil {
+		return apperrors.NewInternalError(err)
+	}
+	if !removed {
+		return apperrors.NewNotFound("Không tìm thấy người dùng trong danh sách")
+	}
+	return nil
+}
+
+func (s *userBlockService) List(blockerID uuid.UUID, kind domain.BlockKind, page, limit int) ([]domain.UserBlock, int64, error) {
+	if kind != "" && !kind.IsValid() {
+		return nil, 0, apperrors.NewBadRequest("Loại chặn không hợp lệ")
+	}
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	blocks, total, err := s.repo.List(blockerID, kind, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError(err)
+	}
+	return blocks, total, nil
+}
+
+func (s *userBlockService) Stats(page, limit int) ([]domain.UserBlockStats, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	stats, total, err := s.repo.Stats((page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError(err)
+	}
+	return stats, total, nil
+}
+
+func (s *userBlockService) ListReceived(blockedID uuid.UUID, page, limit int) ([]domain.UserBlock, int64, error) {
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+	blocks, total, err := s.repo.ListReceived(blockedID, (page-1)*limit, limit)
+	if err != nil {
+		return nil, 0, apperrors.NewInternalError(err)
+	}
+	return blocks, total, nil
+}
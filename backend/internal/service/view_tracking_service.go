@@ -3,15 +3,39 @@ package service
 import (
 	"backend/internal/domain"
 	"errors"
+	"regexp"
+	"sync"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+// engagementSessionID is the random per page load ID sent with engagement pings
+var engagementSessionID = regexp.MustCompile(`^[A-Za-z0-9_-]{8,64}$`)
+
+const (
+	articleResolveTTL        = 5 * time.Minute
+	articleResolveMaxEntries = 10000
+)
+
+type resolvedArticle struct {
+	id      uuid.UUID
+	expires time.Time
+}
+
 type viewTrackingService struct {
 	viewRepo    domain.ViewTrackingRepository
 	articleRepo domain.ArticleRepository
 	seoService  domain.SeoService
+	ingester    domain.ViewIngester
+	classifier  domain.ViewClassifier
+	visitors    domain.VisitorIdentifier
+	sources     domain.ViewSourceResolver
+	engagements domain.EngagementRecorder
+
+	// resolved caches identifier (ID, slug or alias) -> article ID for the hot tracking path
+	resolveMu sync.Mutex
+	resolved  map[string]resolvedArticle
 }
 
 // NewViewTrackingService creates a new view tracking service
@@ -19,22 +43,129 @@ func NewViewTrackingService(
 	viewRepo domain.ViewTrackingRepository,
 	articleRepo domain.ArticleRepository,
 	seoService domain.SeoService,
+	ingester domain.ViewIngester,
+	classifier domain.ViewClassifier,
+	visitors domain.VisitorIdentifier,
+	sources domain.ViewSourceResolver,
+	engagements domain.EngagementRecorder,
 ) domain.ViewTrackingService {
 	return &viewTrackingService{
 		viewRepo:    viewRepo,
 		articleRepo: articleRepo,
 		seoService:  seoService,
+		ingester:    ingester,
+		classifier:  classifier,
+		visitors:    visitors,
+		sources:     sources,
+		engagements: engagements,
+		resolved:    make(map[string]resolvedArticle),
 	}
 }
 
-// TrackView processes and records a view if it meets all criteria
-func (s *viewTrackingService) TrackView(identifier string, ipAddress string, userAgent string, sessionDuration int) error {
+// TrackView validates a view and hands it to the ingestion pipeline, which
+// deduplicates and stores it asynchronously
+func (s *viewTrackingService) TrackView(identifier string, visit domain.ViewVisit, sessionDuration int) error {
 	// 1. Validate minimum session duration (30 seconds)
 	if sessionDuration < 30 {
 		return errors.New("session duration must be at least 30 seconds")
 	}
 
-	// 2. Get article by ID or Slug
+	// 2. Get article by ID, slug or alias
+	articleID, err := s.resolveArticle(identifier)
+	if err != nil {
+		return errors.New("article not found")
+	}
+
+	// 3. Label bots and suspicious clients; their views are stored but not counted
+	class := s.classifier.Classify(visit.IPAddress, visit.UserAgent)
+
+	// 4. Replace the IP by the daily visitor hash; the raw IP is only kept while
+	// view_ip_retention_days allows it
+	now := time.Now()
+	identity, err := s.visitors.Identify(visit.IPAddress, visit.UserAgent, now)
+	if err != nil {
+		return err
+	}
+
+	// 5. Buffer the view with its channel, campaign and device; the pipeline drops
+	// repeats of the same visitor on the same day
+	view := domain.ArticleView{
+		ArticleID:       articleID,
+		VisitorID:       identity.VisitorID,
+		SessionDuration: sessionDuration,
+		ViewedAt:        now,
+		Classification:  class.Class,
+		ClassReason:     class.Reason,
+		ViewSource:      s.sources.Resolve(visit),
+	}
+	if identity.KeepIP {
+		view.IPAddress = &visit.IPAddress
+		view.UserAgent = visit.UserAgent
+	}
+	if !s.ingester.Enqueue(view) {
+		return errors.New("view buffer full")
+	}
+	return nil
+}
+
+// TrackEngagement records a read-depth ping. Pings from bots and suspicious clients
+// are accepted but not stored, like their views are not counted.
+func (s *viewTrackingService) TrackEngagement(identifier string, visit domain.ViewVisit, ping domain.EngagementPing) error {
+	if !engagementSessionID.MatchString(ping.SessionID) {
+		return errors.New("invalid session id")
+	}
+	if ping.ScrollDepth < 0 || ping.ScrollDepth > 100 || ping.ActiveSeconds < 0 {
+		return errors.New("invalid engagement ping")
+	}
+	if ping.ActiveSeconds > domain.MaxEngagementActiveSeconds {
+		ping.ActiveSeconds = domain.MaxEngagementActiveSeconds
+	}
+
+	articleID, err := s.resolveArticle(identifier)
+	if err != nil {
+		return errors.New("article not found")
+	}
+	if s.classifier.Classify(visit.IPAddress, visit.UserAgent).Class != domain.ViewHuman {
+		return nil
+	}
+
+	now := time.Now().UTC()
+	identity, err := s.visitors.Identify(visit.IPAddress, visit.UserAgent, now)
+	if err != nil {
+		return err
+	}
+
+	// A reader who reached the end has seen the whole article
+	scroll := ping.ScrollDepth
+	if ping.ReachedEnd {
+		scroll = 100
+	}
+	session := domain.ArticleEngagement{
+		ArticleID:     articleID,
+		SessionID:     ping.SessionID,
+		VisitorID:     identity.VisitorID,
+		MaxScroll:     scroll,
+		ActiveSeconds: ping.ActiveSeconds,
+		ReachedEnd:    ping.ReachedEnd,
+		Pings:         1,
+		StartedAt:     now,
+		UpdatedAt:     now,
+	}
+	if !s.engagements.Record(session) {
+		return errors.New("engagement buffer full")
+	}
+	return nil
+}
+
+func (s *viewTrackingService) resolveArticle(identifier string) (uuid.UUID, error) {
+	now := time.Now()
+	s.resolveMu.Lock()
+	if r, ok := s.resolved[identifier]; ok && now.Before(r.expires) {
+		s.resolveMu.Unlock()
+		return r.id, nil
+	}
+	s.resolveMu.Unlock()
+
 	var article *domain.Article
 	var err error
 
@@ -54,37 +185,26 @@ func (s *viewTrackingService) TrackView(identifier string, ipAddress string, use
 	}
 
 	if err != nil || article == nil {
-		return errors.New("article not found")
+		return uuid.Nil, errors.New("article not found")
 	}
 
-	// 3. Check for duplicate view within 24 hours (deduplication)
-	// Same IP address cannot count multiple times within 24h
-	hasRecent, err := s.viewRepo.HasRecentView(article.ID, ipAddress, 24*time.Hour)
-	if err != nil {
-		return err
-	}
-
-	if hasRecent {
-		// Silent ignore - don't throw error to maintain smooth UX
-		// The view is valid but we don't count duplicates
-		return nil
-	}
-
-	// 4. Record view
-	view := &domain.ArticleView{
-		ArticleID:       article.ID,
-		IPAddress:       ipAddress,
-		UserAgent:       userAgent,
-		SessionDuration: sessionDuration,
-		ViewedAt:        time.Now(),
+	s.resolveMu.Lock()
+	if len(s.resolved) >= articleResolveMaxEntries {
+		s.resolved = make(map[string]resolvedArticle)
 	}
+	s.resolved[identifier] = resolvedArticle{id: article.ID, expires: now.Add(articleResolveTTL)}
+	s.resolveMu.Unlock()
+	return article.ID, nil
+}
 
-	if err := s.viewRepo.RecordView(view); err != nil {
-		return err
+func (s *viewTrackingService) ForgetArticle(id uuid.UUID) {
+	s.resolveMu.Lock()
+	defer s.resolveMu.Unlock()
+	for identifier, r := range s.resolved {
+		if r.id == id {
+			delete(s.resolved, identifier)
+		}
 	}
-
-	// 5. Increment denormalized view_count in articles table for display
-	return s.articleRepo.IncrementViewCount(article.ID)
 }
 
 // GetArticleViewCount returns the count of valid views for an article by slug
@@ -102,3 +222,60 @@ func (s *viewTrackingService) GetArticleViewCount(articleSlug string) (int64, er
 func (s *viewTrackingService) GetArticleViewCountByID(articleID uuid.UUID) (int64, error) {
 	return s.viewRepo.GetValidViewCount(articleID)
 }
+
+// GetTrafficStats reports human vs filtered traffic between from and to
+func (s *viewTrackingService) GetTrafficStats(from, to time.Time) (*domain.ViewTrafficStats, error) {
+	return s.viewRepo.TrafficStats(from, to, 10)
+}
+
+// GetUniqueVisitors counts the human visitors of the last day, week and month, with
+// a daily series for the month, site-wide or for one article
+func (s *viewTrackingService) GetUniqueVisitors(articleID *uuid.UUID) (*domain.UniqueVisitorStats, error) {
+	tomorrow := domain.VisitorDay(time.Now()).AddDate(0, 0, 1)
+	stats := &domain.UniqueVisitorStats{ArticleID: articleID}
+
+	periods := []struct {
+		days int
+		out  *int64
+	}{
+		{1, &stats.Daily},
+		{7, &stats.Weekly},
+		{30, &stats.Monthly},
+	}
+	for _, p := range periods {
+		count, err := s.viewRepo.CountUniqueVisitors(articleID, tomorrow.AddDate(0, 0, -p.days), tomorrow)
+		if err != nil {
+			return nil, err
+		}
+		*p.out = count
+	}
+
+	series, err := s.viewRepo.DailyUniqueVisitors(articleID, tomorrow.AddDate(0, 0, -30), tomorrow)
+	if err != nil {
+		return nil, err
+	}
+	stats.Series = series
+	return stats, nil
+}
+
+// GetSourceStats breaks the human views of [q.From, q.To) down by each group. Raw
+// views are pruned after view_raw_retention_days, so older ranges come back empty.
+func (s *viewTrackingService) GetSourceStats(groups []domain.ViewSourceGroup, q domain.ViewSourceQuery) (*domain.ViewSourceStats, error) {
+	if q.Limit < 1 || q.Limit > 100 {
+		q.Limit = 20
+	}
+	stats := &domain.ViewSourceStats{
+		From:       q.From,
+		To:         q.To,
+		ArticleID:  q.ArticleID,
+		Breakdowns: make(map[domain.ViewSourceGroup][]domain.ViewSourceCount, len(groups)),
+	}
+	for _, g := range groups {
+		rows, err := s.viewRepo.SourceBreakdown(g, q)
+		if err != nil {
+			return nil, err
+		}
+		stats.Breakdowns[g] = rows
+	}
+	return stats, nil
+}
@@ -0,0 +1,77 @@
+package service
+
+import (
+	"backend/internal/domain"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"sync"
+	"time"
+)
+
+// visitorSettingsRefresh is how long view_ip_retention_days is cached
+const visitorSettingsRefresh = time.Minute
+
+type visitorIdentifier struct {
+	salts       domain.VisitorSaltRepository
+	settingServ domain.SettingService
+
+	mu            sync.Mutex
+	day           time.Time
+	salt          []byte
+	retentionDays int
+	settingsAt    time.Time
+}
+
+// NewVisitorIdentifier hashes IP and user agent with the salt of the view's UTC day
+// (see domain/visitor.go). The current salt and retention setting are cached.
+func NewVisitorIdentifier(salts domain.VisitorSaltRepository, settingServ domain.SettingService) domain.VisitorIdentifier {
+	return &visitorIdentifier{salts: salts, settingServ: settingServ}
+}
+
+func (v *visitorIdentifier) Identify(ipAddress, userAgent string, at time.Time) (domain.VisitorIdentity, error) {
+	salt, retentionDays, err := v.current(domain.VisitorDay(at))
+	if err != nil {
+		return domain.VisitorIdentity{}, err
+	}
+
+	mac := hmac.New(sha256.New, salt)
+	mac.Write([]byte(ipAddress))
+	mac.Write([]byte{0})
+	mac.Write([]byte(userAgent))
+	return domain.VisitorIdentity{
+		VisitorID: hex.EncodeToString(mac.Sum(nil)[:16]),
+		KeepIP:    retentionDays > 0,
+	}, nil
+}
+
+func (v *visitorIdentifier) current(day time.Time) ([]byte, int, error) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	if !v.day.Equal(day) {
+		candidate := make([]byte, 32)
+		if _, err := rand.Read(candidate); err != nil {
+			return nil, 0, err
+		}
+		salt, err := v.salts.GetOrCreate(day, candidate)
+		if err != nil {
+			return nil, 0, err
+		}
+		v.day, v.salt = day, salt
+	}
+
+	if time.Since(v.settingsAt) >= visitorSettingsRefresh {
+		// Fail closed: on a read error keep the last value, which is 0 (no raw IPs)
+		// until the settings have been loaded once
+		if settings, err := v.settingServ.GetSettings(); err != nil {
+			log.Printf("VisitorIdentifier: failed to fetch settings: %v", err)
+		} else {
+			v.retentionDays = domain.SettingInt(settings, "view_ip_retention_days", domain.DefaultViewIPRetentionDays)
+		}
+		v.settingsAt = time.Now()
+	}
+	return v.salt, v.retentionDays, nil
+}
@@ -0,0 +1,164 @@
+package spam
+
+import (
+	"backend/internal/domain"
+	"backend/internal/search"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// docsToken stores the number of trained spam/ham documents alongside the token counts
+	docsToken = "__docs__"
+	// The classifier stays silent until it has seen this many decisions of each kind
+	minTrainingDocs = 10
+	// Only the most telling tokens of a comment take part in the verdict
+	maxInterestingTokens = 15
+	maxTokenLength       = 100
+)
+
+type tokenCounts struct {
+	spam, ham int64
+}
+
+// BayesChecker is a naive-Bayes classifier trained on moderator spam/ham decisions.
+// Counts live in memory and are persisted through SpamTokenRepository.
+type BayesChecker struct {
+	repo domain.SpamTokenRepository
+
+	mu       sync.RWMutex
+	tokens   map[string]tokenCounts
+	spamDocs int64
+	hamDocs  int64
+}
+
+// NewBayesChecker loads the learned token counts
+func NewBayesChecker(repo domain.SpamTokenRepository) (*BayesChecker, error) {
+	b := &BayesChecker{repo: repo, tokens: make(map[string]tokenCounts)}
+
+	rows, err := repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	for _, r := range rows {
+		if r.Token == docsToken {
+			b.spamDocs, b.hamDocs = r.SpamCount, r.HamCount
+			continue
+		}
+		b.tokens[r.Token] = tokenCounts{spam: r.SpamCount, ham: r.HamCount}
+	}
+	return b, nil
+}
+
+func (b *BayesChecker) Name() string {
+	return "bayes"
+}
+
+// bayesTokens returns the distinct analyzed tokens of text
+func bayesTokens(text string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, t := range search.Analyze(text) {
+		if len(t) > maxTokenLength || seen[t] {
+			continue
+		}
+		seen[t] = true
+		out = append(out, t)
+	}
+	for _, link := range extractLinks(text) {
+		if host := linkHost(link); host != "" && len(host) <= maxTokenLength-5 && !seen["host:"+host] {
+			seen["host:"+host] = true
+			out = append(out, "host:"+host)
+		}
+	}
+	return out
+}
+
+func (b *BayesChecker) Check(input domain.SpamCheckInput) (domain.SpamVerdict, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	if b.spamDocs < minTrainingDocs || b.hamDocs < minTrainingDocs {
+		return domain.SpamVerdict{}, nil
+	}
+
+	type scored struct {
+		token string
+		p     float64
+	}
+	var probs []scored
+	for _, t := range bayesTokens(input.Content) {
+		c, ok := b.tokens[t]
+		if !ok {
+			continue
+		}
+		// Per-class frequencies so an imbalanced training set doesn't skew every token
+		ps := float64(c.spam) / float64(b.spamDocs)
+		ph := float64(c.ham) / float64(b.hamDocs)
+		p := ps / (ps + ph)
+		// Shrink rarely seen tokens towards 0.5 (Robinson's correction, s=1)
+		n := float64(c.spam + c.ham)
+		p = (0.5 + n*p) / (1 + n)
+		probs = append(probs, scored{t, math.Max(0.01, math.Min(0.99, p))})
+	}
+	if len(probs) == 0 {
+		return domain.SpamVerdict{}, nil
+	}
+
+	sort.Slice(probs, func(i, j int) bool {
+		return math.Abs(probs[i].p-0.5) > math.Abs(probs[j].p-0.5)
+	})
+	if len(probs) > maxInterestingTokens {
+		probs = probs[:maxInterestingTokens]
+	}
+
+	// Combine in log space to avoid underflow
+	var logSpam, logHam float64
+	var spammy []string
+	for _, s := range probs {
+		logSpam += math.Log(s.p)
+		logHam += math.Log(1 - s.p)
+		if s.p > 0.8 && len(spammy) < 5 {
+			spammy = append(spammy, strings.TrimPrefix(s.token, "host:"))
+		}
+	}
+	prob := 1 / (1 + math.Exp(logHam-logSpam))
+
+	// Only a spam-leaning verdict contributes; 0.5 maps to 0 and 1 to 1
+	score := (prob - 0.5) * 2
+	detail := fmt.Sprintf("Xác suất spam %.0f%%", prob*100)
+	if len(spammy) > 0 {
+		detail += " (" + strings.Join(spammy, ", ") + ")"
+	}
+	return single(b.Name(), score, detail), nil
+}
+
+// Train records a moderator decision
+func (b *BayesChecker) Train(content string, spam bool) error {
+	tokens := bayesTokens(content)
+	if len(tokens) == 0 {
+		return nil
+	}
+
+	b.mu.Lock()
+	for _, t := range tokens {
+		c := b.tokens[t]
+		if spam {
+			c.spam++
+		} else {
+			c.ham++
+		}
+		b.tokens[t] = c
+	}
+	if spam {
+		b.spamDocs++
+	} else {
+		b.hamDocs++
+	}
+	b.mu.Unlock()
+
+	return b.repo.Increment(append(tokens, docsToken), spam)
+}
@@ -0,0 +1,71 @@
+package spam
+
+import (
+	"backend/internal/domain"
+	"backend/internal/search"
+	"fmt"
+	"strings"
+)
+
+// BlocklistChecker matches content against word and domain lists managed in
+// system settings: spam_blocked_words and spam_blocked_domains (JSON arrays or
+// comma separated strings). Words are matched diacritic-insensitively on word
+// boundaries; domains also match their subdomains.
+type BlocklistChecker struct {
+	settingServ domain.SettingService
+}
+
+func NewBlocklistChecker(settingServ domain.SettingService) *BlocklistChecker {
+	return &BlocklistChecker{settingServ: settingServ}
+}
+
+func (c *BlocklistChecker) Name() string {
+	return "blocklist"
+}
+
+func (c *BlocklistChecker) Check(input domain.SpamCheckInput) (domain.SpamVerdict, error) {
+	settings, err := c.settingServ.GetSettings()
+	if err != nil {
+		return domain.SpamVerdict{}, err
+	}
+
+	verdict := domain.SpamVerdict{}
+
+	if domains := domain.SettingStrings(settings, "spam_blocked_domains"); len(domains) > 0 {
+		for _, link := range extractLinks(input.Content) {
+			host := linkHost(link)
+			for _, d := range domains {
+				d = strings.TrimPrefix(strings.ToLower(d), "www.")
+				if host != "" && (host == d || strings.HasSuffix(host, "."+d)) {
+					verdict.Reasons = append(verdict.Reasons, domain.SpamReason{
+						Check: "blocked_domain", Score: 1, Detail: fmt.Sprintf("Tên miền bị chặn: %s", host),
+					})
+					verdict.Score = 1
+					break
+				}
+			}
+		}
+	}
+
+	if words := domain.SettingStrings(settings, "spam_blocked_words"); len(words) > 0 {
+		text := " " + strings.Join(search.Analyze(input.Content), " ") + " "
+		var hits []string
+		for _, w := range words {
+			folded := strings.Join(search.Analyze(w), " ")
+			if folded != "" && strings.Contains(text, " "+folded+" ") {
+				hits = append(hits, w)
+			}
+		}
+		if len(hits) > 0 {
+			score := clamp(0.5 + 0.2*float64(len(hits)-1))
+			verdict.Reasons = append(verdict.Reasons, domain.SpamReason{
+				Check: "blocked_word", Score: score, Detail: fmt.Sprintf("Từ bị chặn: %s", strings.Join(hits, ", ")),
+			})
+			if score > verdict.Score {
+				verdict.Score = score
+			}
+		}
+	}
+
+	return verdict, nil
+}
@@ -0,0 +1,86 @@
+// Package spam scores comment content for the moderation queue. Checkers are
+// combined by Composite; none of them rejects a comment on its own.
+package spam
+
+import (
+	"backend/internal/domain"
+	"log"
+	"math"
+	"sort"
+)
+
+// Weighted pairs a checker with a multiplier applied to its score
+type Weighted struct {
+	Checker domain.SpamChecker
+	Weight  float64
+}
+
+// Composite runs every checker and combines their scores with a noisy-OR:
+// 1 - Π(1 - weight·score). A single confident signal is enough to flag a
+// comment, and several weak ones add up.
+type Composite struct {
+	checkers []Weighted
+}
+
+func NewComposite(checkers ...Weighted) *Composite {
+	return &Composite{checkers: checkers}
+}
+
+func (c *Composite) Name() string {
+	return "composite"
+}
+
+func (c *Composite) Check(input domain.SpamCheckInput) (domain.SpamVerdict, error) {
+	verdict := domain.SpamVerdict{Reasons: []domain.SpamReason{}}
+	notSpam := 1.0
+
+	for _, w := range c.checkers {
+		v, err := w.Checker.Check(input)
+		if err != nil {
+			// A failing checker must not block commenting
+			log.Printf("Spam checker %s failed: %v", w.Checker.Name(), err)
+			continue
+		}
+		score := clamp(v.Score * w.Weight)
+		if score <= 0 {
+			continue
+		}
+		notSpam *= 1 - score
+		verdict.Reasons = append(verdict.Reasons, v.Reasons...)
+	}
+
+	verdict.Score = math.Round((1-notSpam)*1000) / 1000
+	sort.SliceStable(verdict.Reasons, func(i, j int) bool {
+		return verdict.Reasons[i].Score > verdict.Reasons[j].Score
+	})
+	return verdict, nil
+}
+
+// Train forwards a moderator decision to every trainable checker
+func (c *Composite) Train(content string, spam bool) error {
+	var firstErr error
+	for _, w := range c.checkers {
+		if t, ok := w.Checker.(domain.SpamTrainer); ok {
+			if err := t.Train(content, spam); err != nil && firstErr == nil {
+				firstErr = err
+			}
+		}
+	}
+	return firstErr
+}
+
+func clamp(v float64) float64 {
+	return math.Max(0, math.Min(1, v))
+}
+
+// single builds a one-reason verdict, or an empty one when score is zero
+func single(check string, score float64, detail string) domain.SpamVerdict {
+	if score <= 0 {
+		return domain.SpamVerdict{}
+	}
+	score = clamp(score)
+	return domain.SpamVerdict{
+		Score:   score,
+		Reasons: []domain.SpamReason{{Check: check, Score: score, Detail: detail}},
+	}
+}
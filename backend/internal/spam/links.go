@@ -0,0 +1,64 @@
+package spam
+
+import (
+	"backend/internal/domain"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var linkPattern = regexp.MustCompile(`(?i)\b(?:https?://|www\.)[^\s<>"']+`)
+
+// extractLinks returns the URLs found in text
+func extractLinks(text string) []string {
+	return linkPattern.FindAllString(text, -1)
+}
+
+// linkHost returns the lower-cased host of a link found by extractLinks
+func linkHost(link string) string {
+	if !strings.Contains(link, "://") {
+		link = "http://" + link
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+}
+
+// LinkDensityChecker flags comments that are mostly links or carry many of them
+type LinkDensityChecker struct {
+	// MaxLinks is the number of links tolerated before the score saturates
+	MaxLinks int
+}
+
+func NewLinkDensityChecker() *LinkDensityChecker {
+	return &LinkDensityChecker{MaxLinks: 3}
+}
+
+func (c *LinkDensityChecker) Name() string {
+	return "link_density"
+}
+
+func (c *LinkDensityChecker) Check(input domain.SpamCheckInput) (domain.SpamVerdict, error) {
+	links := len(extractLinks(input.Content))
+	if links == 0 {
+		return domain.SpamVerdict{}, nil
+	}
+	words := len(strings.Fields(input.Content))
+	if words == 0 {
+		words = 1
+	}
+
+	density := float64(links) / float64(words)
+	// One link in a normal sentence is fine; a bare link or a list of links is not
+	score := density * 2
+	if links > c.MaxLinks {
+		score += 0.3 * float64(links-c.MaxLinks)
+	}
+	if links == 1 && words >= 8 {
+		score *= 0.5
+	}
+	return single(c.Name(), score, fmt.Sprintf("%d liên kết trong %d từ", links, words)), nil
+}
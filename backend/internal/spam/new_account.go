@@ -0,0 +1,71 @@
+package spam
+
+import (
+	"backend/internal/domain"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// trustedAfter is the number of approved comments after which an account is no longer "new"
+const trustedAfter = 3
+
+// NewAccountChecker raises the score of comments from young accounts without an
+// approved comment history, especially when they post links.
+type NewAccountChecker struct {
+	users domain.UserRepository
+	stats CommentStats
+}
+
+func NewNewAccountChecker(users domain.UserRepository, stats CommentStats) *NewAccountChecker {
+	return &NewAccountChecker{users: users, stats: stats}
+}
+
+func (c *NewAccountChecker) Name() string {
+	return "new_account"
+}
+
+func (c *NewAccountChecker) Check(input domain.SpamCheckInput) (domain.SpamVerdict, error) {
+	if input.UserID == uuid.Nil {
+		// Guests have no account or history at all
+		score, detail := 0.25, "Bình luận của khách không có tài khoản"
+		if len(extractLinks(input.Content)) > 0 {
+			score += 0.3
+			detail += " và có chứa liên kết"
+		}
+		return single(c.Name(), score, detail), nil
+	}
+
+	approved, err := c.stats.CountByUserAndStatus(input.UserID, domain.CommentApproved)
+	if err != nil || approved >= trustedAfter {
+		return domain.SpamVerdict{}, err
+	}
+
+	user, err := c.users.GetUserByID(input.UserID)
+	if err != nil {
+		return domain.SpamVerdict{}, err
+	}
+
+	age := time.Since(user.CreatedAt)
+	var score float64
+	var detail string
+	switch {
+	case age < time.Hour:
+		score, detail = 0.4, "Tài khoản tạo chưa đến 1 giờ"
+	case age < 24*time.Hour:
+		score, detail = 0.25, "Tài khoản tạo chưa đến 24 giờ"
+	case age < 7*24*time.Hour && approved == 0:
+		score, detail = 0.1, "Tài khoản mới chưa có bình luận được duyệt"
+	default:
+		return domain.SpamVerdict{}, nil
+	}
+
+	if len(extractLinks(input.Content)) > 0 {
+		score += 0.3
+		detail += " và có chứa liên kết"
+	}
+	if !user.EmailVerified {
+		score += 0.1
+	}
+	return single(c.Name(), score, detail), nil
+}
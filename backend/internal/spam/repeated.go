@@ -0,0 +1,47 @@
+package spam
+
+import (
+	"backend/internal/domain"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CommentStats is the slice of the comment repository the history-based checkers need
+type CommentStats interface {
+	CountDuplicateContent(content string, excludeUserID uuid.UUID, since time.Time) (int64, error)
+	CountByUserAndStatus(userID uuid.UUID, status domain.CommentStatus) (int64, error)
+}
+
+// minRepeatedLength skips short replies like "cảm ơn" that many users legitimately post
+const minRepeatedLength = 20
+
+// RepeatedContentChecker flags text that other accounts posted recently (copy-paste campaigns)
+type RepeatedContentChecker struct {
+	stats  CommentStats
+	Window time.Duration
+}
+
+func NewRepeatedContentChecker(stats CommentStats) *RepeatedContentChecker {
+	return &RepeatedContentChecker{stats: stats, Window: 24 * time.Hour}
+}
+
+func (c *RepeatedContentChecker) Name() string {
+	return "repeated_content"
+}
+
+func (c *RepeatedContentChecker) Check(input domain.SpamCheckInput) (domain.SpamVerdict, error) {
+	content := strings.TrimSpace(input.Content)
+	if len([]rune(content)) < minRepeatedLength {
+		return domain.SpamVerdict{}, nil
+	}
+
+	n, err := c.stats.CountDuplicateContent(content, input.UserID, time.Now().UTC().Add(-c.Window))
+	if err != nil || n == 0 {
+		return domain.SpamVerdict{}, err
+	}
+	score := 0.5 + 0.15*float64(n-1)
+	return single(c.Name(), score, fmt.Sprintf("Nội dung trùng với %d bình luận của người khác trong 24 giờ", n)), nil
+}
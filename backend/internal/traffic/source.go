@@ -0,0 +1,244 @@
+// Package traffic attributes article views to a channel, campaign and device.
+package traffic
+
+import (
+	"backend/internal/domain"
+	"log"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// settingsRefresh is how long view_internal_hosts is cached
+const settingsRefresh = time.Minute
+
+const maxTagLength = 100
+
+// Referrer hosts are matched by suffix after stripping "www.", so "google.com"
+// also covers "news.google.com". Email hosts are checked before search ones.
+var (
+	searchHosts = []string{
+		"google.com", "google.com.vn", "bing.com", "yahoo.com", "duckduckgo.com",
+		"yandex.ru", "yandex.com", "baidu.com", "coccoc.com", "ecosia.org", "naver.com", "search.brave.com",
+		"qwant.com", "startpage.com",
+	}
+	socialHosts = []string{
+		"facebook.com", "fb.com", "fb.me", "messenger.com", "instagram.com", "threads.net", "t.co",
+		"twitter.com", "x.com", "linkedin.com", "lnkd.in", "reddit.com", "pinterest.com", "tiktok.com",
+		"youtube.com", "youtu.be", "zalo.me", "telegram.org", "t.me", "discord.com",
+		"news.ycombinator.com", "quora.com", "medium.com",
+	}
+	emailHosts = []string{
+		"mail.google.com", "outlook.live.com", "outlook.office.com", "outlook.office365.com",
+		"mail.yahoo.com", "mail.zoho.com",
+	}
+	// appReferrers map android-app:// referrers to a channel by package name
+	appReferrers = map[string]domain.TrafficChannel{
+		"com.google.android.gm":                   domain.ChannelEmail,
+		"com.microsoft.office.outlook":            domain.ChannelEmail,
+		"com.google.android.googlequicksearchbox": domain.ChannelSearch,
+		"com.facebook.katana":                     domain.ChannelSocial,
+		"com.facebook.orca":                       domain.ChannelSocial,
+		"com.zing.zalo":                           domain.ChannelSocial,
+		"com.twitter.android":                     domain.ChannelSocial,
+		"com.linkedin.android":                    domain.ChannelSocial,
+		"org.telegram.messenger":                  domain.ChannelSocial,
+	}
+
+	paidMediums   = []string{"cpc", "ppc", "paid", "cpm", "cpv", "display", "banner", "ads", "paidsearch", "paid_social", "paidsocial", "sponsored"}
+	emailMediums  = []string{"email", "e-mail", "e_mail", "newsletter", "mail"}
+	socialMediums = []string{"social", "social-network", "social_network", "social-media", "sm", "social_media"}
+	searchMediums = []string{"organic", "seo"}
+)
+
+// Resolver implements domain.ViewSourceResolver. Channels are decided in order: a
+// paid, email, social or search utm_medium; a known utm_source; the referrer host
+// (internal, search, social, email or any other site); any remaining UTM tag as a
+// campaign; and otherwise direct traffic.
+type Resolver struct {
+	settingServ domain.SettingService
+
+	mu            sync.RWMutex
+	internalHosts []string
+	loadedAt      time.Time
+}
+
+func NewResolver(settingServ domain.SettingService) *Resolver {
+	return &Resolver{settingServ: settingServ}
+}
+
+func (r *Resolver) Resolve(visit domain.ViewVisit) domain.ViewSource {
+	device := ParseUserAgent(visit.UserAgent)
+	source := domain.ViewSource{
+		UTMSource:   cleanTag(visit.UTMSource),
+		UTMMedium:   cleanTag(visit.UTMMedium),
+		UTMCampaign: cleanTag(visit.UTMCampaign),
+		DeviceClass: device.Class,
+		Browser:     device.Browser,
+		OS:          device.OS,
+	}
+
+	host, appChannel := referrerHost(visit.Referrer)
+	source.ReferrerHost = host
+
+	switch {
+	case matchesAny(source.UTMMedium, paidMediums):
+		source.Channel = domain.ChannelPaid
+	case matchesAny(source.UTMMedium, emailMediums):
+		source.Channel = domain.ChannelEmail
+	case matchesAny(source.UTMMedium, socialMediums):
+		source.Channel = domain.ChannelSocial
+	case matchesAny(source.UTMMedium, searchMediums):
+		source.Channel = domain.ChannelSearch
+	case source.UTMSource != "" && hostChannel(source.UTMSource) != "":
+		source.Channel = hostChannel(source.UTMSource)
+	case appChannel != "":
+		source.Channel = appChannel
+	case host != "" && r.isInternal(host, visit.SiteHost):
+		source.Channel = domain.ChannelInternal
+	case host != "" && hostChannel(host) != "":
+		source.Channel = hostChannel(host)
+	case source.UTMSource != "" || source.UTMMedium != "" || source.UTMCampaign != "":
+		source.Channel = domain.ChannelCampaign
+	case host != "":
+		source.Channel = domain.ChannelReferral
+	default:
+		source.Channel = domain.ChannelDirect
+	}
+	return source
+}
+
+// InternalHosts returns the hosts treated as this site, from view_internal_hosts
+func (r *Resolver) InternalHosts() []string {
+	r.mu.RLock()
+	hosts, loadedAt := r.internalHosts, r.loadedAt
+	r.mu.RUnlock()
+	if !loadedAt.IsZero() && time.Since(loadedAt) < settingsRefresh {
+		return hosts
+	}
+
+	settings, err := r.settingServ.GetSettings()
+	if err != nil {
+		log.Printf("traffic: failed to load settings: %v", err)
+		settings = map[string]interface{}{}
+	}
+	hosts = nil
+	for _, h := range domain.SettingStrings(settings, "view_internal_hosts") {
+		hosts = append(hosts, normalizeHost(h))
+	}
+
+	r.mu.Lock()
+	r.internalHosts, r.loadedAt = hosts, time.Now()
+	r.mu.Unlock()
+	return hosts
+}
+
+func (r *Resolver) isInternal(host, siteHost string) bool {
+	if siteHost = normalizeHost(siteHost); siteHost != "" && host == siteHost {
+		return true
+	}
+	for _, h := range r.InternalHosts() {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+	}
+	return false
+}
+
+// referrerHost extracts the normalized host of a referrer URL, or the channel of
+// an Android app referrer
+func referrerHost(referrer string) (string, domain.TrafficChannel) {
+	referrer = strings.TrimSpace(referrer)
+	if referrer == "" {
+		return "", ""
+	}
+	u, err := url.Parse(referrer)
+	if err != nil {
+		return "", ""
+	}
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return normalizeHost(u.Host), ""
+	case "android-app":
+		pkg := strings.ToLower(u.Host)
+		return truncate(pkg, 255), appReferrers[pkg]
+	}
+	return "", ""
+}
+
+// hostChannel classifies a referrer host or a utm_source such as "facebook"
+func hostChannel(host string) domain.TrafficChannel {
+	host = strings.ToLower(host)
+	switch {
+	case matchesHost(host, emailHosts) || strings.HasPrefix(host, "webmail."):
+		return domain.ChannelEmail
+	case matchesHost(host, searchHosts):
+		return domain.ChannelSearch
+	case matchesHost(host, socialHosts):
+		return domain.ChannelSocial
+	}
+	return ""
+}
+
+// matchesHost accepts the host itself, its subdomains and, for bare utm_source
+// values like "google", the first label of a listed domain
+func matchesHost(host string, list []string) bool {
+	for _, h := range list {
+		if host == h || strings.HasSuffix(host, "."+h) {
+			return true
+		}
+		if !strings.Contains(host, ".") && strings.SplitN(h, ".", 2)[0] == host {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesAny(value string, list []string) bool {
+	for _, v := range list {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
+
+func normalizeHost(host string) string {
+	host = strings.ToLower(strings.TrimSpace(host))
+	if i := strings.Index(host, "://"); i >= 0 {
+		host = host[i+3:]
+	}
+	if i := strings.IndexAny(host, "/?#"); i >= 0 {
+		host = host[:i]
+	}
+	if h, _, ok := strings.Cut(host, ":"); ok && !strings.Contains(h, "[") {
+		host = h
+	}
+	host = strings.TrimSuffix(strings.TrimPrefix(host, "www."), ".")
+	return truncate(host, 255)
+}
+
+// cleanTag lowercases a UTM value and drops control characters
+func cleanTag(tag string) string {
+	tag = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, strings.TrimSpace(tag))
+	return truncate(tag, maxTagLength)
+}
+
+func truncate(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	// Cut on a rune boundary
+	for n > 0 && !utf8.RuneStart(s[n]) {
+		n--
+	}
+	return s[:n]
+}
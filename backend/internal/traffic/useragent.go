@@ -0,0 +1,71 @@
+package traffic
+
+import (
+	"backend/internal/domain"
+	"strings"
+)
+
+// Device is what ParseUserAgent extracts; versions are left out to keep the
+// breakdowns small
+type Device struct {
+	Class   domain.DeviceClass `json:"class"`
+	Browser string             `json:"browser"`
+	OS      string             `json:"os"`
+}
+
+// browserTokens are checked in order: in-app and Chromium-based browsers announce
+// "Chrome" and "Safari" too, so they come before those
+var browserTokens = []struct {
+	token, name string
+}{
+	{"fban", "Facebook"}, {"fbav", "Facebook"}, {"instagram", "Instagram"}, {"zalo", "Zalo"},
+	{"coc_coc_browser", "Cốc Cốc"}, {"edg/", "Edge"}, {"edga/", "Edge"}, {"edgios/", "Edge"}, {"edge/", "Edge"},
+	{"opr/", "Opera"}, {"opera", "Opera"}, {"samsungbrowser", "Samsung Internet"}, {"yabrowser", "Yandex"},
+	{"ucbrowser", "UC Browser"}, {"vivaldi", "Vivaldi"}, {"firefox/", "Firefox"}, {"fxios/", "Firefox"},
+	{"crios/", "Chrome"}, {"chromium/", "Chromium"}, {"chrome/", "Chrome"}, {"msie ", "Internet Explorer"},
+	{"trident/", "Internet Explorer"}, {"safari/", "Safari"},
+}
+
+var osTokens = []struct {
+	token, name string
+}{
+	{"windows phone", "Windows Phone"}, {"windows", "Windows"}, {"iphone", "iOS"}, {"ipad", "iOS"},
+	{"ipod", "iOS"}, {"android", "Android"}, {"cros", "ChromeOS"}, {"mac os x", "macOS"},
+	{"macintosh", "macOS"}, {"linux", "Linux"},
+}
+
+// ParseUserAgent classifies a user agent by keyword. iPadOS presents itself as
+// macOS, so those tablets are counted as desktops.
+func ParseUserAgent(userAgent string) Device {
+	ua := strings.ToLower(userAgent)
+	device := Device{Class: domain.DeviceOther, Browser: "Other", OS: "Other"}
+	if ua == "" {
+		return device
+	}
+
+	for _, b := range browserTokens {
+		if strings.Contains(ua, b.token) {
+			device.Browser = b.name
+			break
+		}
+	}
+	for _, o := range osTokens {
+		if strings.Contains(ua, o.token) {
+			device.OS = o.name
+			break
+		}
+	}
+
+	switch {
+	case strings.Contains(ua, "ipad") || strings.Contains(ua, "tablet") || strings.Contains(ua, "kindle") ||
+		strings.Contains(ua, "silk/") || strings.Contains(ua, "android") && !strings.Contains(ua, "mobile"):
+		device.Class = domain.DeviceTablet
+	case strings.Contains(ua, "mobi") || strings.Contains(ua, "iphone") || strings.Contains(ua, "ipod") ||
+		strings.Contains(ua, "android") || strings.Contains(ua, "windows phone") || strings.Contains(ua, "opera mini"):
+		device.Class = domain.DeviceMobile
+	case strings.Contains(ua, "windows nt") || strings.Contains(ua, "macintosh") || strings.Contains(ua, "x11") ||
+		strings.Contains(ua, "cros"):
+		device.Class = domain.DeviceDesktop
+	}
+	return device
+}
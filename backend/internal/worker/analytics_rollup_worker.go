@@ -0,0 +1,169 @@
+package worker
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"log"
+	"sync"
+	"time"
+)
+
+const (
+	analyticsRollupInterval = 5 * time.Minute
+	// analyticsRollupLookback re-aggregates the recent past on every run, covering views
+	// flushed late by the ingestion pipeline
+	analyticsRollupLookback = 2 * time.Hour
+	// analyticsRollupRefreshDays is re-aggregated once a day, picking up comments
+	// moderated and ratings voided after the fact
+	analyticsRollupRefreshDays = 7
+	viewPruneChunk             = 5000
+
+	rollupStateName   = "rollups"
+	rollupRefreshName = "rollups_refresh"
+)
+
+// AnalyticsRollupWorker keeps the hourly and daily analytics rollups up to date from
+// the raw views, comments and ratings, then prunes raw views and engagement sessions
+// past view_raw_retention_days. Progress is stored in rollup_states, so a restart resumes
+// where the last run stopped and an empty table is backfilled one day at a time.
+type AnalyticsRollupWorker struct {
+	repo           domain.AnalyticsRollupRepository
+	viewRepo       domain.ViewTrackingRepository
+	engagementRepo domain.EngagementRepository
+	settingServ    domain.SettingService
+
+	running sync.Mutex
+}
+
+func NewAnalyticsRollupWorker(repo domain.AnalyticsRollupRepository, viewRepo domain.ViewTrackingRepository, engagementRepo domain.EngagementRepository, settingServ domain.SettingService) *AnalyticsRollupWorker {
+	return &AnalyticsRollupWorker{repo: repo, viewRepo: viewRepo, engagementRepo: engagementRepo, settingServ: settingServ}
+}
+
+func (w *AnalyticsRollupWorker) Start() {
+	log.Println("Starting Analytics Rollup Worker...")
+
+	go func() {
+		for {
+			if _, err := w.RunOnce(); err != nil {
+				log.Printf("AnalyticsRollupWorker: %v", err)
+			}
+			time.Sleep(analyticsRollupInterval)
+		}
+	}()
+}
+
+// RunOnce brings the rollups up to the current hour. A run already in progress is
+// reported as a conflict.
+func (w *AnalyticsRollupWorker) RunOnce() (*domain.RollupState, error) {
+	if !w.running.TryLock() {
+		return nil, apperrors.NewConflict("Đang có tiến trình tổng hợp số liệu chạy", nil)
+	}
+	defer w.running.Unlock()
+
+	now := time.Now().UTC()
+	// The current hour is included so dashboards lag by one run at most
+	end := now.Truncate(time.Hour).Add(time.Hour)
+
+	state, err := w.repo.GetState(rollupStateName)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	var start time.Time
+	if state != nil {
+		start = state.RolledUntil.Add(-analyticsRollupLookback)
+		if end.Sub(start) < analyticsRollupLookback {
+			start = end.Add(-analyticsRollupLookback)
+		}
+	} else {
+		earliest, err := w.repo.EarliestEvent()
+		if err != nil {
+			return nil, apperrors.NewInternalError(err)
+		}
+		if earliest == nil {
+			state = &domain.RollupState{Name: rollupStateName, RolledUntil: end}
+			return state, w.repo.SaveState(state)
+		}
+		start = earliest.UTC()
+		state = &domain.RollupState{Name: rollupStateName}
+	}
+
+	refresh, err := w.repo.GetState(rollupRefreshName)
+	if err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	refreshing := refresh == nil || domain.VisitorDay(refresh.RolledUntil).Before(domain.VisitorDay(now))
+	if weekAgo := domain.VisitorDay(now).AddDate(0, 0, -analyticsRollupRefreshDays); refreshing && weekAgo.Before(start) {
+		start = weekAgo
+	}
+
+	if err := w.rollup(state, start.Truncate(time.Hour), end); err != nil {
+		return nil, apperrors.NewInternalError(err)
+	}
+	if refreshing {
+		if err := w.repo.SaveState(&domain.RollupState{Name: rollupRefreshName, RolledUntil: now}); err != nil {
+			log.Printf("AnalyticsRollupWorker: failed to save refresh state: %v", err)
+		}
+	}
+	w.prune(now, state.RolledUntil)
+	return state, nil
+}
+
+// rollup aggregates [start, end) day by day, saving progress after each day
+func (w *AnalyticsRollupWorker) rollup(state *domain.RollupState, start, end time.Time) error {
+	for day := domain.VisitorDay(start); day.Before(end); day = day.AddDate(0, 0, 1) {
+		from, to := day, day.AddDate(0, 0, 1)
+		if from.Before(start) {
+			from = start
+		}
+		if to.After(end) {
+			to = end
+		}
+		if err := w.repo.Rebuild(domain.RollupHour, from, to); err != nil {
+			return err
+		}
+		if err := w.repo.Rebuild(domain.RollupDay, day, day.AddDate(0, 0, 1)); err != nil {
+			return err
+		}
+		if to.After(state.RolledUntil) {
+			state.RolledUntil = to
+			if err := w.repo.SaveState(state); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// prune deletes raw views and engagement sessions older than view_raw_retention_days,
+// never reaching into days the rollups may still re-aggregate
+func (w *AnalyticsRollupWorker) prune(now, rolledUntil time.Time) {
+	settings, err := w.settingServ.GetSettings()
+	if err != nil {
+		log.Printf("AnalyticsRollupWorker: Failed to fetch settings: %v", err)
+		return
+	}
+	retentionDays := domain.SettingInt(settings, "view_raw_retention_days", domain.DefaultViewRawRetentionDays)
+	if retentionDays <= 0 {
+		return
+	}
+
+	cutoff := domain.VisitorDay(now).AddDate(0, 0, -retentionDays)
+	if limit := domain.VisitorDay(rolledUntil).AddDate(0, 0, -analyticsRollupRefreshDays-1); limit.Before(cutoff) {
+		cutoff = limit
+	}
+	deleted, err := w.viewRepo.DeleteBefore(cutoff, viewPruneChunk)
+	if err != nil {
+		log.Printf("AnalyticsRollupWorker: failed to prune raw views: %v", err)
+	}
+	if deleted > 0 {
+		log.Printf("AnalyticsRollupWorker: pruned %d raw views older than %d days", deleted, retentionDays)
+	}
+
+	deleted, err = w.engagementRepo.DeleteBefore(cutoff, viewPruneChunk)
+	if err != nil {
+		log.Printf("AnalyticsRollupWorker: failed to prune engagement sessions: %v", err)
+	}
+	if deleted > 0 {
+		log.Printf("AnalyticsRollupWorker: pruned %d engagement sessions older than %d days", deleted, retentionDays)
+	}
+}
@@ -0,0 +1,170 @@
+package worker
+
+import (
+	"backend/internal/domain"
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	engagementFlushInterval = 10 * time.Second
+	// engagementMaxPending bounds the sessions held between flushes
+	engagementMaxPending = 100000
+	// A flush that fails is retried with a growing pause before it is dropped
+	engagementFlushAttempts = 3
+	engagementFlushBackoff  = 500 * time.Millisecond
+)
+
+// EngagementBuffer coalesces engagement pings in memory: a page pings every few
+// seconds, but only the latest state of each reading session is written, in one
+// batched upsert per flush.
+type EngagementBuffer struct {
+	repo        domain.EngagementRepository
+	articleRepo domain.ArticleRepository
+
+	mu      sync.Mutex
+	pending map[domain.EngagementKey]domain.ArticleEngagement
+	dropped int64
+
+	// failed counts sessions lost to failed writes, orphaned those of articles
+	// deleted before they were written
+	failed, orphaned atomic.Int64
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+func NewEngagementBuffer(repo domain.EngagementRepository, articleRepo domain.ArticleRepository) *EngagementBuffer {
+	return &EngagementBuffer{
+		repo:        repo,
+		articleRepo: articleRepo,
+		pending:     make(map[domain.EngagementKey]domain.ArticleEngagement),
+		stop:        make(chan struct{}),
+		done:        make(chan struct{}),
+	}
+}
+
+func (b *EngagementBuffer) Start() {
+	log.Println("Starting Engagement Buffer...")
+
+	go func() {
+		defer close(b.done)
+		ticker := time.NewTicker(engagementFlushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				b.flush()
+			case <-b.stop:
+				b.flush()
+				return
+			}
+		}
+	}()
+}
+
+// Record implements domain.EngagementRecorder
+func (b *EngagementBuffer) Record(e domain.ArticleEngagement) bool {
+	key := domain.EngagementKey{ArticleID: e.ArticleID, SessionID: e.SessionID}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if existing, ok := b.pending[key]; ok {
+		// A reused session ID from another visitor is ignored, as in the database
+		if existing.VisitorID != e.VisitorID {
+			return true
+		}
+		existing.Merge(e)
+		b.pending[key] = existing
+		return true
+	}
+	if len(b.pending) >= engagementMaxPending {
+		b.dropped++
+		return false
+	}
+	b.pending[key] = e
+	return true
+}
+
+// Stop writes what is pending and stops flushing, or gives up when ctx is done
+func (b *EngagementBuffer) Stop(ctx context.Context) error {
+	b.once.Do(func() { close(b.stop) })
+	select {
+	case <-b.done:
+		log.Println("EngagementBuffer: flushed and stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *EngagementBuffer) flush() {
+	b.mu.Lock()
+	if len(b.pending) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	batch := make([]domain.ArticleEngagement, 0, len(b.pending))
+	for _, e := range b.pending {
+		batch = append(batch, e)
+	}
+	b.pending = make(map[domain.EngagementKey]domain.ArticleEngagement)
+	dropped := b.dropped
+	b.dropped = 0
+	b.mu.Unlock()
+
+	if dropped > 0 {
+		log.Printf("EngagementBuffer: dropped %d sessions while the buffer was full", dropped)
+	}
+
+	var err error
+	for attempt := 1; attempt <= engagementFlushAttempts; attempt++ {
+		if err = b.write(batch); err == nil {
+			return
+		}
+		if attempt < engagementFlushAttempts {
+			log.Printf("EngagementBuffer: writing %d sessions failed (attempt %d), retrying: %v", len(batch), attempt, err)
+			time.Sleep(time.Duration(attempt) * engagementFlushBackoff)
+		}
+	}
+	failed := b.failed.Add(int64(len(batch)))
+	log.Printf("EngagementBuffer: dropped %d sessions after %d attempts (%d in total): %v", len(batch), engagementFlushAttempts, failed, err)
+}
+
+// write leaves out sessions of articles deleted since they were tracked, which would
+// otherwise fail the whole batch on the foreign key, and upserts the rest
+func (b *EngagementBuffer) write(batch []domain.ArticleEngagement) error {
+	articleIDs := make([]uuid.UUID, 0, len(batch))
+	seen := make(map[uuid.UUID]bool)
+	for _, e := range batch {
+		if !seen[e.ArticleID] {
+			seen[e.ArticleID] = true
+			articleIDs = append(articleIDs, e.ArticleID)
+		}
+	}
+	articles, err := b.articleRepo.ExistingIDs(articleIDs)
+	if err != nil {
+		return err
+	}
+
+	sessions := make([]domain.ArticleEngagement, 0, len(batch))
+	for _, e := range batch {
+		if articles[e.ArticleID] {
+			sessions = append(sessions, e)
+		}
+	}
+	if err := b.repo.UpsertBatch(sessions); err != nil {
+		return err
+	}
+	if orphaned := int64(len(batch) - len(sessions)); orphaned > 0 {
+		total := b.orphaned.Add(orphaned)
+		log.Printf("EngagementBuffer: skipped %d sessions of deleted articles (%d in total)", orphaned, total)
+	}
+	return nil
+}
@@ -3,7 +3,6 @@ package worker
 import (
 	"backend/internal/domain"
 	"log"
-	"strconv"
 	"time"
 )
 
@@ -41,19 +40,7 @@ func (w *LogWorker) runCleanup() {
 		return
 	}
 
-	retentionDays := 30 // Default
-	if val, ok := settings["log_retention_days"]; ok {
-		switch v := val.(type) {
-		case float64:
-			retentionDays = int(v)
-		case string:
-			if i, err := strconv.Atoi(v); err == nil {
-				retentionDays = i
-			}
-		case int:
-			retentionDays = v
-		}
-	}
+	retentionDays := domain.SettingInt(settings, "log_retention_days", 30)
 
 	log.Printf("LogWorker: Running cleanup with retention: %d days", retentionDays)
 	if err := w.auditServ.CleanupOldLogs(retentionDays); err != nil {
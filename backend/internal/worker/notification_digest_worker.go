@@ -0,0 +1,116 @@
+package worker
+
+import (
+	"backend/internal/domain"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	notificationDigestPeriod  = 24 * time.Hour
+	notificationDigestCheck   = time.Hour
+	maxNotificationsPerDigest = 50
+)
+
+// NotificationDigestWorker emails users who opted into the daily digest a summary
+// of their unread comment notifications that no earlier digest included.
+type NotificationDigestWorker struct {
+	repo   domain.NotificationRepository
+	mailer domain.Mailer
+
+	running sync.Mutex
+}
+
+func NewNotificationDigestWorker(repo domain.NotificationRepository, mailer domain.Mailer) *NotificationDigestWorker {
+	return &NotificationDigestWorker{repo: repo, mailer: mailer}
+}
+
+func (w *NotificationDigestWorker) Start() {
+	log.Println("Starting Notification Digest Worker...")
+
+	go func() {
+		for {
+			time.Sleep(notificationDigestCheck)
+			w.RunOnce()
+		}
+	}()
+}
+
+// RunOnce sends every digest that is due. Concurrent calls are skipped rather than queued.
+func (w *NotificationDigestWorker) RunOnce() {
+	if !w.running.TryLock() {
+		return
+	}
+	defer w.running.Unlock()
+
+	now := time.Now().UTC()
+	prefs, err := w.repo.ListDigestDue(now.Add(-notificationDigestPeriod))
+	if err != nil {
+		log.Printf("NotificationDigestWorker: failed to list subscribers: %v", err)
+		return
+	}
+
+	sent := 0
+	for _, pref := range prefs {
+		if pref.User == nil || !pref.User.IsActive || pref.User.Email == "" {
+			continue
+		}
+		notifications, err := w.repo.GetUnemailed(pref.UserID, maxNotificationsPerDigest)
+		if err != nil {
+			log.Printf("NotificationDigestWorker: failed to load notifications for %s: %v", pref.UserID, err)
+			continue
+		}
+		if len(notifications) > 0 {
+			subject, body := digestEmail(pref.User, notifications)
+			if err := w.mailer.Send(pref.User.Email, subject, body); err != nil {
+				// Not marked, so the same notifications are retried next hour
+				log.Printf("NotificationDigestWorker: %v", err)
+				continue
+			}
+			ids := make([]uuid.UUID, len(notifications))
+			for i, n := range notifications {
+				ids[i] = n.ID
+			}
+			if err := w.repo.MarkEmailed(ids, now); err != nil {
+				log.Printf("NotificationDigestWorker: failed to mark notifications emailed for %s: %v", pref.UserID, err)
+			}
+			sent++
+		}
+		// Users with nothing new also restart their 24h period
+		if err := w.repo.MarkDigestSent(pref.UserID, now); err != nil {
+			log.Printf("NotificationDigestWorker: failed to update digest time for %s: %v", pref.UserID, err)
+		}
+	}
+
+	if sent > 0 {
+		log.Printf("NotificationDigestWorker: %d digests sent", sent)
+	}
+}
+
+func digestEmail(user *domain.User, notifications []domain.Notification) (string, string) {
+	subject := fmt.Sprintf("Bạn có %d thông báo mới về bình luận", len(notifications))
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "Xin chào %s,\n\n", user.FullName)
+	for _, n := range notifications {
+		actor, title := "Một người dùng", "một bài viết"
+		if n.Actor != nil && n.Actor.FullName != "" {
+			actor = n.Actor.FullName
+		}
+		if n.Article != nil {
+			title = "\"" + n.Article.Title + "\""
+		}
+		action := "đã trả lời bình luận của bạn trong"
+		if n.Type == domain.NotificationMention {
+			action = "đã nhắc đến bạn trong"
+		}
+		fmt.Fprintf(&b, "- %s %s %s: %s\n", actor, action, title, n.Excerpt)
+	}
+	b.WriteString("\nBạn có thể tắt email tổng hợp trong phần cài đặt thông báo.\n")
+	return subject, b.String()
+}
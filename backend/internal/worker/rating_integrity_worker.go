@@ -0,0 +1,83 @@
+package worker
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"backend/internal/ws"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const ratingIntegrityInterval = 6 * time.Hour
+
+// RatingIntegrityWorker periodically reconciles the cached rating aggregates on
+// articles and scans for suspicious rating bursts, keeping the last report for admins.
+type RatingIntegrityWorker struct {
+	service   domain.RatingService
+	auditServ domain.AuditService
+	hub       *ws.Hub
+
+	running sync.Mutex
+	mu      sync.Mutex
+	report  *domain.RatingIntegrityReport
+}
+
+func NewRatingIntegrityWorker(service domain.RatingService, auditServ domain.AuditService, hub *ws.Hub) *RatingIntegrityWorker {
+	return &RatingIntegrityWorker{service: service, auditServ: auditServ, hub: hub}
+}
+
+func (w *RatingIntegrityWorker) Start() {
+	log.Println("Starting Rating Integrity Worker...")
+
+	go func() {
+		for {
+			time.Sleep(ratingIntegrityInterval)
+			if _, err := w.RunOnce(); err != nil {
+				log.Printf("RatingIntegrityWorker: %v", err)
+			}
+		}
+	}()
+}
+
+// RunOnce reconciles and scans now. A run already in progress is reported as a conflict.
+func (w *RatingIntegrityWorker) RunOnce() (*domain.RatingIntegrityReport, error) {
+	if !w.running.TryLock() {
+		return nil, apperrors.NewConflict("Đang có tiến trình kiểm tra đánh giá chạy", nil)
+	}
+	defer w.running.Unlock()
+
+	started := time.Now().UTC()
+	report, err := w.service.CheckIntegrity()
+	if err != nil {
+		w.setReport(&domain.RatingIntegrityReport{StartedAt: started, FinishedAt: time.Now().UTC(), Error: err.Error()})
+		return nil, apperrors.NewInternalError(err)
+	}
+	w.setReport(report)
+
+	for _, d := range report.Drifts {
+		log.Printf("RatingIntegrityWorker: fixed drift on article %s: avg %.4f -> %.4f, count %d -> %d",
+			d.ArticleID, d.StoredAvg, d.ActualAvg, d.StoredCount, d.ActualCount)
+	}
+	if len(report.Drifts) > 0 || len(report.Anomalies) > 0 {
+		_ = w.auditServ.LogSystemEvent(nil, "RATING_INTEGRITY", "article_ratings", uuid.Nil, nil,
+			map[string]interface{}{"drifts": report.Drifts, "anomalies": len(report.Anomalies)})
+		w.hub.BroadcastEvent("admin_data_updated", map[string]interface{}{"module": "ratings", "action": "integrity"})
+	}
+	return report, nil
+}
+
+// Report returns the last run's report, nil before the first run
+func (w *RatingIntegrityWorker) Report() *domain.RatingIntegrityReport {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.report
+}
+
+func (w *RatingIntegrityWorker) setReport(report *domain.RatingIntegrityReport) {
+	w.mu.Lock()
+	w.report = report
+	w.mu.Unlock()
+}
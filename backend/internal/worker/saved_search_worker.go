@@ -0,0 +1,262 @@
+package worker
+
+import (
+	"backend/internal/domain"
+	"backend/internal/ws"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	savedSearchPageSize = 50
+	// Cap per search and run so a very broad query can't flood a user
+	maxSavedSearchMatchesPerRun = 200
+	// Re-check a short window before the last run so articles whose publish
+	// commit raced the previous run are not missed; AddMatches dedups them.
+	savedSearchOverlap      = 10 * time.Minute
+	savedSearchDigestPeriod = 24 * time.Hour
+)
+
+// savedSearchArticle is the compact article shape pushed in alerts
+type savedSearchArticle struct {
+	ID          uuid.UUID  `json:"id"`
+	Title       string     `json:"title"`
+	Slug        string     `json:"slug"`
+	Summary     string     `json:"summary"`
+	ImageURL    string     `json:"image_url"`
+	PublishedAt *time.Time `json:"published_at"`
+}
+
+type savedSearchAlert struct {
+	SavedSearchID uuid.UUID            `json:"saved_search_id"`
+	Name          string               `json:"name"`
+	Query         string               `json:"query"`
+	Articles      []savedSearchArticle `json:"articles"`
+}
+
+// SavedSearchWorker re-runs users' saved searches against newly published
+// articles. INSTANT searches are pushed as "saved_search_match" events; DAILY
+// searches are bundled into one "saved_search_digest" event per user per day.
+// Matches stay undelivered until a socket of the user accepted them, so instant
+// matches found while the user was offline go out with the next digest.
+// Matching goes through ArticleService.SearchArticles so alerts agree with
+// what the user sees on the search page.
+type SavedSearchWorker struct {
+	repo        domain.SavedSearchRepository
+	articleServ domain.ArticleService
+	settingServ domain.SettingService
+	hub         *ws.Hub
+
+	running sync.Mutex
+}
+
+func NewSavedSearchWorker(repo domain.SavedSearchRepository, articleServ domain.ArticleService, settingServ domain.SettingService, hub *ws.Hub) *SavedSearchWorker {
+	return &SavedSearchWorker{
+		repo:        repo,
+		articleServ: articleServ,
+		settingServ: settingServ,
+		hub:         hub,
+	}
+}
+
+func (w *SavedSearchWorker) Start() {
+	log.Println("Starting Saved Search Worker...")
+
+	go func() {
+		for {
+			time.Sleep(w.interval())
+			w.RunOnce()
+		}
+	}()
+}
+
+// interval reads saved_search_interval_minutes (default 15) on every cycle so changes apply without a restart
+func (w *SavedSearchWorker) interval() time.Duration {
+	minutes := 15
+	if settings, err := w.settingServ.GetSettings(); err == nil {
+		minutes = domain.SettingInt(settings, "saved_search_interval_minutes", minutes)
+	}
+	if minutes < 1 {
+		minutes = 1
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+// RunOnce processes every alerting saved search and sends any due digests.
+// Concurrent calls are skipped rather than queued.
+func (w *SavedSearchWorker) RunOnce() {
+	if !w.running.TryLock() {
+		return
+	}
+	defer w.running.Unlock()
+
+	searches, err := w.repo.ListAlerting()
+	if err != nil {
+		log.Printf("SavedSearchWorker: failed to list saved searches: %v", err)
+		return
+	}
+
+	var newMatches int
+	for i := range searches {
+		newMatches += w.runSearch(&searches[i])
+	}
+	digests := w.sendDigests(searches)
+
+	if newMatches > 0 || digests > 0 {
+		log.Printf("SavedSearchWorker: %d searches, %d new matches, %d digests sent", len(searches), newMatches, digests)
+	}
+}
+
+// runSearch records new matches for one saved search and pushes them if it is INSTANT
+func (w *SavedSearchWorker) runSearch(s *domain.SavedSearch) int {
+	startedAt := time.Now().UTC()
+
+	since := s.CreatedAt
+	if s.LastRunAt != nil {
+		since = *s.LastRunAt
+	}
+	filters := s.Filters()
+	filters["published_after"] = since.Add(-savedSearchOverlap)
+
+	found := make(map[uuid.UUID]domain.Article)
+	var ids []uuid.UUID
+	for page := 1; len(ids) < maxSavedSearchMatchesPerRun; page++ {
+		articles, total, err := w.articleServ.SearchArticles(s.Query, page, savedSearchPageSize, filters)
+		if err != nil {
+			// LastRunAt is not advanced, so the window is retried next cycle
+			log.Printf("SavedSearchWorker: search %s failed: %v", s.ID, err)
+			return 0
+		}
+		for _, a := range articles {
+			if _, ok := found[a.ID]; !ok {
+				found[a.ID] = a
+				ids = append(ids, a.ID)
+			}
+		}
+		if len(articles) == 0 || int64(page*savedSearchPageSize) >= total {
+			break
+		}
+	}
+
+	matches, err := w.repo.AddMatches(s.ID, ids)
+	if err != nil {
+		log.Printf("SavedSearchWorker: failed to record matches for %s: %v", s.ID, err)
+		return 0
+	}
+	if err := w.repo.MarkRun(s.ID, startedAt); err != nil {
+		log.Printf("SavedSearchWorker: failed to update last run for %s: %v", s.ID, err)
+	}
+
+	if s.Frequency == domain.SavedSearchInstant && len(matches) > 0 {
+		alert := savedSearchAlert{SavedSearchID: s.ID, Name: s.Name, Query: s.Query}
+		matchIDs := make([]uuid.UUID, 0, len(matches))
+		for _, m := range matches {
+			alert.Articles = append(alert.Articles, toSavedSearchArticle(found[m.ArticleID]))
+			matchIDs = append(matchIDs, m.ID)
+		}
+		if w.hub.SendToUser(s.UserID, "saved_search_match", alert) {
+			if err := w.repo.MarkDelivered(matchIDs, time.Now().UTC()); err != nil {
+				log.Printf("SavedSearchWorker: failed to mark matches delivered for %s: %v", s.ID, err)
+			}
+		}
+	}
+
+	return len(matches)
+}
+
+// sendDigests bundles the undelivered matches of due searches into one event per
+// user: those of DAILY searches and instant ones the user was offline for. A user
+// who is offline keeps their searches due, so the digest goes out once they connect.
+func (w *SavedSearchWorker) sendDigests(searches []domain.SavedSearch) int {
+	now := time.Now().UTC()
+
+	due := make(map[uuid.UUID]*domain.SavedSearch)
+	var dueIDs []uuid.UUID
+	for i := range searches {
+		s := &searches[i]
+		if s.LastDigestAt != nil && now.Sub(*s.LastDigestAt) < savedSearchDigestPeriod {
+			continue
+		}
+		due[s.ID] = s
+		dueIDs = append(dueIDs, s.ID)
+	}
+	if len(dueIDs) == 0 {
+		return 0
+	}
+
+	matches, err := w.repo.GetUndelivered(dueIDs)
+	if err != nil {
+		log.Printf("SavedSearchWorker: failed to load digest matches: %v", err)
+		return 0
+	}
+
+	// user -> search -> alert, keeping searches in a stable order
+	type userDigest struct {
+		alerts   []*savedSearchAlert
+		bySearch map[uuid.UUID]*savedSearchAlert
+		matchIDs []uuid.UUID
+		total    int
+	}
+	delivered := make(map[uuid.UUID]bool)
+	digests := make(map[uuid.UUID]*userDigest)
+	for _, m := range matches {
+		s := due[m.SavedSearchID]
+		if s == nil || m.Article == nil {
+			continue
+		}
+		d := digests[s.UserID]
+		if d == nil {
+			d = &userDigest{bySearch: make(map[uuid.UUID]*savedSearchAlert)}
+			digests[s.UserID] = d
+		}
+		alert := d.bySearch[s.ID]
+		if alert == nil {
+			alert = &savedSearchAlert{SavedSearchID: s.ID, Name: s.Name, Query: s.Query}
+			d.bySearch[s.ID] = alert
+			d.alerts = append(d.alerts, alert)
+		}
+		alert.Articles = append(alert.Articles, toSavedSearchArticle(*m.Article))
+		d.matchIDs = append(d.matchIDs, m.ID)
+		d.total++
+	}
+
+	for userID, d := range digests {
+		if !w.hub.SendToUser(userID, "saved_search_digest", map[string]interface{}{
+			"searches": d.alerts,
+			"total":    d.total,
+		}) {
+			continue
+		}
+		delivered[userID] = true
+		if err := w.repo.MarkDelivered(d.matchIDs, now); err != nil {
+			log.Printf("SavedSearchWorker: failed to mark digest delivered for user %s: %v", userID, err)
+		}
+	}
+
+	// Searches with nothing new also restart their 24h period
+	var digested []uuid.UUID
+	for _, id := range dueIDs {
+		userID := due[id].UserID
+		if digests[userID] == nil || delivered[userID] {
+			digested = append(digested, id)
+		}
+	}
+	if err := w.repo.MarkDigested(digested, now); err != nil {
+		log.Printf("SavedSearchWorker: failed to update digest time: %v", err)
+	}
+	return len(delivered)
+}
+
+func toSavedSearchArticle(a domain.Article) savedSearchArticle {
+	return savedSearchArticle{
+		ID:          a.ID,
+		Title:       a.Title,
+		Slug:        a.Slug,
+		Summary:     a.Summary,
+		ImageURL:    a.ImageURL,
+		PublishedAt: a.PublishedAt,
+	}
+}
@@ -0,0 +1,145 @@
+package worker
+
+import (
+	apperrors "backend/internal/core/error"
+	"backend/internal/domain"
+	"backend/internal/ws"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const reindexBatchSize = 100
+
+// SearchReindexWorker rebuilds the search index from the articles table on demand
+// and streams progress to the requesting admin over WebSocket. The old index keeps
+// serving searches until the rebuild completes, and stays if it fails.
+type SearchReindexWorker struct {
+	articleRepo domain.ArticleRepository
+	index       domain.SearchIndex
+	hub         *ws.Hub
+
+	mu     sync.Mutex
+	status domain.SearchReindexStatus
+}
+
+func NewSearchReindexWorker(articleRepo domain.ArticleRepository, index domain.SearchIndex, hub *ws.Hub) *SearchReindexWorker {
+	return &SearchReindexWorker{
+		articleRepo: articleRepo,
+		index:       index,
+		hub:         hub,
+		status:      domain.SearchReindexStatus{Backend: index.Name()},
+	}
+}
+
+// Start launches a full reindex unless one is already running
+func (w *SearchReindexWorker) Start(requestedBy uuid.UUID) error {
+	w.mu.Lock()
+	if w.status.Running {
+		w.mu.Unlock()
+		return apperrors.NewConflict("Đang có tiến trình lập chỉ mục chạy", nil)
+	}
+	now := time.Now()
+	w.status = domain.SearchReindexStatus{
+		Backend:     w.index.Name(),
+		Running:     true,
+		StartedAt:   &now,
+		RequestedBy: requestedBy,
+	}
+	w.mu.Unlock()
+
+	go w.run()
+	return nil
+}
+
+// Status returns a snapshot of the current or last reindex run
+func (w *SearchReindexWorker) Status() domain.SearchReindexStatus {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.status
+}
+
+func (w *SearchReindexWorker) run() {
+	log.Printf("SearchReindexWorker: rebuilding %s index", w.index.Name())
+
+	build, err := w.index.BeginRebuild()
+	if err != nil {
+		w.finish(err)
+		return
+	}
+
+	// Keyset pages on id don't skip rows when articles are deleted mid-rebuild;
+	// articles written meanwhile reach the build through the live index
+	_, total, err := w.articleRepo.GetAll(0, 1, domain.ArticleQuery{Minimal: true})
+	if err != nil {
+		build.Discard()
+		w.finish(err)
+		return
+	}
+	w.mu.Lock()
+	w.status.Total = total
+	w.mu.Unlock()
+
+	after := uuid.Nil
+	for {
+		articles, err := w.articleRepo.GetAfterID(after, reindexBatchSize)
+		if err != nil {
+			build.Discard()
+			w.finish(err)
+			return
+		}
+
+		var failed int64
+		for i := range articles {
+			if err := build.IndexArticle(&articles[i]); err != nil {
+				log.Printf("SearchReindexWorker: failed to index %s: %v", articles[i].ID, err)
+				failed++
+			}
+		}
+
+		w.mu.Lock()
+		w.status.Processed += int64(len(articles))
+		w.status.Failed += failed
+		w.mu.Unlock()
+		w.notify("search_reindex_progress")
+
+		if len(articles) < reindexBatchSize {
+			break
+		}
+		after = articles[len(articles)-1].ID
+	}
+
+	if err := build.Commit(); err != nil {
+		build.Discard()
+		w.finish(err)
+		return
+	}
+	w.finish(w.index.Flush())
+}
+
+func (w *SearchReindexWorker) finish(err error) {
+	now := time.Now()
+	w.mu.Lock()
+	w.status.Running = false
+	w.status.FinishedAt = &now
+	if err != nil {
+		w.status.Error = err.Error()
+	}
+	w.mu.Unlock()
+
+	if err != nil {
+		log.Printf("SearchReindexWorker: reindex failed: %v", err)
+	} else {
+		log.Println("SearchReindexWorker: reindex completed successfully")
+	}
+	w.notify("search_reindex_finished")
+}
+
+func (w *SearchReindexWorker) notify(msgType string) {
+	status := w.Status()
+	if status.RequestedBy != uuid.Nil {
+		w.hub.SendToUser(status.RequestedBy, msgType, status)
+	}
+}
@@ -0,0 +1,367 @@
+package worker
+
+import (
+	"backend/internal/domain"
+	"context"
+	"log"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	viewBlockTimeout = 50 * time.Millisecond
+	// viewDedupMaxEntries bounds the in-memory dedup set; past it the batch check
+	// against article_views still catches repeats
+	viewDedupMaxEntries = 500000
+	viewDedupPrune      = 10 * time.Minute
+	// A batch that fails to write is retried with a growing pause before it is dropped
+	viewFlushAttempts = 3
+	viewFlushBackoff  = 500 * time.Millisecond
+)
+
+// ViewIngestOptions sizes the view ingestion pipeline
+type ViewIngestOptions struct {
+	BufferSize    int
+	Workers       int
+	BatchSize     int
+	FlushInterval time.Duration
+	DropPolicy    domain.ViewDropPolicy
+}
+
+// ViewIngestPipeline takes article views off the request path: views go into a
+// bounded channel, are deduplicated in memory and written by a pool of workers in
+// batches, each batch being one multi-row INSERT and one view_count UPDATE.
+type ViewIngestPipeline struct {
+	viewRepo    domain.ViewTrackingRepository
+	articleRepo domain.ArticleRepository
+	opts        ViewIngestOptions
+
+	queue chan domain.ArticleView
+	wg    sync.WaitGroup
+	// closeMu guards sends on queue against Stop closing it
+	closeMu sync.RWMutex
+	closed  bool
+
+	dedupMu sync.Mutex
+	seen    map[domain.ViewKey]time.Time
+	stopped chan struct{}
+
+	enqueued, deduplicated, dropped, orphaned atomic.Int64
+	written, failed, batches                  atomic.Int64
+
+	statusMu    sync.Mutex
+	lastFlushAt *time.Time
+	lastError   string
+}
+
+func NewViewIngestPipeline(viewRepo domain.ViewTrackingRepository, articleRepo domain.ArticleRepository, opts ViewIngestOptions) *ViewIngestPipeline {
+	if opts.BufferSize < 1 {
+		opts.BufferSize = 10000
+	}
+	if opts.Workers < 1 {
+		opts.Workers = 2
+	}
+	if opts.BatchSize < 1 {
+		opts.BatchSize = 500
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 2 * time.Second
+	}
+	switch opts.DropPolicy {
+	case domain.ViewDropNewest, domain.ViewDropOldest, domain.ViewBlock:
+	default:
+		opts.DropPolicy = domain.ViewDropNewest
+	}
+	return &ViewIngestPipeline{
+		viewRepo:    viewRepo,
+		articleRepo: articleRepo,
+		opts:        opts,
+		queue:       make(chan domain.ArticleView, opts.BufferSize),
+		seen:        make(map[domain.ViewKey]time.Time),
+		stopped:     make(chan struct{}),
+	}
+}
+
+func (p *ViewIngestPipeline) Start() {
+	log.Printf("Starting View Ingest Pipeline (%d workers, buffer %d, %s)...", p.opts.Workers, p.opts.BufferSize, p.opts.DropPolicy)
+
+	for i := 0; i < p.opts.Workers; i++ {
+		p.wg.Add(1)
+		go p.work()
+	}
+	go p.pruneLoop()
+}
+
+// Enqueue implements domain.ViewIngester
+func (p *ViewIngestPipeline) Enqueue(view domain.ArticleView) bool {
+	if p.isDuplicate(view) {
+		p.deduplicated.Add(1)
+		return true
+	}
+
+	p.closeMu.RLock()
+	defer p.closeMu.RUnlock()
+	if p.closed {
+		p.dropped.Add(1)
+		return false
+	}
+
+	select {
+	case p.queue <- view:
+		p.enqueued.Add(1)
+		return true
+	default:
+	}
+
+	switch p.opts.DropPolicy {
+	case domain.ViewDropOldest:
+		// Make room by discarding the oldest buffered view; a worker may have freed
+		// a slot meanwhile, so the second send is also non-blocking
+		select {
+		case old := <-p.queue:
+			p.forget(old)
+			p.dropped.Add(1)
+		default:
+		}
+		select {
+		case p.queue <- view:
+			p.enqueued.Add(1)
+			return true
+		default:
+		}
+	case domain.ViewBlock:
+		timer := time.NewTimer(viewBlockTimeout)
+		defer timer.Stop()
+		select {
+		case p.queue <- view:
+			p.enqueued.Add(1)
+			return true
+		case <-timer.C:
+		}
+	}
+
+	p.forget(view)
+	p.dropped.Add(1)
+	return false
+}
+
+// Stop stops accepting views and waits until the workers have flushed everything
+// buffered, or until ctx is done
+func (p *ViewIngestPipeline) Stop(ctx context.Context) error {
+	p.closeMu.Lock()
+	if p.closed {
+		p.closeMu.Unlock()
+		return nil
+	}
+	p.closed = true
+	close(p.queue)
+	close(p.stopped)
+	p.closeMu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		p.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		log.Println("ViewIngestPipeline: flushed and stopped")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Stats returns a snapshot of the pipeline counters
+func (p *ViewIngestPipeline) Stats() domain.ViewIngestStats {
+	p.statusMu.Lock()
+	lastFlushAt, lastError := p.lastFlushAt, p.lastError
+	p.statusMu.Unlock()
+
+	return domain.ViewIngestStats{
+		Workers:       p.opts.Workers,
+		DropPolicy:    p.opts.DropPolicy,
+		QueueLength:   len(p.queue),
+		QueueCapacity: cap(p.queue),
+		Enqueued:      p.enqueued.Load(),
+		Deduplicated:  p.deduplicated.Load(),
+		Dropped:       p.dropped.Load(),
+		Orphaned:      p.orphaned.Load(),
+		Written:       p.written.Load(),
+		Failed:        p.failed.Load(),
+		Batches:       p.batches.Load(),
+		LastFlushAt:   lastFlushAt,
+		LastError:     lastError,
+	}
+}
+
+// isDuplicate reports whether the visitor already viewed the article today, and
+// otherwise remembers this view
+func (p *ViewIngestPipeline) isDuplicate(view domain.ArticleView) bool {
+	key := domain.ViewKey{ArticleID: view.ArticleID, VisitorID: view.VisitorID}
+	now := time.Now()
+
+	p.dedupMu.Lock()
+	defer p.dedupMu.Unlock()
+	if expires, ok := p.seen[key]; ok && now.Before(expires) {
+		return true
+	}
+	if len(p.seen) < viewDedupMaxEntries {
+		// Visitor IDs rotate at UTC midnight, so the entry is useless after that
+		p.seen[key] = domain.VisitorDay(now).AddDate(0, 0, 1)
+	}
+	return false
+}
+
+// forget lets a dropped view be counted when the reader comes back
+func (p *ViewIngestPipeline) forget(view domain.ArticleView) {
+	p.dedupMu.Lock()
+	delete(p.seen, domain.ViewKey{ArticleID: view.ArticleID, VisitorID: view.VisitorID})
+	p.dedupMu.Unlock()
+}
+
+func (p *ViewIngestPipeline) pruneLoop() {
+	ticker := time.NewTicker(viewDedupPrune)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stopped:
+			return
+		case <-ticker.C:
+			now := time.Now()
+			p.dedupMu.Lock()
+			for key, expires := range p.seen {
+				if !now.Before(expires) {
+					delete(p.seen, key)
+				}
+			}
+			p.dedupMu.Unlock()
+		}
+	}
+}
+
+// work collects views until the batch is full or the flush interval passes
+func (p *ViewIngestPipeline) work() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]domain.ArticleView, 0, p.opts.BatchSize)
+	for {
+		select {
+		case view, ok := <-p.queue:
+			if !ok {
+				p.flush(batch)
+				return
+			}
+			batch = append(batch, view)
+			if len(batch) >= p.opts.BatchSize {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		case <-ticker.C:
+			if len(batch) > 0 {
+				p.flush(batch)
+				batch = batch[:0]
+			}
+		}
+	}
+}
+
+// flush writes a batch, retrying transient failures. A batch that still fails is
+// dropped and its readers forgotten, so they are counted when they come back.
+func (p *ViewIngestPipeline) flush(batch []domain.ArticleView) {
+	if len(batch) == 0 {
+		return
+	}
+	p.batches.Add(1)
+
+	var err error
+	for attempt := 1; attempt <= viewFlushAttempts; attempt++ {
+		if err = p.write(batch); err == nil {
+			return
+		}
+		if attempt < viewFlushAttempts {
+			log.Printf("ViewIngestPipeline: writing %d views failed (attempt %d), retrying: %v", len(batch), attempt, err)
+			time.Sleep(time.Duration(attempt) * viewFlushBackoff)
+		}
+	}
+	p.fail(batch, err)
+}
+
+// write drops views already stored in an earlier process lifetime and views of
+// articles deleted since they were tracked, inserts the rest and bumps the articles'
+// view_count by their human views in one statement
+func (p *ViewIngestPipeline) write(batch []domain.ArticleView) error {
+	keys := make([]domain.ViewKey, len(batch))
+	articleIDs := make([]uuid.UUID, 0, len(batch))
+	seen := make(map[uuid.UUID]bool)
+	for i, v := range batch {
+		keys[i] = domain.ViewKey{ArticleID: v.ArticleID, VisitorID: v.VisitorID}
+		if !seen[v.ArticleID] {
+			seen[v.ArticleID] = true
+			articleIDs = append(articleIDs, v.ArticleID)
+		}
+	}
+	// One deleted article would otherwise fail the whole batch on the foreign key
+	articles, err := p.articleRepo.ExistingIDs(articleIDs)
+	if err != nil {
+		return err
+	}
+	// Batches are roughly in arrival order; a batch spanning midnight is checked from
+	// the earlier day, which is harmless since visitor IDs differ between days
+	existing, err := p.viewRepo.RecentViewKeys(keys, domain.VisitorDay(batch[0].ViewedAt))
+	if err != nil {
+		return err
+	}
+
+	views := make([]domain.ArticleView, 0, len(batch))
+	counts := make(map[uuid.UUID]int)
+	var orphaned, repeats int64
+	for i, v := range batch {
+		if !articles[v.ArticleID] {
+			orphaned++
+			continue
+		}
+		if existing[keys[i]] {
+			repeats++
+			continue
+		}
+		views = append(views, v)
+		if v.Classification == domain.ViewHuman {
+			counts[v.ArticleID]++
+		}
+	}
+
+	if err := p.viewRepo.RecordViews(views); err != nil {
+		return err
+	}
+	if err := p.articleRepo.IncrementViewCounts(counts); err != nil {
+		// The views are stored; only the cached counters lag behind
+		log.Printf("ViewIngestPipeline: failed to update view counts: %v", err)
+	}
+	p.deduplicated.Add(repeats)
+	p.orphaned.Add(orphaned)
+	p.written.Add(int64(len(views)))
+
+	now := time.Now().UTC()
+	p.statusMu.Lock()
+	p.lastFlushAt = &now
+	p.statusMu.Unlock()
+	return nil
+}
+
+func (p *ViewIngestPipeline) fail(batch []domain.ArticleView, err error) {
+	for _, v := range batch {
+		p.forget(v)
+	}
+	p.failed.Add(int64(len(batch)))
+	log.Printf("ViewIngestPipeline: dropped %d views after %d attempts: %v", len(batch), viewFlushAttempts, err)
+	p.statusMu.Lock()
+	p.lastError = err.Error()
+	p.statusMu.Unlock()
+}
@@ -0,0 +1,59 @@
+package worker
+
+import (
+	"backend/internal/domain"
+	"log"
+	"time"
+)
+
+// ViewPrivacyWorker enforces the view data retention rules: it deletes visitor salts
+// of past days, so their hashes can no longer be reproduced, and clears raw IPs and
+// user agents older than view_ip_retention_days.
+type ViewPrivacyWorker struct {
+	viewRepo    domain.ViewTrackingRepository
+	salts       domain.VisitorSaltRepository
+	settingServ domain.SettingService
+}
+
+func NewViewPrivacyWorker(viewRepo domain.ViewTrackingRepository, salts domain.VisitorSaltRepository, settingServ domain.SettingService) *ViewPrivacyWorker {
+	return &ViewPrivacyWorker{viewRepo: viewRepo, salts: salts, settingServ: settingServ}
+}
+
+func (w *ViewPrivacyWorker) Start() {
+	log.Println("Starting View Privacy Worker...")
+
+	// Run immediately on start, then hourly so salts go soon after midnight
+	w.RunOnce()
+	ticker := time.NewTicker(time.Hour)
+	go func() {
+		for range ticker.C {
+			w.RunOnce()
+		}
+	}()
+}
+
+func (w *ViewPrivacyWorker) RunOnce() {
+	now := time.Now().UTC()
+	if err := w.salts.DeleteBefore(domain.VisitorDay(now)); err != nil {
+		log.Printf("ViewPrivacyWorker: failed to delete old salts: %v", err)
+	}
+
+	settings, err := w.settingServ.GetSettings()
+	if err != nil {
+		log.Printf("ViewPrivacyWorker: Failed to fetch settings: %v", err)
+		return
+	}
+	retentionDays := domain.SettingInt(settings, "view_ip_retention_days", domain.DefaultViewIPRetentionDays)
+	if retentionDays < 0 {
+		retentionDays = 0
+	}
+
+	cleared, err := w.viewRepo.AnonymizeBefore(now.AddDate(0, 0, -retentionDays))
+	if err != nil {
+		log.Printf("ViewPrivacyWorker: failed to anonymize views: %v", err)
+		return
+	}
+	if cleared > 0 {
+		log.Printf("ViewPrivacyWorker: cleared raw IPs and user agents of %d views older than %d days", cleared, retentionDays)
+	}
+}
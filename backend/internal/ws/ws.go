@@ -233,28 +233,31 @@ func (h *Hub) sendOnlineList(client *Client, userIDs []uuid.UUID) {
 	}
 }
 
-func (h *Hub) SendToUser(userID uuid.UUID, msgType string, payload interface{}) {
+// SendToUser queues a message on every socket of the user and reports whether at
+// least one of them accepted it
+func (h *Hub) SendToUser(userID uuid.UUID, msgType string, payload interface{}) bool {
 	data, err := json.Marshal(map[string]interface{}{
 		"type":    msgType,
 		"payload": payload,
 	})
 	if err != nil {
 		log.Printf("Error marshaling WS message: %v", err)
-		return
+		return false
 	}
 
 	h.mu.RLock()
 	defer h.mu.RUnlock()
 
-	if clients, ok := h.Clients[userID]; ok {
-		for _, client := range clients {
-			select {
-			case client.Send <- data:
-			default:
-				log.Printf("Failed to send message to user %s", userID)
-			}
+	sent := false
+	for _, client := range h.Clients[userID] {
+		select {
+		case client.Send <- data:
+			sent = true
+		default:
+			log.Printf("Failed to send message to user %s", userID)
 		}
 	}
+	return sent
 }
 
 func (h *Hub) BroadcastEvent(msgType string, payload interface{}) {
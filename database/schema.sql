@@ -79,6 +79,7 @@ CREATE TABLE articles (
 
     is_featured BOOLEAN DEFAULT false,
     allow_comment BOOLEAN DEFAULT true,
+    language VARCHAR(10) NOT NULL DEFAULT 'vi',
 
     published_at TIMESTAMP,
     created_at TIMESTAMP DEFAULT NOW(),
@@ -437,6 +438,488 @@ WHERE search_vector IS NULL;
 COMMENT ON COLUMN articles.search_vector IS 'Full-text search vector with weighted title (A), summary (B), and content (C)';
 COMMENT ON INDEX idx_articles_search_vector IS 'GIN index for fast full-text search on articles';
 
+-- =========================================
+-- KEYSET (CURSOR) PAGINATION
+-- =========================================
+-- Composite (sort key, id) indexes backing ?after=<cursor> listings
+
+CREATE INDEX IF NOT EXISTS idx_articles_created_keyset ON articles(created_at DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_articles_published_keyset ON articles((COALESCE(published_at, created_at)) DESC, id DESC);
+CREATE INDEX IF NOT EXISTS idx_comments_root_keyset ON comments(article_id, created_at DESC, id DESC) WHERE parent_id IS NULL AND is_deleted = false;
+CREATE INDEX IF NOT EXISTS idx_audit_logs_created_keyset ON audit_logs(created_at DESC, id DESC);
+
+-- =========================================
+-- ARTICLE QUERY FILTERS
+-- =========================================
+
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS language VARCHAR(10) NOT NULL DEFAULT 'vi';
+CREATE INDEX IF NOT EXISTS idx_articles_language ON articles(language);
+CREATE INDEX IF NOT EXISTS idx_articles_updated_at ON articles(updated_at DESC);
+CREATE INDEX IF NOT EXISTS idx_article_tags_tag_article ON article_tags(tag_id, article_id);
+
+-- =========================================
+-- COMMENT MODERATION
+-- =========================================
+-- PENDING | APPROVED | REJECTED | SPAM. Existing comments stay visible.
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS status VARCHAR(20) NOT NULL DEFAULT 'APPROVED';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS moderation_reason TEXT;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS moderated_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP;
+
+UPDATE comments SET status = 'SPAM' WHERE is_spam = true AND status = 'APPROVED';
+
+CREATE INDEX IF NOT EXISTS idx_comments_moderation_queue ON comments(status, created_at) WHERE is_deleted = false;
+
+-- =========================================
+-- COMMENT SPAM SCORING
+-- =========================================
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS spam_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS spam_reasons JSONB;
+
+-- Naive-Bayes token counts learned from moderator decisions ('__docs__' holds document totals)
+CREATE TABLE IF NOT EXISTS spam_tokens (
+    token VARCHAR(100) PRIMARY KEY,
+    spam_count BIGINT NOT NULL DEFAULT 0,
+    ham_count BIGINT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_spam_score ON comments(spam_score DESC) WHERE status = 'PENDING';
+CREATE INDEX IF NOT EXISTS idx_comments_user_created ON comments(user_id, created_at DESC);
+
+-- =========================================
+-- SAVED SEARCHES
+-- =========================================
+
+CREATE TABLE IF NOT EXISTS saved_searches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL,
+    query VARCHAR(200) NOT NULL,
+    category_id UUID REFERENCES categories(id) ON DELETE SET NULL,
+    frequency VARCHAR(20) NOT NULL DEFAULT 'INSTANT',
+    -- INSTANT | DAILY | NONE
+    last_run_at TIMESTAMP,
+    last_digest_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS saved_search_matches (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    saved_search_id UUID NOT NULL REFERENCES saved_searches(id) ON DELETE CASCADE,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    matched_at TIMESTAMP DEFAULT NOW(),
+    delivered_at TIMESTAMP,
+    read_at TIMESTAMP,
+    CONSTRAINT idx_saved_search_match UNIQUE (saved_search_id, article_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_saved_searches_user_id ON saved_searches(user_id);
+CREATE INDEX IF NOT EXISTS idx_saved_search_matches_undelivered ON saved_search_matches(saved_search_id) WHERE delivered_at IS NULL;
+CREATE INDEX IF NOT EXISTS idx_articles_published_at ON articles(published_at DESC) WHERE status = 'PUBLISHED';
+
+-- =========================================
+-- COMMENT THREADS
+-- =========================================
+-- path is the dot-separated chain of ancestor IDs (hex, no dashes) ending with the
+-- comment's own; depth is 0 for top-level comments. reply_count counts visible
+-- (approved, not deleted) direct replies.
+
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS path TEXT NOT NULL DEFAULT '';
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS depth INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS reply_count INTEGER NOT NULL DEFAULT 0;
+
+WITH RECURSIVE tree AS (
+    SELECT id, replace(id::text, '-', '') AS path, 0 AS depth
+    FROM comments WHERE parent_id IS NULL
+    UNION ALL
+    SELECT c.id, t.path || '.' || replace(c.id::text, '-', ''), t.depth + 1
+    FROM comments c JOIN tree t ON c.parent_id = t.id
+)
+UPDATE comments SET path = tree.path, depth = tree.depth
+FROM tree WHERE comments.id = tree.id AND comments.path = '';
+
+UPDATE comments p SET reply_count = r.n
+FROM (
+    SELECT parent_id, COUNT(*) AS n FROM comments
+    WHERE parent_id IS NOT NULL AND status = 'APPROVED' AND is_deleted = false
+    GROUP BY parent_id
+) r
+WHERE p.id = r.parent_id AND p.reply_count <> r.n;
+
+CREATE INDEX IF NOT EXISTS idx_comments_thread ON comments(article_id, depth, path text_pattern_ops);
+CREATE INDEX IF NOT EXISTS idx_comments_parent_created ON comments(parent_id, created_at, id) WHERE is_deleted = false;
+
+-- =========================================
+-- COMMENT REACTIONS
+-- =========================================
+
+CREATE TABLE IF NOT EXISTS comment_reactions (
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    -- LIKE | LOVE | HAHA | WOW | SAD | ANGRY
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (comment_id, user_id, type)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_reactions_user ON comment_reactions(user_id, comment_id);
+
+-- Denormalized from comment_reactions; score is the Wilson lower bound of approving vs ANGRY reactions
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS reaction_counts JSONB;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_comments_root_top ON comments(article_id, score DESC, id DESC) WHERE parent_id IS NULL AND is_deleted = false;
+
+-- =========================================
+-- MENTIONS & NOTIFICATIONS
+-- =========================================
+
+CREATE TABLE IF NOT EXISTS comment_mentions (
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    name VARCHAR(255),
+    PRIMARY KEY (comment_id, user_id)
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    type VARCHAR(20) NOT NULL,
+    -- MENTION | REPLY
+    actor_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    excerpt VARCHAR(200),
+    created_at TIMESTAMP DEFAULT NOW(),
+    read_at TIMESTAMP,
+    emailed_at TIMESTAMP,
+    CONSTRAINT idx_notification_unique UNIQUE (user_id, comment_id, type)
+);
+
+-- One row per user who changed a default; no row means all in-app notifications, no email
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    mute_mentions BOOLEAN NOT NULL DEFAULT false,
+    mute_replies BOOLEAN NOT NULL DEFAULT false,
+    email_digest BOOLEAN NOT NULL DEFAULT false,
+    muted_until TIMESTAMP,
+    last_digest_at TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS notification_mutes (
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (user_id, article_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_comment_mentions_user ON comment_mentions(user_id);
+CREATE INDEX IF NOT EXISTS idx_notifications_user_created ON notifications(user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications(user_id) WHERE read_at IS NULL;
+
+-- =========================================
+-- CONTENT REPORTS
+-- =========================================
+
+-- target_id points at comments or articles depending on target_type, so it has no FK
+CREATE TABLE IF NOT EXISTS content_reports (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    target_type VARCHAR(20) NOT NULL,
+    -- COMMENT | ARTICLE
+    target_id UUID NOT NULL,
+    reporter_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    reason VARCHAR(30) NOT NULL,
+    details TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'OPEN',
+    -- OPEN | ACTIONED | DISMISSED
+    resolution_note TEXT,
+    resolved_by UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolved_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT idx_content_report_unique UNIQUE (target_type, target_id, reporter_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_content_reports_status ON content_reports(status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_content_reports_open_target ON content_reports(target_type, target_id) WHERE status = 'OPEN';
+CREATE INDEX IF NOT EXISTS idx_content_reports_reporter ON content_reports(reporter_id, created_at DESC);
+
+-- =========================================
+-- COMMENT REVISIONS
+-- =========================================
+
+-- Earlier comment texts, one row per edit; revision 1 is the original text
+CREATE TABLE IF NOT EXISTS comment_revisions (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    comment_id UUID NOT NULL REFERENCES comments(id) ON DELETE CASCADE,
+    revision INT NOT NULL,
+    content TEXT NOT NULL,
+    edited_by UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    edited_at TIMESTAMP NOT NULL,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT idx_comment_revision_number UNIQUE (comment_id, revision)
+);
+
+-- Past edits left no trace (updated_at also moves on moderation), so existing rows start unedited
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edited_at TIMESTAMP;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS edit_count INT NOT NULL DEFAULT 0;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS is_edited BOOLEAN NOT NULL DEFAULT false;
+
+-- =========================================
+-- COMMENT POLICIES
+-- =========================================
+
+-- {"mode": "OPEN|CLOSED|MEMBERS_ONLY|PRE_MODERATED", "roles": [...], "auto_close_days": N}
+-- NULL inherits: article -> category -> parent categories -> comment_default_policy setting
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS comment_policy JSONB;
+ALTER TABLE categories ADD COLUMN IF NOT EXISTS comment_policy JSONB;
+
+-- allow_comment = false was never enforced; carry it over as an explicit closed policy
+UPDATE articles SET comment_policy = '{"mode": "CLOSED"}'::jsonb
+WHERE allow_comment = false AND comment_policy IS NULL;
+
+-- =========================================
+-- GUEST COMMENTS
+-- =========================================
+
+-- Guest comments have no user row: user_id is NULL and guest_name is shown instead
+ALTER TABLE comments ALTER COLUMN user_id DROP NOT NULL;
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS guest_name VARCHAR(100);
+ALTER TABLE comments ADD COLUMN IF NOT EXISTS guest_email VARCHAR(255);
+
+ALTER TABLE comments DROP CONSTRAINT IF EXISTS chk_comments_author;
+ALTER TABLE comments ADD CONSTRAINT chk_comments_author CHECK (user_id IS NOT NULL OR guest_name IS NOT NULL);
+
+-- =========================================
+-- USER BLOCKS & MUTES
+-- =========================================
+
+CREATE TABLE IF NOT EXISTS user_blocks (
+    blocker_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    blocked_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(10) NOT NULL,
+    -- MUTE | BLOCK
+    created_at TIMESTAMP DEFAULT NOW(),
+    PRIMARY KEY (blocker_id, blocked_id, kind),
+    CONSTRAINT chk_user_blocks_self CHECK (blocker_id <> blocked_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_blocks_blocked ON user_blocks(blocked_id, kind);
+
+-- =========================================
+-- RATING BREAKDOWN
+-- =========================================
+
+-- Bayesian-adjusted rating_avg for ranking (see rating_prior_weight, default 5)
+ALTER TABLE articles ADD COLUMN IF NOT EXISTS rating_score DOUBLE PRECISION DEFAULT 0;
+CREATE INDEX IF NOT EXISTS idx_articles_rating_score ON articles(rating_score DESC, id DESC);
+
+-- Overall scores used to be truncated (4.67 -> 4); store them rounded
+UPDATE article_ratings
+SET score = ROUND((content_score + clarity_score + relevance_score) / 3.0)
+WHERE content_score > 0 AND clarity_score > 0 AND relevance_score > 0
+  AND score <> ROUND((content_score + clarity_score + relevance_score) / 3.0);
+
+-- rating_avg from the exact per-rating means, then rating_score against the site mean
+UPDATE articles a
+SET rating_avg = r.avg, rating_count = r.count
+FROM (
+    SELECT article_id,
+           AVG(CASE WHEN content_score > 0 AND clarity_score > 0 AND relevance_score > 0
+                    THEN (content_score + clarity_score + relevance_score) / 3.0 ELSE score END) AS avg,
+           COUNT(*) AS count
+    FROM article_ratings
+    GROUP BY article_id
+) r
+WHERE a.id = r.article_id;
+
+UPDATE articles
+SET rating_score = (5 * prior.mean + rating_count * rating_avg) / (5 + rating_count)
+FROM (
+    SELECT COALESCE(SUM(rating_avg * rating_count) / NULLIF(SUM(rating_count), 0), 3) AS mean
+    FROM articles WHERE rating_count > 0
+) prior
+WHERE rating_count > 0;
+
+-- =========================================
+-- REVIEWS
+-- =========================================
+
+-- Optional written review on a rating, moderated like comments (review_status empty without one)
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS review TEXT DEFAULT '';
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS review_status VARCHAR(20) DEFAULT '';
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS review_updated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS moderation_reason TEXT DEFAULT '';
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS moderated_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS moderated_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS spam_score DOUBLE PRECISION DEFAULT 0;
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS helpful_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS not_helpful_count INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS helpful_score DOUBLE PRECISION NOT NULL DEFAULT 0;
+
+CREATE INDEX IF NOT EXISTS idx_article_ratings_reviews ON article_ratings(article_id, helpful_score DESC, review_updated_at DESC)
+    WHERE review <> '' AND review_status = 'APPROVED';
+CREATE INDEX IF NOT EXISTS idx_article_ratings_review_queue ON article_ratings(review_status, review_updated_at)
+    WHERE review <> '';
+
+CREATE TABLE IF NOT EXISTS review_votes (
+    rating_id UUID NOT NULL REFERENCES article_ratings(id) ON DELETE CASCADE,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    helpful BOOLEAN NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (rating_id, user_id)
+);
+CREATE INDEX IF NOT EXISTS idx_review_votes_user ON review_votes(user_id);
+
+-- =========================================
+-- RATING INTEGRITY
+-- =========================================
+
+-- Voided ratings stay for the audit trail but are excluded from every aggregate
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS voided_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS voided_by UUID REFERENCES users(id) ON DELETE SET NULL;
+ALTER TABLE article_ratings ADD COLUMN IF NOT EXISTS void_reason TEXT DEFAULT '';
+
+-- Recent extreme ratings scanned by the anomaly detector
+CREATE INDEX IF NOT EXISTS idx_article_ratings_recent_extreme ON article_ratings(updated_at, article_id)
+    WHERE voided_at IS NULL AND score IN (1, 5);
+
+-- =========================================
+-- VIEW CLASSIFICATION
+-- =========================================
+
+-- Bot and suspicious views are stored with their classification but not counted
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS classification VARCHAR(20) NOT NULL DEFAULT 'HUMAN';
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS class_reason VARCHAR(100);
+CREATE INDEX IF NOT EXISTS idx_article_views_classification ON article_views(classification, viewed_at);
+
+-- =========================================
+-- VISITOR PRIVACY
+-- =========================================
+
+-- Daily salts for the hashed visitor IDs; past days are deleted by the privacy worker
+CREATE TABLE IF NOT EXISTS visitor_salts (
+    day DATE PRIMARY KEY,
+    salt BYTEA NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS visitor_id VARCHAR(64) NOT NULL DEFAULT '';
+
+-- Dedup and uniques use the visitor hash instead of the IP
+DROP INDEX IF EXISTS idx_article_views_dedup;
+CREATE INDEX IF NOT EXISTS idx_article_views_visitor ON article_views(article_id, visitor_id, viewed_at DESC);
+
+-- Anonymize historical rows: hash IP and user agent per day with a throwaway salt
+-- that is never stored, then drop raw IPs and user agents past the default 7 day retention
+WITH salt AS (SELECT gen_random_uuid()::text AS s)
+UPDATE article_views v
+SET visitor_id = md5(salt.s || CAST(v.viewed_at AS DATE) || COALESCE(host(v.ip_address), '') || '|' || COALESCE(v.user_agent, ''))
+FROM salt
+WHERE v.visitor_id = '';
+
+UPDATE article_views SET ip_address = NULL, user_agent = ''
+WHERE (ip_address IS NOT NULL OR user_agent <> '') AND viewed_at < NOW() - INTERVAL '7 days';
+
+-- =========================================
+-- ANALYTICS ROLLUPS
+-- =========================================
+
+-- Hourly and daily metrics per article, category, author and site (dimension_id is
+-- the nil UUID for SITE), rebuilt incrementally by the analytics rollup worker
+CREATE TABLE IF NOT EXISTS analytics_rollups (
+    granularity VARCHAR(10) NOT NULL,
+    bucket TIMESTAMP NOT NULL,
+    dimension VARCHAR(10) NOT NULL,
+    dimension_id UUID NOT NULL,
+    views BIGINT NOT NULL DEFAULT 0,
+    bot_views BIGINT NOT NULL DEFAULT 0,
+    uniques BIGINT NOT NULL DEFAULT 0,
+    session_seconds BIGINT NOT NULL DEFAULT 0,
+    comments BIGINT NOT NULL DEFAULT 0,
+    ratings BIGINT NOT NULL DEFAULT 0,
+    rating_sum BIGINT NOT NULL DEFAULT 0,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (granularity, bucket, dimension, dimension_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_analytics_rollups_dimension ON analytics_rollups(dimension, dimension_id, granularity, bucket);
+
+-- How far the worker has aggregated; an empty table makes it backfill from the oldest event
+CREATE TABLE IF NOT EXISTS rollup_states (
+    name VARCHAR(50) PRIMARY KEY,
+    rolled_until TIMESTAMP WITH TIME ZONE NOT NULL,
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX IF NOT EXISTS idx_comments_created_at ON comments(created_at);
+CREATE INDEX IF NOT EXISTS idx_article_ratings_created_at ON article_ratings(created_at);
+
+-- =========================================
+-- TRAFFIC SOURCES
+-- =========================================
+
+-- Normalized channel, UTM tags and parsed user agent of each view; only the
+-- referrer host is stored. Earlier views stay DIRECT / OTHER.
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS channel VARCHAR(20) NOT NULL DEFAULT 'DIRECT';
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS referrer_host VARCHAR(255);
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS utm_source VARCHAR(100);
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS utm_medium VARCHAR(100);
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS utm_campaign VARCHAR(100);
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS device_class VARCHAR(20) NOT NULL DEFAULT 'OTHER';
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS browser VARCHAR(50);
+ALTER TABLE article_views ADD COLUMN IF NOT EXISTS os VARCHAR(50);
+
+CREATE INDEX IF NOT EXISTS idx_article_views_campaign ON article_views(utm_campaign, viewed_at) WHERE utm_campaign <> '';
+
+-- =========================================
+-- READ ENGAGEMENT
+-- =========================================
+
+-- One row per reading session, merged from the page's periodic pings; pruned with
+-- raw views after view_raw_retention_days
+CREATE TABLE IF NOT EXISTS article_engagements (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    article_id UUID NOT NULL REFERENCES articles(id) ON DELETE CASCADE,
+    session_id VARCHAR(64) NOT NULL,
+    visitor_id VARCHAR(64) NOT NULL,
+    max_scroll INTEGER NOT NULL DEFAULT 0 CHECK (max_scroll BETWEEN 0 AND 100),
+    active_seconds INTEGER NOT NULL DEFAULT 0,
+    reached_end BOOLEAN NOT NULL DEFAULT FALSE,
+    pings INTEGER NOT NULL DEFAULT 1,
+    started_at TIMESTAMP NOT NULL,
+    updated_at TIMESTAMP
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_article_engagements_session ON article_engagements(article_id, session_id);
+CREATE INDEX IF NOT EXISTS idx_article_engagements_started_at ON article_engagements(started_at);
+
+-- =========================================
+-- AUTHOR ANALYTICS
+-- =========================================
+
+-- Engagement sessions rolled up with views so author dashboards can show read
+-- completion over time
+ALTER TABLE analytics_rollups ADD COLUMN IF NOT EXISTS reads BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE analytics_rollups ADD COLUMN IF NOT EXISTS completions BIGINT NOT NULL DEFAULT 0;
+ALTER TABLE analytics_rollups ADD COLUMN IF NOT EXISTS active_seconds BIGINT NOT NULL DEFAULT 0;
+
+-- =========================================
+-- UTC ANALYTICS TIMESTAMPS
+-- =========================================
+
+-- Engagement sessions and rollup buckets are instants; existing values were written
+-- in UTC
+ALTER TABLE article_engagements
+    ALTER COLUMN started_at TYPE TIMESTAMP WITH TIME ZONE USING started_at AT TIME ZONE 'UTC',
+    ALTER COLUMN updated_at TYPE TIMESTAMP WITH TIME ZONE USING updated_at AT TIME ZONE 'UTC';
+ALTER TABLE analytics_rollups
+    ALTER COLUMN bucket TYPE TIMESTAMP WITH TIME ZONE USING bucket AT TIME ZONE 'UTC';
+
 -- =========================================
 -- SEED DATA
 -- =========================================